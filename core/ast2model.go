@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // Ast2Block converts an ast node to a code block
@@ -25,12 +26,22 @@ func Ast2Block(node *node32, meta ScriptMetaData) Block {
 func Ast2List(node *node32, meta ScriptMetaData, pipe Call) Call {
 	var arguments = []Argument{}
 	for _, argument := range nodeChildren(node) {
-		arguments = append(arguments, Ast2Argument(argument.up, meta))
+		arguments = append(arguments, Ast2Argument(argumentNode(argument), meta))
 	}
 
 	return NewCallWithFunction(meta, node, ListConstructor, arguments, pipe)
 }
 
+// argumentNode returns the node that holds the value of an argument,
+// parameters and keyword arguments are not wrapped in an argument node
+//
+func argumentNode(node *node32) *node32 {
+	if node.pegRule == ruleParameter || node.pegRule == ruleNamedArgument {
+		return node
+	}
+	return node.up
+}
+
 // Ast2Call converts an ast node to a function call
 //
 func Ast2Call(node *node32, meta ScriptMetaData) Call {
@@ -60,8 +71,8 @@ func Ast2Call(node *node32, meta ScriptMetaData) Call {
 			}
 
 		} else {
-			if argument.pegRule == ruleArgument {
-				arguments = append(arguments, Ast2Argument(argument.up, meta))
+			if argument.pegRule == ruleArgument || argument.pegRule == ruleParameter || argument.pegRule == ruleNamedArgument {
+				arguments = append(arguments, Ast2Argument(argumentNode(argument), meta))
 			} else if argument.pegRule == ruleCOLON {
 				// convert identifier : value => set identifier value
 				//
@@ -161,7 +172,18 @@ func Ast2StringLiteral(node *node32, meta ScriptMetaData, driver *ast2StringDriv
 						blocks = make([]*blockAtPositionInString, 0, 0)
 					}
 
-					blocks = append(blocks, &blockAtPositionInString{at: sb.Len(), block: block})
+					// a block can end with a format like in \{$price:%.2f}
+					//
+					format := ""
+					for part := cursor.up; part != nil; part = part.next {
+						if part.pegRule == ruleFormatSpec {
+							format = nodeText(part, content)[1:]
+						}
+					}
+
+					// blocks are positioned by character, not by byte
+					//
+					blocks = append(blocks, &blockAtPositionInString{at: utf8.RuneCountInString(sb.String()), block: block, format: format})
 
 				} else {
 					panic("string parsing failed while escaping")
@@ -200,6 +222,18 @@ func Ast2Argument(node *node32, meta ScriptMetaData) Argument {
 
 		return NewArgumentWithDots(meta, begin, end, NewNameSpacedIdentifier(parts))
 
+	case ruleParameter:
+		return NewArgument(meta, node, NewIdentifier(nodeText(node, meta.Content())))
+
+	case ruleNamedArgument:
+		// convert identifier = value => keyword argument
+		//
+		keyword := node.up
+		value := Ast2Argument(keyword.next.next.up, meta)
+		return NewKeywordArgument(meta, node, nodeText(keyword, meta.Content()), value.Value())
+	case ruleSpreadArgument:
+		value := Ast2Argument(node.up.next.up, meta)
+		return NewSpreadArgument(meta, node, value.Value())
 	case ruleStringLiteral:
 		return NewArgument(meta, node, Ast2StringLiteral(node, meta, stringDriver))
 	case ruleLongStringLiteral:
@@ -212,6 +246,12 @@ func Ast2Argument(node *node32, meta ScriptMetaData) Argument {
 		i, err := strconv.ParseInt(txt, 10, 64)
 		if err != nil {
 
+			// integers that do not fit in 64 bits become big integers
+			//
+			if big, err := NewIntegerFromString(txt); err == nil {
+				return NewArgument(meta, node, big)
+			}
+
 			// then try parsing as float
 			//
 			f, err := strconv.ParseFloat(txt, 64)
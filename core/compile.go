@@ -0,0 +1,402 @@
+package elmo
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// opcode denotes the kind of a vm instruction
+//
+type opcode uint8
+
+const (
+	// opLoad resolves a single identifier as function, using
+	// a local slot when possible
+	opLoad opcode = iota
+
+	// opLoadPath resolves a dotted identifier as function
+	opLoadPath
+
+	// opLoadDynamic evaluates a call to determine the function
+	opLoadDynamic
+
+	// opLoadString resolves a string (with blocks) as function
+	opLoadString
+
+	// opLoadConst uses a literal value as function
+	opLoadConst
+
+	// opInvoke calls the loaded function with the arguments of a call
+	opInvoke
+
+	// opBuiltin calls a go function that does not need to be resolved
+	opBuiltin
+
+	// opAssign assigns a value to a single variable. Assignments written
+	// as 'name: value' do not load a function, other assignments only
+	// assign when the loaded function is set or let and invoke it otherwise
+	opAssign
+
+	// opStatement ends a statement within a block
+	opStatement
+)
+
+// instruction is a single vm instruction. Its operand is an index into
+// one of the tables of a program
+//
+type instruction struct {
+	op      opcode
+	operand int
+}
+
+// program is the compiled form of a block or a call
+//
+type program struct {
+	code []instruction
+
+	// names of identifiers that are loaded using opLoad
+	names []string
+
+	// identifiers that are loaded using opLoadPath
+	paths []IdentifierValue
+
+	// literal values loaded with opLoadConst or opLoadString
+	constants []Value
+
+	// calls used by opLoadDynamic, opInvoke and opBuiltin
+	calls []*call
+
+	// compiled arguments of calls, by index of the call
+	arguments [][]Argument
+
+	// assignments done by opAssign
+	assignments []assignment
+
+	// cache of slot indexes (*resolvedSlots) for the last used frame layout
+	slotCache atomic.Value
+}
+
+// assignment refers to the call of an assignment and the name
+// of the assigned variable within the names of a program
+//
+type assignment struct {
+	call int
+	name int
+
+	// assignments written as 'name: value' always assign, other
+	// assignments only when set or let is loaded
+	syntax bool
+}
+
+// resolvedSlots maps the names of a program to the slots of a frame layout
+//
+type resolvedSlots struct {
+	layout *slotLayout
+	slotOf []int
+}
+
+type compiler struct {
+	program *program
+}
+
+func (compiler *compiler) emit(op opcode, operand int) {
+	compiler.program.code = append(compiler.program.code, instruction{op: op, operand: operand})
+}
+
+func (compiler *compiler) addCall(c *call) int {
+	compiler.program.calls = append(compiler.program.calls, c)
+	compiler.program.arguments = append(compiler.program.arguments, compileArguments(c.arguments))
+	return len(compiler.program.calls) - 1
+}
+
+// compileArguments replaces arguments that read a variable ($name) by
+// arguments that read local variables from their slot. Other arguments
+// are used as they are, calls used as argument are compiled when run
+//
+func compileArguments(arguments []Argument) []Argument {
+	var compiled []Argument
+	for i, a := range arguments {
+		plain, isPlain := a.(*argument)
+		if !isPlain {
+			continue
+		}
+		reference, isCall := plain.value.(*call)
+		if !isCall || !isVariableReference(reference) {
+			continue
+		}
+		if compiled == nil {
+			compiled = append([]Argument{}, arguments...)
+		}
+		compiled[i] = &argument{astNode: plain.astNode, value: &variable{call: reference, name: reference.firstArgument.String()}}
+	}
+
+	if compiled == nil {
+		return arguments
+	}
+	return compiled
+}
+
+// assignedVariable returns the name of the variable a call assigns when
+// it is an assignment of a single value to a single variable
+//
+func assignedVariable(c *call) (string, bool) {
+	if len(c.arguments) != 2 || (c.firstArgument.String() != "set" && c.firstArgument.String() != "let") {
+		return "", false
+	}
+	target, isPlain := c.arguments[0].(*argument)
+	if !isPlain || target.Type() != TypeIdentifier || len(target.value.(*identifier).value) != 1 {
+		return "", false
+	}
+	return target.String(), true
+}
+
+// isAssignmentSyntax checks if a call is written as 'name: value'
+//
+func isAssignmentSyntax(c *call) bool {
+	set, isPlain := c.firstArgument.(*argument)
+	return isPlain && set.node != nil && set.node.pegRule == ruleCOLON
+}
+
+// isVariableReference checks if a call only reads a variable, like $name does
+//
+func isVariableReference(c *call) bool {
+	if c.function != nil || c.pipe != nil || len(c.arguments) > 0 || c.firstArgument.Type() != TypeIdentifier {
+		return false
+	}
+	return len(c.firstArgument.Value().(*identifier).value) == 1
+}
+
+// variable is a compiled variable reference. When run within a function frame
+// that stores the variable in a slot, the slot is read directly. All other
+// cases, like calling a function stored in a variable, are left to the call
+//
+type variable struct {
+	*call
+	name string
+
+	// cache of the slot index (*resolvedSlots) for the last used frame layout
+	slotCache atomic.Value
+}
+
+func (variable *variable) slotIn(layout *slotLayout) int {
+	if cached, ok := variable.slotCache.Load().(*resolvedSlots); ok && cached.layout == layout {
+		return cached.slotOf[0]
+	}
+
+	slot, found := layout.index[variable.name]
+	if !found {
+		slot = -1
+	}
+	variable.slotCache.Store(&resolvedSlots{layout: layout, slotOf: []int{slot}})
+	return slot
+}
+
+func (variable *variable) Run(context RunContext, arguments []Argument) Value {
+	if frame, isFrame := context.(*runContext); isFrame && frame.layout != nil && len(arguments) == 0 {
+		if slot := variable.slotIn(frame.layout); slot >= 0 {
+			value := frame.slot(slot)
+			if value != nil && value.Type() != TypeGoFunction && value.Type() != TypeError {
+				return value
+			}
+		}
+	}
+	return variable.call.Run(context, arguments)
+}
+
+func (compiler *compiler) addName(name string) int {
+	compiler.program.names = append(compiler.program.names, name)
+	return len(compiler.program.names) - 1
+}
+
+func (compiler *compiler) addConstant(value Value) int {
+	compiler.program.constants = append(compiler.program.constants, value)
+	return len(compiler.program.constants) - 1
+}
+
+func (compiler *compiler) compileCall(call *call) {
+
+	index := compiler.addCall(call)
+
+	if call.function != nil {
+		compiler.emit(opBuiltin, index)
+		return
+	}
+
+	switch call.firstArgument.Type() {
+	case TypeCall:
+		compiler.emit(opLoadDynamic, index)
+	case TypeIdentifier:
+		name := call.firstArgument.Value().(*identifier)
+		if len(name.value) == 1 {
+			target, isAssignment := assignedVariable(call)
+			syntax := isAssignment && isAssignmentSyntax(call)
+			if !syntax {
+				compiler.emit(opLoad, compiler.addName(name.value[0]))
+			}
+			if isAssignment {
+				compiler.program.assignments = append(compiler.program.assignments, assignment{call: index, name: compiler.addName(target), syntax: syntax})
+				compiler.emit(opAssign, len(compiler.program.assignments)-1)
+				return
+			}
+		} else {
+			compiler.program.paths = append(compiler.program.paths, name)
+			compiler.emit(opLoadPath, len(compiler.program.paths)-1)
+		}
+	case TypeString:
+		compiler.emit(opLoadString, compiler.addConstant(call.firstArgument.Value()))
+	default:
+		compiler.emit(opLoadConst, compiler.addConstant(call.firstArgument.Value()))
+	}
+
+	compiler.emit(opInvoke, index)
+}
+
+// compileBlock lowers all calls of a block into one sequence of instructions
+//
+func compileBlock(block *block) *program {
+	compiler := &compiler{program: &program{}}
+
+	for _, c := range block.calls {
+		compiler.compileCall(c.(*call))
+		compiler.emit(opStatement, 0)
+	}
+
+	return compiler.program
+}
+
+// compileSingleCall lowers a single call into instructions
+//
+func compileSingleCall(call *call) *program {
+	compiler := &compiler{program: &program{}}
+	compiler.compileCall(call)
+	return compiler.program
+}
+
+// slots returns for every name used by the program, the slot index
+// within given layout or -1 when the name is not a local
+//
+func (program *program) slots(layout *slotLayout) []int {
+
+	if cached, ok := program.slotCache.Load().(*resolvedSlots); ok && cached.layout == layout {
+		return cached.slotOf
+	}
+
+	slotOf := make([]int, len(program.names))
+	for i, name := range program.names {
+		if slot, found := layout.index[name]; found {
+			slotOf[i] = slot
+		} else {
+			slotOf[i] = -1
+		}
+	}
+
+	program.slotCache.Store(&resolvedSlots{layout: layout, slotOf: slotOf})
+	return slotOf
+}
+
+// compiledCode holds the lazily compiled program of a block. It is shared
+// between all copies of a block
+//
+type compiledCode struct {
+	once    sync.Once
+	program *program
+
+	layoutMutex sync.Mutex
+	layout      *slotLayout
+}
+
+func (code *compiledCode) compile(block *block) *program {
+	code.once.Do(func() {
+		code.program = compileBlock(block)
+	})
+	return code.program
+}
+
+// frameLayout returns the slot layout for a function using the compiled
+// block as body. The layout is reused as long as argument names do not change
+//
+func (code *compiledCode) frameLayout(block *block, argNames []string) *slotLayout {
+	code.layoutMutex.Lock()
+	defer code.layoutMutex.Unlock()
+
+	if code.layout == nil || !code.layout.hasArguments(argNames) {
+		code.layout = newSlotLayout(block, argNames)
+	}
+	return code.layout
+}
+
+// slotLayout describes which variables of a function frame are stored
+// in slots instead of by name
+//
+type slotLayout struct {
+	names     []string
+	index     map[string]int
+	arguments int
+}
+
+func (layout *slotLayout) hasArguments(argNames []string) bool {
+	if layout.arguments != len(argNames) {
+		return false
+	}
+	for i, name := range argNames {
+		if layout.names[i] != name {
+			return false
+		}
+	}
+	return true
+}
+
+func (layout *slotLayout) add(name string) {
+	if _, found := layout.index[name]; !found {
+		layout.index[name] = len(layout.names)
+		layout.names = append(layout.names, name)
+	}
+}
+
+// newSlotLayout determines the local variables of a function: its
+// arguments and all variables that are assigned in its body, including
+// the ones assigned within blocks of its body
+//
+func newSlotLayout(body Block, argNames []string) *slotLayout {
+	layout := &slotLayout{names: make([]string, 0, len(argNames)), index: make(map[string]int)}
+
+	for _, name := range argNames {
+		layout.add(name)
+	}
+	layout.arguments = len(layout.names)
+
+	layout.addAssigned(body)
+
+	return layout
+}
+
+// addAssigned adds all variables assigned using set or let within given block
+//
+func (layout *slotLayout) addAssigned(block Block) {
+	for _, c := range block.Calls() {
+		assignment, ok := c.(*call)
+		if !ok {
+			continue
+		}
+
+		for _, argument := range assignment.arguments {
+			if nested, isBlock := argument.Value().(Block); isBlock {
+				layout.addAssigned(nested)
+			}
+		}
+
+		if assignment.function != nil || assignment.firstArgument.Type() != TypeIdentifier {
+			continue
+		}
+
+		switch assignment.firstArgument.String() {
+		case "set", "let":
+			arguments := assignment.arguments
+			for i := 0; i < len(arguments)-1; i++ {
+				if arguments[i].Type() == TypeIdentifier && len(arguments[i].Value().(*identifier).value) == 1 {
+					layout.add(arguments[i].String())
+				}
+			}
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package elmo
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"sync"
+)
 
 type runContext struct {
 	properties map[string]Value
@@ -10,6 +14,29 @@ type runContext struct {
 	parent     RunContext
 	joined     RunContext
 	stopped    bool
+
+	// function frames created by the vm store their local
+	// variables in slots instead of in the properties map
+	layout *slotLayout
+	slots  []Value
+
+	// contexts used to construct dictionaries remember the order
+	// in which their variables are set
+	order *[]string
+
+	execution *execution
+	depth     int
+
+	// method is the method called on this, see bindMethod
+	method *boundMethod
+
+	// loads is the chain of scripts being loaded that led to the
+	// script run within this context, see loadChain
+	loads []string
+
+	// lock guards properties, slots and modules. It is shared by all
+	// contexts sharing these, so actors can safely use them concurrently
+	lock *sync.RWMutex
 }
 
 // RunContext provides a runtime environment for script execution
@@ -21,6 +48,8 @@ type RunContext interface {
 	SetNamed(value NamedValue)
 	SetThis(this DictionaryValue)
 	This() DictionaryValue
+	setBoundMethod(method *boundMethod)
+	boundMethod() *boundMethod
 	SetScriptName(this Value)
 	ScriptName() Value
 	Get(key string) (Value, bool)
@@ -33,13 +62,61 @@ type RunContext interface {
 	Stop()
 	isStopped() bool
 	Join(with RunContext) RunContext
+	detach() RunContext
+
+	// Context returns the Go context used to cancel execution
+	Context() context.Context
+	// SetContext sets the Go context of the execution this context takes part in
+	SetContext(ctx context.Context)
+	// Limits returns the budgets of the execution
+	Limits() Limits
+	// SetLimits sets the budgets of the execution this context takes part in
+	SetLimits(limits Limits)
+	// Policy returns the policy of a sandbox or nil when not running in a sandbox
+	Policy() *Policy
+
+	step() ErrorValue
+	enter(caller RunContext) ErrorValue
+	callDepth() int
+	loadChain() []string
 }
 
 func (runContext *runContext) Set(key string, value Value) {
+	runContext.lock.Lock()
+	defer runContext.lock.Unlock()
+
+	if runContext.layout != nil {
+		if slot, found := runContext.layout.index[key]; found {
+			runContext.slots[slot] = value
+			return
+		}
+	}
+	if runContext.order != nil {
+		if _, found := runContext.properties[key]; !found {
+			*runContext.order = append(*runContext.order, key)
+		}
+	}
 	runContext.properties[key] = value
 }
 
 func (runContext *runContext) Remove(key string) {
+	runContext.lock.Lock()
+	defer runContext.lock.Unlock()
+
+	if runContext.layout != nil {
+		if slot, found := runContext.layout.index[key]; found {
+			runContext.slots[slot] = nil
+			return
+		}
+	}
+	if _, found := runContext.properties[key]; found && runContext.order != nil {
+		for i, k := range *runContext.order {
+			if k == key {
+				*runContext.order = append((*runContext.order)[:i:i], (*runContext.order)[i+1:]...)
+				break
+			}
+		}
+	}
 	delete(runContext.properties, key)
 }
 
@@ -48,8 +125,10 @@ func (runContext *runContext) Mixin(value Value) Value {
 		return NewErrorValue(fmt.Sprintf("can only mix in dictionaries, not %s", value.String()))
 	}
 
-	for k, v := range value.Internal().(map[string]Value) {
-		runContext.Set(k, v)
+	dict := value.(DictionaryValue)
+	for _, k := range dict.KeyValues() {
+		v, _ := dict.ResolveKey(k)
+		runContext.Set(k.String(), v)
 	}
 
 	return value
@@ -60,15 +139,38 @@ func (runContext *runContext) SetNamed(value NamedValue) {
 }
 
 func (runContext *runContext) This() DictionaryValue {
+	runContext.lock.RLock()
+	defer runContext.lock.RUnlock()
+
 	return runContext.this
 }
 
 func (runContext *runContext) SetThis(this DictionaryValue) {
+	runContext.lock.Lock()
+	defer runContext.lock.Unlock()
+
 	runContext.this = this
 }
 
+func (runContext *runContext) boundMethod() *boundMethod {
+	runContext.lock.RLock()
+	defer runContext.lock.RUnlock()
+
+	return runContext.method
+}
+
+func (runContext *runContext) setBoundMethod(method *boundMethod) {
+	runContext.lock.Lock()
+	defer runContext.lock.Unlock()
+
+	runContext.method = method
+}
+
 func (runContext *runContext) ScriptName() Value {
+	runContext.lock.RLock()
 	name := runContext.scriptName
+	runContext.lock.RUnlock()
+
 	if name != nil {
 		return name
 	}
@@ -79,16 +181,24 @@ func (runContext *runContext) ScriptName() Value {
 }
 
 func (runContext *runContext) SetScriptName(scriptName Value) {
+	runContext.lock.Lock()
+	defer runContext.lock.Unlock()
+
 	runContext.scriptName = scriptName
 }
 
 func (runContext *runContext) RegisterModule(module Module) {
+	runContext.lock.Lock()
+	defer runContext.lock.Unlock()
+
 	runContext.modules[module.Name()] = module
 }
 
 func (runContext *runContext) Module(name string) (Module, bool) {
 
+	runContext.lock.RLock()
 	value, found := runContext.modules[name]
+	runContext.lock.RUnlock()
 
 	if found {
 		return value, true
@@ -101,9 +211,68 @@ func (runContext *runContext) Module(name string) (Module, bool) {
 	return nil, false
 }
 
+// local returns a variable stored in this context only
+//
+func (runContext *runContext) local(key string) (Value, bool) {
+	runContext.lock.RLock()
+	defer runContext.lock.RUnlock()
+
+	if runContext.layout != nil {
+		if slot, found := runContext.layout.index[key]; found && runContext.slots[slot] != nil {
+			return runContext.slots[slot], true
+		}
+	}
+
+	value, found := runContext.properties[key]
+	return value, found
+}
+
+// slot returns the value of a local variable of a function frame
+//
+func (runContext *runContext) slot(index int) Value {
+	runContext.lock.RLock()
+	defer runContext.lock.RUnlock()
+
+	return runContext.slots[index]
+}
+
+// getUnslotted looks up a variable that is known not to be stored in
+// one of the slots of this context
+//
+func (runContext *runContext) getUnslotted(key string) (Value, bool) {
+	runContext.lock.RLock()
+	value, found := runContext.properties[key]
+	runContext.lock.RUnlock()
+
+	if found {
+		return value, true
+	}
+
+	if runContext.joined != nil {
+		if value, found := runContext.joined.Get(key); found {
+			return value, true
+		}
+	}
+
+	if runContext.parent != nil {
+		return runContext.parent.Get(key)
+	}
+
+	return nil, false
+}
+
+// setSlot sets the value of a local variable of a function frame
+//
+func (runContext *runContext) setSlot(index int, value Value) {
+	runContext.lock.Lock()
+	defer runContext.lock.Unlock()
+
+	runContext.slots[index] = value
+}
+
 func (runContext *runContext) Get(key string) (Value, bool) {
 
-	if value, found := runContext.properties[key]; found {
+	if value, found := runContext.local(key); found {
 		return value, true
 	}
 
@@ -123,9 +292,18 @@ func (runContext *runContext) Get(key string) (Value, bool) {
 
 func (runContext *runContext) Keys() []string {
 	keys := []string{}
+
+	runContext.lock.RLock()
 	for k := range runContext.properties {
 		keys = append(keys, k)
 	}
+	for i, v := range runContext.slots {
+		if v != nil {
+			keys = append(keys, runContext.layout.names[i])
+		}
+	}
+	runContext.lock.RUnlock()
+
 	if runContext.parent != nil {
 		keys = append(keys, runContext.parent.Keys()...)
 	}
@@ -144,26 +322,155 @@ func (runContext *runContext) CreateSubContext() RunContext {
 	return NewRunContext(runContext)
 }
 
+// Mapping returns a copy of all variables stored in this context
+//
 func (runContext *runContext) Mapping() map[string]Value {
-	return runContext.properties
+	runContext.lock.RLock()
+	defer runContext.lock.RUnlock()
+
+	mapping := make(map[string]Value, len(runContext.properties)+len(runContext.slots))
+	for k, v := range runContext.properties {
+		mapping[k] = v
+	}
+	for i, v := range runContext.slots {
+		if v != nil {
+			mapping[runContext.layout.names[i]] = v
+		}
+	}
+	return mapping
+}
+
+// orderedMapping returns a copy of all variables stored in this context
+// together with their keys in the order in which they were set
+//
+func (runContext *runContext) orderedMapping() ([]string, map[string]Value) {
+	runContext.lock.RLock()
+	defer runContext.lock.RUnlock()
+
+	var keys []string
+	if runContext.order != nil {
+		keys = append(keys, *runContext.order...)
+	}
+
+	mapping := make(map[string]Value, len(runContext.properties))
+	for k, v := range runContext.properties {
+		mapping[k] = v
+	}
+	return keys, mapping
 }
 
 func (runContext *runContext) Stop() {
+	runContext.lock.Lock()
+	defer runContext.lock.Unlock()
+
 	runContext.stopped = true
 }
 
 func (runContext *runContext) isStopped() bool {
+	runContext.lock.RLock()
+	defer runContext.lock.RUnlock()
+
 	return runContext.stopped
 }
 
+func (runContext *runContext) Context() context.Context {
+	return runContext.execution.current().ctx
+}
+
+func (runContext *runContext) SetContext(ctx context.Context) {
+	runContext.execution.change(func(settings *executionSettings) {
+		settings.ctx = ctx
+		settings.done = ctx.Done()
+	})
+}
+
+func (runContext *runContext) Limits() Limits {
+	return runContext.execution.current().limits
+}
+
+func (runContext *runContext) SetLimits(limits Limits) {
+	runContext.execution.change(func(settings *executionSettings) {
+		settings.limits = limits
+	})
+}
+
+func (runContext *runContext) Policy() *Policy {
+	return runContext.execution.policy
+}
+
+// step is called before every call to check for cancellation and
+// to keep track of the number of executed steps
+//
+func (runContext *runContext) step() ErrorValue {
+	return runContext.execution.step()
+}
+
+// enter marks a context as the frame of a function called from given
+// context and checks the recursion depth
+//
+func (runContext *runContext) enter(caller RunContext) ErrorValue {
+	runContext.depth = caller.callDepth() + 1
+	return runContext.execution.checkDepth(runContext.depth)
+}
+
+func (runContext *runContext) callDepth() int {
+	return runContext.depth
+}
+
+// loadChain returns the chain of scripts being loaded that led to the script
+// this context belongs to
+//
+func (runContext *runContext) loadChain() []string {
+	if runContext.loads != nil {
+		return runContext.loads
+	}
+	if runContext.parent != nil {
+		return runContext.parent.loadChain()
+	}
+	return nil
+}
+
 func (rc *runContext) Join(with RunContext) RunContext {
-	copy := &runContext{parent: rc.parent, properties: rc.properties, this: rc.this, scriptName: rc.scriptName, modules: rc.modules}
+	rc.lock.RLock()
+	defer rc.lock.RUnlock()
+
+	copy := &runContext{parent: rc.parent, properties: rc.properties, this: rc.this, scriptName: rc.scriptName, modules: rc.modules, layout: rc.layout, slots: rc.slots, order: rc.order, execution: rc.execution, depth: rc.depth, method: rc.method, loads: rc.loads, lock: rc.lock}
 	copy.joined = with
 	return copy
 }
 
+// detach creates a context that shares all variables with the original context
+// but that can be stopped without stopping the original context
+//
+func (rc *runContext) detach() RunContext {
+	rc.lock.RLock()
+	defer rc.lock.RUnlock()
+
+	return &runContext{parent: rc.parent, properties: rc.properties, this: rc.this, scriptName: rc.scriptName, modules: rc.modules, joined: rc.joined, layout: rc.layout, slots: rc.slots, order: rc.order, execution: rc.execution, depth: rc.depth, method: rc.method, loads: rc.loads, lock: rc.lock}
+}
+
 // NewRunContext constructs a new run context
 //
 func NewRunContext(parent RunContext) RunContext {
-	return &runContext{parent: parent, properties: make(map[string]Value), this: nil, scriptName: nil, modules: make(map[string]Module)}
+	rc := &runContext{parent: parent, properties: make(map[string]Value), this: nil, scriptName: nil, modules: make(map[string]Module), execution: executionOf(parent), lock: &sync.RWMutex{}}
+	if parent != nil {
+		rc.depth = parent.callDepth()
+	}
+	return rc
+}
+
+// newOrderedRunContext constructs a new run context that remembers the order
+// in which its variables are set
+//
+func newOrderedRunContext(parent RunContext) *runContext {
+	rc := NewRunContext(parent).(*runContext)
+	rc.order = &[]string{}
+	return rc
+}
+
+// newFrameContext constructs a run context for a function call that stores
+// the local variables denoted by given layout in slots
+//
+func newFrameContext(parent RunContext, layout *slotLayout) RunContext {
+	return &runContext{parent: parent, properties: make(map[string]Value), modules: make(map[string]Module), layout: layout, slots: make([]Value, len(layout.names)), execution: executionOf(parent), lock: &sync.RWMutex{}}
 }
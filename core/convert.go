@@ -88,6 +88,8 @@ func convertValueToInterface(converted map[uuid.UUID]bool, in Value, ignore map[
 		return convertDictionaryToMap(converted, in.(DictionaryValue), ignore)
 	case TypeList:
 		return convertListToArray(converted, in.(ListValue), ignore)
+	case TypeSet:
+		return convertListToArray(converted, NewListValue(in.(SetValue).List()), ignore)
 	case TypeString:
 		return string(in.Internal().([]rune))
 	default:
@@ -122,11 +124,11 @@ func convertDictionaryToMap(converted map[uuid.UUID]bool, in DictionaryValue, ig
 
 	mapping := make(map[string]interface{})
 
-	for _, key := range in.Keys() {
+	for _, key := range in.KeyValues() {
 
-		value, _ := in.Resolve(key)
+		value, _ := in.ResolveKey(key)
 		if _, found := ignore[value.Type()]; !found {
-			mapping[key] = convertValueToInterface(converted, value, ignore)
+			mapping[key.String()] = convertValueToInterface(converted, value, ignore)
 		}
 
 	}
@@ -158,7 +160,16 @@ func ConvertDictionaryToFlatMap(in DictionaryValue) map[string]Value {
 
 func ConvertDictionaryToFlatDictionary(in DictionaryValue) DictionaryValue {
 
-	return NewDictionaryValue(nil, ConvertDictionaryToFlatMap(in))
+	tuples := flattenDictionary("", make(map[uuid.UUID]bool, 0), in, make([]*keyValue, 0, 0))
+
+	keys := make([]string, len(tuples))
+	mapping := make(map[string]Value, len(tuples))
+	for i, tuple := range tuples {
+		keys[i] = tuple.key
+		mapping[tuple.key] = tuple.value
+	}
+
+	return NewOrderedDictionaryValue(nil, keys, mapping)
 }
 
 func flattenList(pre string, visited map[uuid.UUID]bool, in ListValue, done []*keyValue) []*keyValue {
@@ -190,9 +201,10 @@ func flattenDictionary(pre string, visited map[uuid.UUID]bool, in DictionaryValu
 	visited[uuid] = true
 
 	result := done
-	for _, key := range in.Keys() {
+	for _, dictKey := range in.KeyValues() {
 
-		value, _ := in.Resolve(key)
+		key := dictKey.String()
+		value, _ := in.ResolveKey(dictKey)
 		if value.Type() == TypeDictionary {
 			result = flattenDictionary(prefix(pre, key), visited, value.(DictionaryValue), result)
 		} else if value.Type() == TypeList {
@@ -19,9 +19,12 @@ const (
 	rulePipedOutput
 	ruleEndOfLine
 	ruleArgument
+	ruleNamedArgument
+	ruleSpreadArgument
 	ruleFunctionCall
 	ruleBlock
 	ruleBlockWithoutSpacing
+	ruleFormatSpec
 	ruleList
 	ruleSpacing
 	ruleWhiteSpace
@@ -29,6 +32,7 @@ const (
 	ruleLineComment
 	ruleNewLine
 	ruleIdentifier
+	ruleParameter
 	ruleIdNondigit
 	ruleIdChar
 	ruleIdEnd
@@ -51,7 +55,9 @@ const (
 	ruleCOMMA
 	rulePCOMMA
 	ruleCOLON
+	ruleEQUAL
 	ruleDOT
+	ruleELLIPSIS
 	rulePIPE
 	ruleDOLLAR
 	ruleAMPERSAND
@@ -69,9 +75,12 @@ var rul3s = [...]string{
 	"PipedOutput",
 	"EndOfLine",
 	"Argument",
+	"NamedArgument",
+	"SpreadArgument",
 	"FunctionCall",
 	"Block",
 	"BlockWithoutSpacing",
+	"FormatSpec",
 	"List",
 	"Spacing",
 	"WhiteSpace",
@@ -79,6 +88,7 @@ var rul3s = [...]string{
 	"LineComment",
 	"NewLine",
 	"Identifier",
+	"Parameter",
 	"IdNondigit",
 	"IdChar",
 	"IdEnd",
@@ -101,7 +111,9 @@ var rul3s = [...]string{
 	"COMMA",
 	"PCOMMA",
 	"COLON",
+	"EQUAL",
 	"DOT",
+	"ELLIPSIS",
 	"PIPE",
 	"DOLLAR",
 	"AMPERSAND",
@@ -399,7 +411,7 @@ func (t *tokens32) Expand(index int) {
 type ElmoGrammar struct {
 	Buffer string
 	buffer []rune
-	rules  [43]func() bool
+	rules  [49]func() bool
 	Parse  func(rule ...int) error
 	Reset  func()
 	Pretty bool
@@ -567,7 +579,7 @@ func (p *ElmoGrammar) Init() {
 			position, tokenIndex, depth = position0, tokenIndex0, depth0
 			return false
 		},
-		/* 1 Line <- <(NewLine? Argument COLON? Argument? ((COMMA NewLine?)? Argument)* (PipedOutput / EndOfLine)?)> */
+		/* 1 Line <- <(NewLine? Argument (!FormatSpec COLON)? (NamedArgument / Parameter / Argument)? ((COMMA NewLine?)? (NamedArgument / Parameter / Argument))* (PipedOutput / EndOfLine)?)> */
 		func() bool {
 			position4, tokenIndex4, depth4 := position, tokenIndex, depth
 			{
@@ -588,6 +600,15 @@ func (p *ElmoGrammar) Init() {
 				}
 				{
 					position8, tokenIndex8, depth8 := position, tokenIndex, depth
+					{
+						position10, tokenIndex10, depth10 := position, tokenIndex, depth
+						if !_rules[ruleFormatSpec]() {
+							goto l10
+						}
+						goto l8
+					l10:
+						position, tokenIndex, depth = position10, tokenIndex10, depth10
+					}
 					if !_rules[ruleCOLON]() {
 						goto l8
 					}
@@ -597,65 +618,97 @@ func (p *ElmoGrammar) Init() {
 				}
 			l9:
 				{
-					position10, tokenIndex10, depth10 := position, tokenIndex, depth
-					if !_rules[ruleArgument]() {
-						goto l10
+					position11, tokenIndex11, depth11 := position, tokenIndex, depth
+					{
+						position13, tokenIndex13, depth13 := position, tokenIndex, depth
+						if !_rules[ruleNamedArgument]() {
+							goto l14
+						}
+						goto l13
+					l14:
+						position, tokenIndex, depth = position13, tokenIndex13, depth13
+						if !_rules[ruleParameter]() {
+							goto l15
+						}
+						goto l13
+					l15:
+						position, tokenIndex, depth = position13, tokenIndex13, depth13
+						if !_rules[ruleArgument]() {
+							goto l11
+						}
 					}
-					goto l11
-				l10:
-					position, tokenIndex, depth = position10, tokenIndex10, depth10
+				l13:
+					goto l12
+				l11:
+					position, tokenIndex, depth = position11, tokenIndex11, depth11
 				}
-			l11:
 			l12:
+			l16:
 				{
-					position13, tokenIndex13, depth13 := position, tokenIndex, depth
+					position17, tokenIndex17, depth17 := position, tokenIndex, depth
 					{
-						position14, tokenIndex14, depth14 := position, tokenIndex, depth
+						position18, tokenIndex18, depth18 := position, tokenIndex, depth
 						if !_rules[ruleCOMMA]() {
-							goto l14
+							goto l18
 						}
 						{
-							position16, tokenIndex16, depth16 := position, tokenIndex, depth
+							position20, tokenIndex20, depth20 := position, tokenIndex, depth
 							if !_rules[ruleNewLine]() {
-								goto l16
+								goto l20
 							}
-							goto l17
-						l16:
-							position, tokenIndex, depth = position16, tokenIndex16, depth16
+							goto l21
+						l20:
+							position, tokenIndex, depth = position20, tokenIndex20, depth20
 						}
-					l17:
-						goto l15
-					l14:
-						position, tokenIndex, depth = position14, tokenIndex14, depth14
+					l21:
+						goto l19
+					l18:
+						position, tokenIndex, depth = position18, tokenIndex18, depth18
 					}
-				l15:
-					if !_rules[ruleArgument]() {
-						goto l13
+				l19:
+					{
+						position22, tokenIndex22, depth22 := position, tokenIndex, depth
+						if !_rules[ruleNamedArgument]() {
+							goto l23
+						}
+						goto l22
+					l23:
+						position, tokenIndex, depth = position22, tokenIndex22, depth22
+						if !_rules[ruleParameter]() {
+							goto l24
+						}
+						goto l22
+					l24:
+						position, tokenIndex, depth = position22, tokenIndex22, depth22
+						if !_rules[ruleArgument]() {
+							goto l17
+						}
 					}
-					goto l12
-				l13:
-					position, tokenIndex, depth = position13, tokenIndex13, depth13
+				l22:
+					goto l16
+				l17:
+					position, tokenIndex, depth = position17, tokenIndex17, depth17
 				}
 				{
-					position18, tokenIndex18, depth18 := position, tokenIndex, depth
+					position25, tokenIndex25, depth25 := position, tokenIndex, depth
 					{
-						position20, tokenIndex20, depth20 := position, tokenIndex, depth
+						position27, tokenIndex27, depth27 := position, tokenIndex, depth
 						if !_rules[rulePipedOutput]() {
-							goto l21
+							goto l28
 						}
-						goto l20
-					l21:
-						position, tokenIndex, depth = position20, tokenIndex20, depth20
+						goto l27
+					l28:
+						position, tokenIndex, depth = position27, tokenIndex27, depth27
 						if !_rules[ruleEndOfLine]() {
-							goto l18
+							goto l25
 						}
 					}
-				l20:
-					goto l19
-				l18:
-					position, tokenIndex, depth = position18, tokenIndex18, depth18
+				l27:
+					goto l26
+				l25:
+					position, tokenIndex, depth = position25, tokenIndex25, depth25
 				}
-			l19:
+			l26:
 				depth--
 				add(ruleLine, position5)
 			}
@@ -666,1383 +719,1676 @@ func (p *ElmoGrammar) Init() {
 		},
 		/* 2 PipedOutput <- <(PIPE Line)> */
 		func() bool {
-			position22, tokenIndex22, depth22 := position, tokenIndex, depth
+			position29, tokenIndex29, depth29 := position, tokenIndex, depth
 			{
-				position23 := position
+				position30 := position
 				depth++
 				if !_rules[rulePIPE]() {
-					goto l22
+					goto l29
 				}
 				if !_rules[ruleLine]() {
-					goto l22
+					goto l29
 				}
 				depth--
-				add(rulePipedOutput, position23)
+				add(rulePipedOutput, position30)
 			}
 			return true
-		l22:
-			position, tokenIndex, depth = position22, tokenIndex22, depth22
+		l29:
+			position, tokenIndex, depth = position29, tokenIndex29, depth29
 			return false
 		},
 		/* 3 EndOfLine <- <(PCOMMA / NewLine)> */
 		func() bool {
-			position24, tokenIndex24, depth24 := position, tokenIndex, depth
+			position31, tokenIndex31, depth31 := position, tokenIndex, depth
 			{
-				position25 := position
+				position32 := position
 				depth++
 				{
-					position26, tokenIndex26, depth26 := position, tokenIndex, depth
+					position33, tokenIndex33, depth33 := position, tokenIndex, depth
 					if !_rules[rulePCOMMA]() {
-						goto l27
+						goto l34
 					}
-					goto l26
-				l27:
-					position, tokenIndex, depth = position26, tokenIndex26, depth26
+					goto l33
+				l34:
+					position, tokenIndex, depth = position33, tokenIndex33, depth33
 					if !_rules[ruleNewLine]() {
-						goto l24
+						goto l31
 					}
 				}
-			l26:
+			l33:
 				depth--
-				add(ruleEndOfLine, position25)
+				add(ruleEndOfLine, position32)
 			}
 			return true
-		l24:
-			position, tokenIndex, depth = position24, tokenIndex24, depth24
+		l31:
+			position, tokenIndex, depth = position31, tokenIndex31, depth31
 			return false
 		},
-		/* 4 Argument <- <((Identifier (DOT Identifier)*) / StringLiteral / LongStringLiteral / Number / FunctionCall / Block / List)> */
+		/* 4 Argument <- <(SpreadArgument / (Identifier (DOT Identifier)*) / StringLiteral / LongStringLiteral / Number / FunctionCall / Block / List)> */
 		func() bool {
-			position28, tokenIndex28, depth28 := position, tokenIndex, depth
+			position35, tokenIndex35, depth35 := position, tokenIndex, depth
 			{
-				position29 := position
+				position36 := position
 				depth++
 				{
-					position30, tokenIndex30, depth30 := position, tokenIndex, depth
+					position37, tokenIndex37, depth37 := position, tokenIndex, depth
+					if !_rules[ruleSpreadArgument]() {
+						goto l38
+					}
+					goto l37
+				l38:
+					position, tokenIndex, depth = position37, tokenIndex37, depth37
 					if !_rules[ruleIdentifier]() {
-						goto l31
+						goto l39
 					}
-				l32:
+				l40:
 					{
-						position33, tokenIndex33, depth33 := position, tokenIndex, depth
+						position41, tokenIndex41, depth41 := position, tokenIndex, depth
 						if !_rules[ruleDOT]() {
-							goto l33
+							goto l41
 						}
 						if !_rules[ruleIdentifier]() {
-							goto l33
+							goto l41
 						}
-						goto l32
-					l33:
-						position, tokenIndex, depth = position33, tokenIndex33, depth33
+						goto l40
+					l41:
+						position, tokenIndex, depth = position41, tokenIndex41, depth41
 					}
-					goto l30
-				l31:
-					position, tokenIndex, depth = position30, tokenIndex30, depth30
+					goto l37
+				l39:
+					position, tokenIndex, depth = position37, tokenIndex37, depth37
 					if !_rules[ruleStringLiteral]() {
-						goto l34
+						goto l42
 					}
-					goto l30
-				l34:
-					position, tokenIndex, depth = position30, tokenIndex30, depth30
+					goto l37
+				l42:
+					position, tokenIndex, depth = position37, tokenIndex37, depth37
 					if !_rules[ruleLongStringLiteral]() {
-						goto l35
+						goto l43
 					}
-					goto l30
-				l35:
-					position, tokenIndex, depth = position30, tokenIndex30, depth30
+					goto l37
+				l43:
+					position, tokenIndex, depth = position37, tokenIndex37, depth37
 					if !_rules[ruleNumber]() {
-						goto l36
+						goto l44
 					}
-					goto l30
-				l36:
-					position, tokenIndex, depth = position30, tokenIndex30, depth30
+					goto l37
+				l44:
+					position, tokenIndex, depth = position37, tokenIndex37, depth37
 					if !_rules[ruleFunctionCall]() {
-						goto l37
+						goto l45
 					}
-					goto l30
-				l37:
-					position, tokenIndex, depth = position30, tokenIndex30, depth30
+					goto l37
+				l45:
+					position, tokenIndex, depth = position37, tokenIndex37, depth37
 					if !_rules[ruleBlock]() {
-						goto l38
+						goto l46
 					}
-					goto l30
-				l38:
-					position, tokenIndex, depth = position30, tokenIndex30, depth30
+					goto l37
+				l46:
+					position, tokenIndex, depth = position37, tokenIndex37, depth37
 					if !_rules[ruleList]() {
-						goto l28
+						goto l35
 					}
 				}
-			l30:
+			l37:
+				depth--
+				add(ruleArgument, position36)
+			}
+			return true
+		l35:
+			position, tokenIndex, depth = position35, tokenIndex35, depth35
+			return false
+		},
+		/* 5 NamedArgument <- <(Identifier EQUAL Argument)> */
+		func() bool {
+			position47, tokenIndex47, depth47 := position, tokenIndex, depth
+			{
+				position48 := position
+				depth++
+				if !_rules[ruleIdentifier]() {
+					goto l47
+				}
+				if !_rules[ruleEQUAL]() {
+					goto l47
+				}
+				if !_rules[ruleArgument]() {
+					goto l47
+				}
+				depth--
+				add(ruleNamedArgument, position48)
+			}
+			return true
+		l47:
+			position, tokenIndex, depth = position47, tokenIndex47, depth47
+			return false
+		},
+		/* 6 SpreadArgument <- <(ELLIPSIS Argument)> */
+		func() bool {
+			position49, tokenIndex49, depth49 := position, tokenIndex, depth
+			{
+				position50 := position
+				depth++
+				if !_rules[ruleELLIPSIS]() {
+					goto l49
+				}
+				if !_rules[ruleArgument]() {
+					goto l49
+				}
 				depth--
-				add(ruleArgument, position29)
+				add(ruleSpreadArgument, position50)
 			}
 			return true
-		l28:
-			position, tokenIndex, depth = position28, tokenIndex28, depth28
+		l49:
+			position, tokenIndex, depth = position49, tokenIndex49, depth49
 			return false
 		},
-		/* 5 FunctionCall <- <((LPAR Line RPAR) / ((DOLLAR / AMPERSAND) Argument (DOT Argument)*))> */
+		/* 7 FunctionCall <- <((LPAR Line RPAR) / ((DOLLAR / AMPERSAND) Argument (DOT Argument)*))> */
 		func() bool {
-			position39, tokenIndex39, depth39 := position, tokenIndex, depth
+			position51, tokenIndex51, depth51 := position, tokenIndex, depth
 			{
-				position40 := position
+				position52 := position
 				depth++
 				{
-					position41, tokenIndex41, depth41 := position, tokenIndex, depth
+					position53, tokenIndex53, depth53 := position, tokenIndex, depth
 					if !_rules[ruleLPAR]() {
-						goto l42
+						goto l54
 					}
 					if !_rules[ruleLine]() {
-						goto l42
+						goto l54
 					}
 					if !_rules[ruleRPAR]() {
-						goto l42
+						goto l54
 					}
-					goto l41
-				l42:
-					position, tokenIndex, depth = position41, tokenIndex41, depth41
+					goto l53
+				l54:
+					position, tokenIndex, depth = position53, tokenIndex53, depth53
 					{
-						position43, tokenIndex43, depth43 := position, tokenIndex, depth
+						position55, tokenIndex55, depth55 := position, tokenIndex, depth
 						if !_rules[ruleDOLLAR]() {
-							goto l44
+							goto l56
 						}
-						goto l43
-					l44:
-						position, tokenIndex, depth = position43, tokenIndex43, depth43
+						goto l55
+					l56:
+						position, tokenIndex, depth = position55, tokenIndex55, depth55
 						if !_rules[ruleAMPERSAND]() {
-							goto l39
+							goto l51
 						}
 					}
-				l43:
+				l55:
 					if !_rules[ruleArgument]() {
-						goto l39
+						goto l51
 					}
-				l45:
+				l57:
 					{
-						position46, tokenIndex46, depth46 := position, tokenIndex, depth
+						position58, tokenIndex58, depth58 := position, tokenIndex, depth
 						if !_rules[ruleDOT]() {
-							goto l46
+							goto l58
 						}
 						if !_rules[ruleArgument]() {
-							goto l46
+							goto l58
 						}
-						goto l45
-					l46:
-						position, tokenIndex, depth = position46, tokenIndex46, depth46
+						goto l57
+					l58:
+						position, tokenIndex, depth = position58, tokenIndex58, depth58
 					}
 				}
-			l41:
+			l53:
 				depth--
-				add(ruleFunctionCall, position40)
+				add(ruleFunctionCall, position52)
 			}
 			return true
-		l39:
-			position, tokenIndex, depth = position39, tokenIndex39, depth39
+		l51:
+			position, tokenIndex, depth = position51, tokenIndex51, depth51
 			return false
 		},
-		/* 6 Block <- <(LCURLY NewLine* Line* RCURLY)> */
+		/* 8 Block <- <(LCURLY NewLine* Line* RCURLY)> */
 		func() bool {
-			position47, tokenIndex47, depth47 := position, tokenIndex, depth
+			position59, tokenIndex59, depth59 := position, tokenIndex, depth
 			{
-				position48 := position
+				position60 := position
 				depth++
 				if !_rules[ruleLCURLY]() {
-					goto l47
+					goto l59
 				}
-			l49:
+			l61:
 				{
-					position50, tokenIndex50, depth50 := position, tokenIndex, depth
+					position62, tokenIndex62, depth62 := position, tokenIndex, depth
 					if !_rules[ruleNewLine]() {
-						goto l50
+						goto l62
 					}
-					goto l49
-				l50:
-					position, tokenIndex, depth = position50, tokenIndex50, depth50
+					goto l61
+				l62:
+					position, tokenIndex, depth = position62, tokenIndex62, depth62
 				}
-			l51:
+			l63:
 				{
-					position52, tokenIndex52, depth52 := position, tokenIndex, depth
+					position64, tokenIndex64, depth64 := position, tokenIndex, depth
 					if !_rules[ruleLine]() {
-						goto l52
+						goto l64
 					}
-					goto l51
-				l52:
-					position, tokenIndex, depth = position52, tokenIndex52, depth52
+					goto l63
+				l64:
+					position, tokenIndex, depth = position64, tokenIndex64, depth64
 				}
 				if !_rules[ruleRCURLY]() {
-					goto l47
+					goto l59
 				}
 				depth--
-				add(ruleBlock, position48)
+				add(ruleBlock, position60)
 			}
 			return true
-		l47:
-			position, tokenIndex, depth = position47, tokenIndex47, depth47
+		l59:
+			position, tokenIndex, depth = position59, tokenIndex59, depth59
 			return false
 		},
-		/* 7 BlockWithoutSpacing <- <(LCURLY NewLine* Line* '}')> */
+		/* 9 BlockWithoutSpacing <- <(LCURLY NewLine* Line* FormatSpec? '}')> */
 		func() bool {
-			position53, tokenIndex53, depth53 := position, tokenIndex, depth
+			position65, tokenIndex65, depth65 := position, tokenIndex, depth
 			{
-				position54 := position
+				position66 := position
 				depth++
 				if !_rules[ruleLCURLY]() {
-					goto l53
+					goto l65
 				}
-			l55:
+			l67:
 				{
-					position56, tokenIndex56, depth56 := position, tokenIndex, depth
+					position68, tokenIndex68, depth68 := position, tokenIndex, depth
 					if !_rules[ruleNewLine]() {
-						goto l56
+						goto l68
 					}
-					goto l55
-				l56:
-					position, tokenIndex, depth = position56, tokenIndex56, depth56
+					goto l67
+				l68:
+					position, tokenIndex, depth = position68, tokenIndex68, depth68
 				}
-			l57:
+			l69:
 				{
-					position58, tokenIndex58, depth58 := position, tokenIndex, depth
+					position70, tokenIndex70, depth70 := position, tokenIndex, depth
 					if !_rules[ruleLine]() {
-						goto l58
+						goto l70
+					}
+					goto l69
+				l70:
+					position, tokenIndex, depth = position70, tokenIndex70, depth70
+				}
+				{
+					position71, tokenIndex71, depth71 := position, tokenIndex, depth
+					if !_rules[ruleFormatSpec]() {
+						goto l71
 					}
-					goto l57
-				l58:
-					position, tokenIndex, depth = position58, tokenIndex58, depth58
+					goto l72
+				l71:
+					position, tokenIndex, depth = position71, tokenIndex71, depth71
 				}
+			l72:
 				if buffer[position] != rune('}') {
-					goto l53
+					goto l65
 				}
 				position++
 				depth--
-				add(ruleBlockWithoutSpacing, position54)
+				add(ruleBlockWithoutSpacing, position66)
 			}
 			return true
-		l53:
-			position, tokenIndex, depth = position53, tokenIndex53, depth53
+		l65:
+			position, tokenIndex, depth = position65, tokenIndex65, depth65
 			return false
 		},
-		/* 8 List <- <(LBRACKET NewLine* (Argument / NewLine)? (((COMMA NewLine?)? Argument) / NewLine)* RBRACKET)> */
+		/* 10 FormatSpec <- <(':' '%' (!('}' / '\n') .)*)> */
 		func() bool {
-			position59, tokenIndex59, depth59 := position, tokenIndex, depth
+			position73, tokenIndex73, depth73 := position, tokenIndex, depth
 			{
-				position60 := position
+				position74 := position
+				depth++
+				if buffer[position] != rune(':') {
+					goto l73
+				}
+				position++
+				if buffer[position] != rune('%') {
+					goto l73
+				}
+				position++
+			l75:
+				{
+					position76, tokenIndex76, depth76 := position, tokenIndex, depth
+					{
+						position77, tokenIndex77, depth77 := position, tokenIndex, depth
+						{
+							position78, tokenIndex78, depth78 := position, tokenIndex, depth
+							if buffer[position] != rune('}') {
+								goto l79
+							}
+							position++
+							goto l78
+						l79:
+							position, tokenIndex, depth = position78, tokenIndex78, depth78
+							if buffer[position] != rune('\n') {
+								goto l77
+							}
+							position++
+						}
+					l78:
+						goto l76
+					l77:
+						position, tokenIndex, depth = position77, tokenIndex77, depth77
+					}
+					if !matchDot() {
+						goto l76
+					}
+					goto l75
+				l76:
+					position, tokenIndex, depth = position76, tokenIndex76, depth76
+				}
+				depth--
+				add(ruleFormatSpec, position74)
+			}
+			return true
+		l73:
+			position, tokenIndex, depth = position73, tokenIndex73, depth73
+			return false
+		},
+		/* 11 List <- <(LBRACKET NewLine* (Parameter / Argument / NewLine)? (((COMMA NewLine?)? (Parameter / Argument)) / NewLine)* RBRACKET)> */
+		func() bool {
+			position80, tokenIndex80, depth80 := position, tokenIndex, depth
+			{
+				position81 := position
 				depth++
 				if !_rules[ruleLBRACKET]() {
-					goto l59
+					goto l80
 				}
-			l61:
+			l82:
 				{
-					position62, tokenIndex62, depth62 := position, tokenIndex, depth
+					position83, tokenIndex83, depth83 := position, tokenIndex, depth
 					if !_rules[ruleNewLine]() {
-						goto l62
+						goto l83
 					}
-					goto l61
-				l62:
-					position, tokenIndex, depth = position62, tokenIndex62, depth62
+					goto l82
+				l83:
+					position, tokenIndex, depth = position83, tokenIndex83, depth83
 				}
 				{
-					position63, tokenIndex63, depth63 := position, tokenIndex, depth
+					position84, tokenIndex84, depth84 := position, tokenIndex, depth
 					{
-						position65, tokenIndex65, depth65 := position, tokenIndex, depth
+						position86, tokenIndex86, depth86 := position, tokenIndex, depth
+						if !_rules[ruleParameter]() {
+							goto l87
+						}
+						goto l86
+					l87:
+						position, tokenIndex, depth = position86, tokenIndex86, depth86
 						if !_rules[ruleArgument]() {
-							goto l66
+							goto l88
 						}
-						goto l65
-					l66:
-						position, tokenIndex, depth = position65, tokenIndex65, depth65
+						goto l86
+					l88:
+						position, tokenIndex, depth = position86, tokenIndex86, depth86
 						if !_rules[ruleNewLine]() {
-							goto l63
+							goto l84
 						}
 					}
-				l65:
-					goto l64
-				l63:
-					position, tokenIndex, depth = position63, tokenIndex63, depth63
+				l86:
+					goto l85
+				l84:
+					position, tokenIndex, depth = position84, tokenIndex84, depth84
 				}
-			l64:
-			l67:
+			l85:
+			l89:
 				{
-					position68, tokenIndex68, depth68 := position, tokenIndex, depth
+					position90, tokenIndex90, depth90 := position, tokenIndex, depth
 					{
-						position69, tokenIndex69, depth69 := position, tokenIndex, depth
+						position91, tokenIndex91, depth91 := position, tokenIndex, depth
 						{
-							position71, tokenIndex71, depth71 := position, tokenIndex, depth
+							position93, tokenIndex93, depth93 := position, tokenIndex, depth
 							if !_rules[ruleCOMMA]() {
-								goto l71
+								goto l93
 							}
 							{
-								position73, tokenIndex73, depth73 := position, tokenIndex, depth
+								position95, tokenIndex95, depth95 := position, tokenIndex, depth
 								if !_rules[ruleNewLine]() {
-									goto l73
+									goto l95
 								}
-								goto l74
-							l73:
-								position, tokenIndex, depth = position73, tokenIndex73, depth73
+								goto l96
+							l95:
+								position, tokenIndex, depth = position95, tokenIndex95, depth95
 							}
-						l74:
-							goto l72
-						l71:
-							position, tokenIndex, depth = position71, tokenIndex71, depth71
+						l96:
+							goto l94
+						l93:
+							position, tokenIndex, depth = position93, tokenIndex93, depth93
 						}
-					l72:
-						if !_rules[ruleArgument]() {
-							goto l70
+					l94:
+						{
+							position97, tokenIndex97, depth97 := position, tokenIndex, depth
+							if !_rules[ruleParameter]() {
+								goto l98
+							}
+							goto l97
+						l98:
+							position, tokenIndex, depth = position97, tokenIndex97, depth97
+							if !_rules[ruleArgument]() {
+								goto l92
+							}
 						}
-						goto l69
-					l70:
-						position, tokenIndex, depth = position69, tokenIndex69, depth69
+					l97:
+						goto l91
+					l92:
+						position, tokenIndex, depth = position91, tokenIndex91, depth91
 						if !_rules[ruleNewLine]() {
-							goto l68
+							goto l90
 						}
 					}
-				l69:
-					goto l67
-				l68:
-					position, tokenIndex, depth = position68, tokenIndex68, depth68
+				l91:
+					goto l89
+				l90:
+					position, tokenIndex, depth = position90, tokenIndex90, depth90
 				}
 				if !_rules[ruleRBRACKET]() {
-					goto l59
+					goto l80
 				}
 				depth--
-				add(ruleList, position60)
+				add(ruleList, position81)
 			}
 			return true
-		l59:
-			position, tokenIndex, depth = position59, tokenIndex59, depth59
+		l80:
+			position, tokenIndex, depth = position80, tokenIndex80, depth80
 			return false
 		},
-		/* 9 Spacing <- <(WhiteSpace / LongComment / LineComment)*> */
+		/* 12 Spacing <- <(WhiteSpace / LongComment / LineComment)*> */
 		func() bool {
 			{
-				position76 := position
+				position100 := position
 				depth++
-			l77:
+			l101:
 				{
-					position78, tokenIndex78, depth78 := position, tokenIndex, depth
+					position102, tokenIndex102, depth102 := position, tokenIndex, depth
 					{
-						position79, tokenIndex79, depth79 := position, tokenIndex, depth
+						position103, tokenIndex103, depth103 := position, tokenIndex, depth
 						if !_rules[ruleWhiteSpace]() {
-							goto l80
+							goto l104
 						}
-						goto l79
-					l80:
-						position, tokenIndex, depth = position79, tokenIndex79, depth79
+						goto l103
+					l104:
+						position, tokenIndex, depth = position103, tokenIndex103, depth103
 						if !_rules[ruleLongComment]() {
-							goto l81
+							goto l105
 						}
-						goto l79
-					l81:
-						position, tokenIndex, depth = position79, tokenIndex79, depth79
+						goto l103
+					l105:
+						position, tokenIndex, depth = position103, tokenIndex103, depth103
 						if !_rules[ruleLineComment]() {
-							goto l78
+							goto l102
 						}
 					}
-				l79:
-					goto l77
-				l78:
-					position, tokenIndex, depth = position78, tokenIndex78, depth78
+				l103:
+					goto l101
+				l102:
+					position, tokenIndex, depth = position102, tokenIndex102, depth102
 				}
 				depth--
-				add(ruleSpacing, position76)
+				add(ruleSpacing, position100)
 			}
 			return true
 		},
-		/* 10 WhiteSpace <- <(' ' / '\t')> */
+		/* 13 WhiteSpace <- <(' ' / '\t')> */
 		func() bool {
-			position82, tokenIndex82, depth82 := position, tokenIndex, depth
+			position106, tokenIndex106, depth106 := position, tokenIndex, depth
 			{
-				position83 := position
+				position107 := position
 				depth++
 				{
-					position84, tokenIndex84, depth84 := position, tokenIndex, depth
+					position108, tokenIndex108, depth108 := position, tokenIndex, depth
 					if buffer[position] != rune(' ') {
-						goto l85
+						goto l109
 					}
 					position++
-					goto l84
-				l85:
-					position, tokenIndex, depth = position84, tokenIndex84, depth84
+					goto l108
+				l109:
+					position, tokenIndex, depth = position108, tokenIndex108, depth108
 					if buffer[position] != rune('\t') {
-						goto l82
+						goto l106
 					}
 					position++
 				}
-			l84:
+			l108:
 				depth--
-				add(ruleWhiteSpace, position83)
+				add(ruleWhiteSpace, position107)
 			}
 			return true
-		l82:
-			position, tokenIndex, depth = position82, tokenIndex82, depth82
+		l106:
+			position, tokenIndex, depth = position106, tokenIndex106, depth106
 			return false
 		},
-		/* 11 LongComment <- <('/' '*' (!('*' '/') .)* ('*' '/'))> */
+		/* 14 LongComment <- <('/' '*' (!('*' '/') .)* ('*' '/'))> */
 		func() bool {
-			position86, tokenIndex86, depth86 := position, tokenIndex, depth
+			position110, tokenIndex110, depth110 := position, tokenIndex, depth
 			{
-				position87 := position
+				position111 := position
 				depth++
 				if buffer[position] != rune('/') {
-					goto l86
+					goto l110
 				}
 				position++
 				if buffer[position] != rune('*') {
-					goto l86
+					goto l110
 				}
 				position++
-			l88:
+			l112:
 				{
-					position89, tokenIndex89, depth89 := position, tokenIndex, depth
+					position113, tokenIndex113, depth113 := position, tokenIndex, depth
 					{
-						position90, tokenIndex90, depth90 := position, tokenIndex, depth
+						position114, tokenIndex114, depth114 := position, tokenIndex, depth
 						if buffer[position] != rune('*') {
-							goto l90
+							goto l114
 						}
 						position++
 						if buffer[position] != rune('/') {
-							goto l90
+							goto l114
 						}
 						position++
-						goto l89
-					l90:
-						position, tokenIndex, depth = position90, tokenIndex90, depth90
+						goto l113
+					l114:
+						position, tokenIndex, depth = position114, tokenIndex114, depth114
 					}
 					if !matchDot() {
-						goto l89
+						goto l113
 					}
-					goto l88
-				l89:
-					position, tokenIndex, depth = position89, tokenIndex89, depth89
+					goto l112
+				l113:
+					position, tokenIndex, depth = position113, tokenIndex113, depth113
 				}
 				if buffer[position] != rune('*') {
-					goto l86
+					goto l110
 				}
 				position++
 				if buffer[position] != rune('/') {
-					goto l86
+					goto l110
 				}
 				position++
 				depth--
-				add(ruleLongComment, position87)
+				add(ruleLongComment, position111)
 			}
 			return true
-		l86:
-			position, tokenIndex, depth = position86, tokenIndex86, depth86
+		l110:
+			position, tokenIndex, depth = position110, tokenIndex110, depth110
 			return false
 		},
-		/* 12 LineComment <- <('#' (!'\n' .)*)> */
+		/* 15 LineComment <- <('#' (!'\n' .)*)> */
 		func() bool {
-			position91, tokenIndex91, depth91 := position, tokenIndex, depth
+			position115, tokenIndex115, depth115 := position, tokenIndex, depth
 			{
-				position92 := position
+				position116 := position
 				depth++
 				if buffer[position] != rune('#') {
-					goto l91
+					goto l115
 				}
 				position++
-			l93:
+			l117:
 				{
-					position94, tokenIndex94, depth94 := position, tokenIndex, depth
+					position118, tokenIndex118, depth118 := position, tokenIndex, depth
 					{
-						position95, tokenIndex95, depth95 := position, tokenIndex, depth
+						position119, tokenIndex119, depth119 := position, tokenIndex, depth
 						if buffer[position] != rune('\n') {
-							goto l95
+							goto l119
 						}
 						position++
-						goto l94
-					l95:
-						position, tokenIndex, depth = position95, tokenIndex95, depth95
+						goto l118
+					l119:
+						position, tokenIndex, depth = position119, tokenIndex119, depth119
 					}
 					if !matchDot() {
-						goto l94
+						goto l118
 					}
-					goto l93
-				l94:
-					position, tokenIndex, depth = position94, tokenIndex94, depth94
+					goto l117
+				l118:
+					position, tokenIndex, depth = position118, tokenIndex118, depth118
 				}
 				depth--
-				add(ruleLineComment, position92)
+				add(ruleLineComment, position116)
 			}
 			return true
-		l91:
-			position, tokenIndex, depth = position91, tokenIndex91, depth91
+		l115:
+			position, tokenIndex, depth = position115, tokenIndex115, depth115
 			return false
 		},
-		/* 13 NewLine <- <(('\n' / '\r') Spacing)+> */
+		/* 16 NewLine <- <(('\n' / '\r') Spacing)+> */
 		func() bool {
-			position96, tokenIndex96, depth96 := position, tokenIndex, depth
+			position120, tokenIndex120, depth120 := position, tokenIndex, depth
 			{
-				position97 := position
+				position121 := position
 				depth++
 				{
-					position100, tokenIndex100, depth100 := position, tokenIndex, depth
+					position124, tokenIndex124, depth124 := position, tokenIndex, depth
 					if buffer[position] != rune('\n') {
-						goto l101
+						goto l125
 					}
 					position++
-					goto l100
-				l101:
-					position, tokenIndex, depth = position100, tokenIndex100, depth100
+					goto l124
+				l125:
+					position, tokenIndex, depth = position124, tokenIndex124, depth124
 					if buffer[position] != rune('\r') {
-						goto l96
+						goto l120
 					}
 					position++
 				}
-			l100:
+			l124:
 				if !_rules[ruleSpacing]() {
-					goto l96
+					goto l120
 				}
-			l98:
+			l122:
 				{
-					position99, tokenIndex99, depth99 := position, tokenIndex, depth
+					position123, tokenIndex123, depth123 := position, tokenIndex, depth
 					{
-						position102, tokenIndex102, depth102 := position, tokenIndex, depth
+						position126, tokenIndex126, depth126 := position, tokenIndex, depth
 						if buffer[position] != rune('\n') {
-							goto l103
+							goto l127
 						}
 						position++
-						goto l102
-					l103:
-						position, tokenIndex, depth = position102, tokenIndex102, depth102
+						goto l126
+					l127:
+						position, tokenIndex, depth = position126, tokenIndex126, depth126
 						if buffer[position] != rune('\r') {
-							goto l99
+							goto l123
 						}
 						position++
 					}
-				l102:
+				l126:
 					if !_rules[ruleSpacing]() {
-						goto l99
+						goto l123
 					}
-					goto l98
-				l99:
-					position, tokenIndex, depth = position99, tokenIndex99, depth99
+					goto l122
+				l123:
+					position, tokenIndex, depth = position123, tokenIndex123, depth123
 				}
 				depth--
-				add(ruleNewLine, position97)
+				add(ruleNewLine, position121)
 			}
 			return true
-		l96:
-			position, tokenIndex, depth = position96, tokenIndex96, depth96
+		l120:
+			position, tokenIndex, depth = position120, tokenIndex120, depth120
 			return false
 		},
-		/* 14 Identifier <- <((IdNondigit IdChar* ((IdEnd? Spacing) / (IdEnd Spacing?))) / (IdEnd Spacing))> */
+		/* 17 Identifier <- <((IdNondigit IdChar* ((IdEnd? Spacing) / (IdEnd Spacing?))) / (IdEnd Spacing))> */
 		func() bool {
-			position104, tokenIndex104, depth104 := position, tokenIndex, depth
+			position128, tokenIndex128, depth128 := position, tokenIndex, depth
 			{
-				position105 := position
+				position129 := position
 				depth++
 				{
-					position106, tokenIndex106, depth106 := position, tokenIndex, depth
+					position130, tokenIndex130, depth130 := position, tokenIndex, depth
 					if !_rules[ruleIdNondigit]() {
-						goto l107
+						goto l131
 					}
-				l108:
+				l132:
 					{
-						position109, tokenIndex109, depth109 := position, tokenIndex, depth
+						position133, tokenIndex133, depth133 := position, tokenIndex, depth
 						if !_rules[ruleIdChar]() {
-							goto l109
+							goto l133
 						}
-						goto l108
-					l109:
-						position, tokenIndex, depth = position109, tokenIndex109, depth109
+						goto l132
+					l133:
+						position, tokenIndex, depth = position133, tokenIndex133, depth133
 					}
 					{
-						position110, tokenIndex110, depth110 := position, tokenIndex, depth
+						position134, tokenIndex134, depth134 := position, tokenIndex, depth
 						{
-							position112, tokenIndex112, depth112 := position, tokenIndex, depth
+							position136, tokenIndex136, depth136 := position, tokenIndex, depth
 							if !_rules[ruleIdEnd]() {
-								goto l112
+								goto l136
 							}
-							goto l113
-						l112:
-							position, tokenIndex, depth = position112, tokenIndex112, depth112
+							goto l137
+						l136:
+							position, tokenIndex, depth = position136, tokenIndex136, depth136
 						}
-					l113:
+					l137:
 						if !_rules[ruleSpacing]() {
-							goto l111
+							goto l135
 						}
-						goto l110
-					l111:
-						position, tokenIndex, depth = position110, tokenIndex110, depth110
+						goto l134
+					l135:
+						position, tokenIndex, depth = position134, tokenIndex134, depth134
 						if !_rules[ruleIdEnd]() {
-							goto l107
+							goto l131
 						}
 						{
-							position114, tokenIndex114, depth114 := position, tokenIndex, depth
+							position138, tokenIndex138, depth138 := position, tokenIndex, depth
 							if !_rules[ruleSpacing]() {
-								goto l114
+								goto l138
 							}
-							goto l115
-						l114:
-							position, tokenIndex, depth = position114, tokenIndex114, depth114
+							goto l139
+						l138:
+							position, tokenIndex, depth = position138, tokenIndex138, depth138
 						}
-					l115:
+					l139:
 					}
-				l110:
-					goto l106
-				l107:
-					position, tokenIndex, depth = position106, tokenIndex106, depth106
+				l134:
+					goto l130
+				l131:
+					position, tokenIndex, depth = position130, tokenIndex130, depth130
 					if !_rules[ruleIdEnd]() {
-						goto l104
+						goto l128
 					}
 					if !_rules[ruleSpacing]() {
-						goto l104
+						goto l128
 					}
 				}
-			l106:
+			l130:
 				depth--
-				add(ruleIdentifier, position105)
+				add(ruleIdentifier, position129)
 			}
 			return true
-		l104:
-			position, tokenIndex, depth = position104, tokenIndex104, depth104
+		l128:
+			position, tokenIndex, depth = position128, tokenIndex128, depth128
 			return false
 		},
-		/* 15 IdNondigit <- <([a-z] / [A-Z] / '_')> */
+		/* 18 Parameter <- <(IdNondigit IdChar* ((':' IdNondigit IdChar* ('.' '.' '.')?) / ('.' '.' '.')) ((IdEnd? Spacing) / (IdEnd Spacing?)))> */
 		func() bool {
-			position116, tokenIndex116, depth116 := position, tokenIndex, depth
+			position140, tokenIndex140, depth140 := position, tokenIndex, depth
 			{
-				position117 := position
+				position141 := position
 				depth++
+				if !_rules[ruleIdNondigit]() {
+					goto l140
+				}
+			l142:
 				{
-					position118, tokenIndex118, depth118 := position, tokenIndex, depth
+					position143, tokenIndex143, depth143 := position, tokenIndex, depth
+					if !_rules[ruleIdChar]() {
+						goto l143
+					}
+					goto l142
+				l143:
+					position, tokenIndex, depth = position143, tokenIndex143, depth143
+				}
+				{
+					position144, tokenIndex144, depth144 := position, tokenIndex, depth
+					if buffer[position] != rune(':') {
+						goto l145
+					}
+					position++
+					if !_rules[ruleIdNondigit]() {
+						goto l145
+					}
+				l146:
+					{
+						position147, tokenIndex147, depth147 := position, tokenIndex, depth
+						if !_rules[ruleIdChar]() {
+							goto l147
+						}
+						goto l146
+					l147:
+						position, tokenIndex, depth = position147, tokenIndex147, depth147
+					}
+					{
+						position148, tokenIndex148, depth148 := position, tokenIndex, depth
+						if buffer[position] != rune('.') {
+							goto l148
+						}
+						position++
+						if buffer[position] != rune('.') {
+							goto l148
+						}
+						position++
+						if buffer[position] != rune('.') {
+							goto l148
+						}
+						position++
+						goto l149
+					l148:
+						position, tokenIndex, depth = position148, tokenIndex148, depth148
+					}
+				l149:
+					goto l144
+				l145:
+					position, tokenIndex, depth = position144, tokenIndex144, depth144
+					if buffer[position] != rune('.') {
+						goto l140
+					}
+					position++
+					if buffer[position] != rune('.') {
+						goto l140
+					}
+					position++
+					if buffer[position] != rune('.') {
+						goto l140
+					}
+					position++
+				}
+			l144:
+				{
+					position150, tokenIndex150, depth150 := position, tokenIndex, depth
+					{
+						position152, tokenIndex152, depth152 := position, tokenIndex, depth
+						if !_rules[ruleIdEnd]() {
+							goto l152
+						}
+						goto l153
+					l152:
+						position, tokenIndex, depth = position152, tokenIndex152, depth152
+					}
+				l153:
+					if !_rules[ruleSpacing]() {
+						goto l151
+					}
+					goto l150
+				l151:
+					position, tokenIndex, depth = position150, tokenIndex150, depth150
+					if !_rules[ruleIdEnd]() {
+						goto l140
+					}
+					{
+						position154, tokenIndex154, depth154 := position, tokenIndex, depth
+						if !_rules[ruleSpacing]() {
+							goto l154
+						}
+						goto l155
+					l154:
+						position, tokenIndex, depth = position154, tokenIndex154, depth154
+					}
+				l155:
+				}
+			l150:
+				depth--
+				add(ruleParameter, position141)
+			}
+			return true
+		l140:
+			position, tokenIndex, depth = position140, tokenIndex140, depth140
+			return false
+		},
+		/* 19 IdNondigit <- <([a-z] / [A-Z] / '_')> */
+		func() bool {
+			position156, tokenIndex156, depth156 := position, tokenIndex, depth
+			{
+				position157 := position
+				depth++
+				{
+					position158, tokenIndex158, depth158 := position, tokenIndex, depth
 					if c := buffer[position]; c < rune('a') || c > rune('z') {
-						goto l119
+						goto l159
 					}
 					position++
-					goto l118
-				l119:
-					position, tokenIndex, depth = position118, tokenIndex118, depth118
+					goto l158
+				l159:
+					position, tokenIndex, depth = position158, tokenIndex158, depth158
 					if c := buffer[position]; c < rune('A') || c > rune('Z') {
-						goto l120
+						goto l160
 					}
 					position++
-					goto l118
-				l120:
-					position, tokenIndex, depth = position118, tokenIndex118, depth118
+					goto l158
+				l160:
+					position, tokenIndex, depth = position158, tokenIndex158, depth158
 					if buffer[position] != rune('_') {
-						goto l116
+						goto l156
 					}
 					position++
 				}
-			l118:
+			l158:
 				depth--
-				add(ruleIdNondigit, position117)
+				add(ruleIdNondigit, position157)
 			}
 			return true
-		l116:
-			position, tokenIndex, depth = position116, tokenIndex116, depth116
+		l156:
+			position, tokenIndex, depth = position156, tokenIndex156, depth156
 			return false
 		},
-		/* 16 IdChar <- <([a-z] / [A-Z] / [0-9] / '_')> */
+		/* 20 IdChar <- <([a-z] / [A-Z] / [0-9] / '_')> */
 		func() bool {
-			position121, tokenIndex121, depth121 := position, tokenIndex, depth
+			position161, tokenIndex161, depth161 := position, tokenIndex, depth
 			{
-				position122 := position
+				position162 := position
 				depth++
 				{
-					position123, tokenIndex123, depth123 := position, tokenIndex, depth
+					position163, tokenIndex163, depth163 := position, tokenIndex, depth
 					if c := buffer[position]; c < rune('a') || c > rune('z') {
-						goto l124
+						goto l164
 					}
 					position++
-					goto l123
-				l124:
-					position, tokenIndex, depth = position123, tokenIndex123, depth123
+					goto l163
+				l164:
+					position, tokenIndex, depth = position163, tokenIndex163, depth163
 					if c := buffer[position]; c < rune('A') || c > rune('Z') {
-						goto l125
+						goto l165
 					}
 					position++
-					goto l123
-				l125:
-					position, tokenIndex, depth = position123, tokenIndex123, depth123
+					goto l163
+				l165:
+					position, tokenIndex, depth = position163, tokenIndex163, depth163
 					if c := buffer[position]; c < rune('0') || c > rune('9') {
-						goto l126
+						goto l166
 					}
 					position++
-					goto l123
-				l126:
-					position, tokenIndex, depth = position123, tokenIndex123, depth123
+					goto l163
+				l166:
+					position, tokenIndex, depth = position163, tokenIndex163, depth163
 					if buffer[position] != rune('_') {
-						goto l121
+						goto l161
 					}
 					position++
 				}
-			l123:
+			l163:
 				depth--
-				add(ruleIdChar, position122)
+				add(ruleIdChar, position162)
 			}
 			return true
-		l121:
-			position, tokenIndex, depth = position121, tokenIndex121, depth121
+		l161:
+			position, tokenIndex, depth = position161, tokenIndex161, depth161
 			return false
 		},
-		/* 17 IdEnd <- <('?' / '!')> */
+		/* 21 IdEnd <- <('?' / '!')> */
 		func() bool {
-			position127, tokenIndex127, depth127 := position, tokenIndex, depth
+			position167, tokenIndex167, depth167 := position, tokenIndex, depth
 			{
-				position128 := position
+				position168 := position
 				depth++
 				{
-					position129, tokenIndex129, depth129 := position, tokenIndex, depth
+					position169, tokenIndex169, depth169 := position, tokenIndex, depth
 					if buffer[position] != rune('?') {
-						goto l130
+						goto l170
 					}
 					position++
-					goto l129
-				l130:
-					position, tokenIndex, depth = position129, tokenIndex129, depth129
+					goto l169
+				l170:
+					position, tokenIndex, depth = position169, tokenIndex169, depth169
 					if buffer[position] != rune('!') {
-						goto l127
+						goto l167
 					}
 					position++
 				}
-			l129:
+			l169:
 				depth--
-				add(ruleIdEnd, position128)
+				add(ruleIdEnd, position168)
 			}
 			return true
-		l127:
-			position, tokenIndex, depth = position127, tokenIndex127, depth127
+		l167:
+			position, tokenIndex, depth = position167, tokenIndex167, depth167
 			return false
 		},
-		/* 18 StringLiteral <- <(Quote StringChar* Quote Spacing)> */
+		/* 22 StringLiteral <- <(Quote StringChar* Quote Spacing)> */
 		func() bool {
-			position131, tokenIndex131, depth131 := position, tokenIndex, depth
+			position171, tokenIndex171, depth171 := position, tokenIndex, depth
 			{
-				position132 := position
+				position172 := position
 				depth++
 				if !_rules[ruleQuote]() {
-					goto l131
+					goto l171
 				}
-			l133:
+			l173:
 				{
-					position134, tokenIndex134, depth134 := position, tokenIndex, depth
+					position174, tokenIndex174, depth174 := position, tokenIndex, depth
 					if !_rules[ruleStringChar]() {
-						goto l134
+						goto l174
 					}
-					goto l133
-				l134:
-					position, tokenIndex, depth = position134, tokenIndex134, depth134
+					goto l173
+				l174:
+					position, tokenIndex, depth = position174, tokenIndex174, depth174
 				}
 				if !_rules[ruleQuote]() {
-					goto l131
+					goto l171
 				}
 				if !_rules[ruleSpacing]() {
-					goto l131
+					goto l171
 				}
 				depth--
-				add(ruleStringLiteral, position132)
+				add(ruleStringLiteral, position172)
 			}
 			return true
-		l131:
-			position, tokenIndex, depth = position131, tokenIndex131, depth131
+		l171:
+			position, tokenIndex, depth = position171, tokenIndex171, depth171
 			return false
 		},
-		/* 19 Quote <- <'"'> */
+		/* 23 Quote <- <'"'> */
 		func() bool {
-			position135, tokenIndex135, depth135 := position, tokenIndex, depth
+			position175, tokenIndex175, depth175 := position, tokenIndex, depth
 			{
-				position136 := position
+				position176 := position
 				depth++
 				if buffer[position] != rune('"') {
-					goto l135
+					goto l175
 				}
 				position++
 				depth--
-				add(ruleQuote, position136)
+				add(ruleQuote, position176)
 			}
 			return true
-		l135:
-			position, tokenIndex, depth = position135, tokenIndex135, depth135
+		l175:
+			position, tokenIndex, depth = position175, tokenIndex175, depth175
 			return false
 		},
-		/* 20 StringChar <- <(Escape / (!('"' / '\n' / '\\') .))> */
+		/* 24 StringChar <- <(Escape / (!('"' / '\n' / '\\') .))> */
 		func() bool {
-			position137, tokenIndex137, depth137 := position, tokenIndex, depth
+			position177, tokenIndex177, depth177 := position, tokenIndex, depth
 			{
-				position138 := position
+				position178 := position
 				depth++
 				{
-					position139, tokenIndex139, depth139 := position, tokenIndex, depth
+					position179, tokenIndex179, depth179 := position, tokenIndex, depth
 					if !_rules[ruleEscape]() {
-						goto l140
+						goto l180
 					}
-					goto l139
-				l140:
-					position, tokenIndex, depth = position139, tokenIndex139, depth139
+					goto l179
+				l180:
+					position, tokenIndex, depth = position179, tokenIndex179, depth179
 					{
-						position141, tokenIndex141, depth141 := position, tokenIndex, depth
+						position181, tokenIndex181, depth181 := position, tokenIndex, depth
 						{
-							position142, tokenIndex142, depth142 := position, tokenIndex, depth
+							position182, tokenIndex182, depth182 := position, tokenIndex, depth
 							if buffer[position] != rune('"') {
-								goto l143
+								goto l183
 							}
 							position++
-							goto l142
-						l143:
-							position, tokenIndex, depth = position142, tokenIndex142, depth142
+							goto l182
+						l183:
+							position, tokenIndex, depth = position182, tokenIndex182, depth182
 							if buffer[position] != rune('\n') {
-								goto l144
+								goto l184
 							}
 							position++
-							goto l142
-						l144:
-							position, tokenIndex, depth = position142, tokenIndex142, depth142
+							goto l182
+						l184:
+							position, tokenIndex, depth = position182, tokenIndex182, depth182
 							if buffer[position] != rune('\\') {
-								goto l141
+								goto l181
 							}
 							position++
 						}
-					l142:
-						goto l137
-					l141:
-						position, tokenIndex, depth = position141, tokenIndex141, depth141
+					l182:
+						goto l177
+					l181:
+						position, tokenIndex, depth = position181, tokenIndex181, depth181
 					}
 					if !matchDot() {
-						goto l137
+						goto l177
 					}
 				}
-			l139:
+			l179:
 				depth--
-				add(ruleStringChar, position138)
+				add(ruleStringChar, position178)
 			}
 			return true
-		l137:
-			position, tokenIndex, depth = position137, tokenIndex137, depth137
+		l177:
+			position, tokenIndex, depth = position177, tokenIndex177, depth177
 			return false
 		},
-		/* 21 Escape <- <('\\' (BlockWithoutSpacing / .))> */
+		/* 25 Escape <- <('\\' (BlockWithoutSpacing / .))> */
 		func() bool {
-			position145, tokenIndex145, depth145 := position, tokenIndex, depth
+			position185, tokenIndex185, depth185 := position, tokenIndex, depth
 			{
-				position146 := position
+				position186 := position
 				depth++
 				if buffer[position] != rune('\\') {
-					goto l145
+					goto l185
 				}
 				position++
 				{
-					position147, tokenIndex147, depth147 := position, tokenIndex, depth
+					position187, tokenIndex187, depth187 := position, tokenIndex, depth
 					if !_rules[ruleBlockWithoutSpacing]() {
-						goto l148
+						goto l188
 					}
-					goto l147
-				l148:
-					position, tokenIndex, depth = position147, tokenIndex147, depth147
+					goto l187
+				l188:
+					position, tokenIndex, depth = position187, tokenIndex187, depth187
 					if !matchDot() {
-						goto l145
+						goto l185
 					}
 				}
-			l147:
+			l187:
 				depth--
-				add(ruleEscape, position146)
+				add(ruleEscape, position186)
 			}
 			return true
-		l145:
-			position, tokenIndex, depth = position145, tokenIndex145, depth145
+		l185:
+			position, tokenIndex, depth = position185, tokenIndex185, depth185
 			return false
 		},
-		/* 22 LongStringLiteral <- <(BackTick LongStringChar* BackTick Spacing)> */
+		/* 26 LongStringLiteral <- <(BackTick LongStringChar* BackTick Spacing)> */
 		func() bool {
-			position149, tokenIndex149, depth149 := position, tokenIndex, depth
+			position189, tokenIndex189, depth189 := position, tokenIndex, depth
 			{
-				position150 := position
+				position190 := position
 				depth++
 				if !_rules[ruleBackTick]() {
-					goto l149
+					goto l189
 				}
-			l151:
+			l191:
 				{
-					position152, tokenIndex152, depth152 := position, tokenIndex, depth
+					position192, tokenIndex192, depth192 := position, tokenIndex, depth
 					if !_rules[ruleLongStringChar]() {
-						goto l152
+						goto l192
 					}
-					goto l151
-				l152:
-					position, tokenIndex, depth = position152, tokenIndex152, depth152
+					goto l191
+				l192:
+					position, tokenIndex, depth = position192, tokenIndex192, depth192
 				}
 				if !_rules[ruleBackTick]() {
-					goto l149
+					goto l189
 				}
 				if !_rules[ruleSpacing]() {
-					goto l149
+					goto l189
 				}
 				depth--
-				add(ruleLongStringLiteral, position150)
+				add(ruleLongStringLiteral, position190)
 			}
 			return true
-		l149:
-			position, tokenIndex, depth = position149, tokenIndex149, depth149
+		l189:
+			position, tokenIndex, depth = position189, tokenIndex189, depth189
 			return false
 		},
-		/* 23 BackTick <- <'`'> */
+		/* 27 BackTick <- <'`'> */
 		func() bool {
-			position153, tokenIndex153, depth153 := position, tokenIndex, depth
+			position193, tokenIndex193, depth193 := position, tokenIndex, depth
 			{
-				position154 := position
+				position194 := position
 				depth++
 				if buffer[position] != rune('`') {
-					goto l153
+					goto l193
 				}
 				position++
 				depth--
-				add(ruleBackTick, position154)
+				add(ruleBackTick, position194)
 			}
 			return true
-		l153:
-			position, tokenIndex, depth = position153, tokenIndex153, depth153
+		l193:
+			position, tokenIndex, depth = position193, tokenIndex193, depth193
 			return false
 		},
-		/* 24 LongStringChar <- <(LongEscape / (!'`' .))> */
+		/* 28 LongStringChar <- <(LongEscape / (!'`' .))> */
 		func() bool {
-			position155, tokenIndex155, depth155 := position, tokenIndex, depth
+			position195, tokenIndex195, depth195 := position, tokenIndex, depth
 			{
-				position156 := position
+				position196 := position
 				depth++
 				{
-					position157, tokenIndex157, depth157 := position, tokenIndex, depth
+					position197, tokenIndex197, depth197 := position, tokenIndex, depth
 					if !_rules[ruleLongEscape]() {
-						goto l158
+						goto l198
 					}
-					goto l157
-				l158:
-					position, tokenIndex, depth = position157, tokenIndex157, depth157
+					goto l197
+				l198:
+					position, tokenIndex, depth = position197, tokenIndex197, depth197
 					{
-						position159, tokenIndex159, depth159 := position, tokenIndex, depth
+						position199, tokenIndex199, depth199 := position, tokenIndex, depth
 						if buffer[position] != rune('`') {
-							goto l159
+							goto l199
 						}
 						position++
-						goto l155
-					l159:
-						position, tokenIndex, depth = position159, tokenIndex159, depth159
+						goto l195
+					l199:
+						position, tokenIndex, depth = position199, tokenIndex199, depth199
 					}
 					if !matchDot() {
-						goto l155
+						goto l195
 					}
 				}
-			l157:
+			l197:
 				depth--
-				add(ruleLongStringChar, position156)
+				add(ruleLongStringChar, position196)
 			}
 			return true
-		l155:
-			position, tokenIndex, depth = position155, tokenIndex155, depth155
+		l195:
+			position, tokenIndex, depth = position195, tokenIndex195, depth195
 			return false
 		},
-		/* 25 LongEscape <- <('`' (BlockWithoutSpacing / '`'))> */
+		/* 29 LongEscape <- <('`' (BlockWithoutSpacing / '`'))> */
 		func() bool {
-			position160, tokenIndex160, depth160 := position, tokenIndex, depth
+			position200, tokenIndex200, depth200 := position, tokenIndex, depth
 			{
-				position161 := position
+				position201 := position
 				depth++
 				if buffer[position] != rune('`') {
-					goto l160
+					goto l200
 				}
 				position++
 				{
-					position162, tokenIndex162, depth162 := position, tokenIndex, depth
+					position202, tokenIndex202, depth202 := position, tokenIndex, depth
 					if !_rules[ruleBlockWithoutSpacing]() {
-						goto l163
+						goto l203
 					}
-					goto l162
-				l163:
-					position, tokenIndex, depth = position162, tokenIndex162, depth162
+					goto l202
+				l203:
+					position, tokenIndex, depth = position202, tokenIndex202, depth202
 					if buffer[position] != rune('`') {
-						goto l160
+						goto l200
 					}
 					position++
 				}
-			l162:
+			l202:
 				depth--
-				add(ruleLongEscape, position161)
+				add(ruleLongEscape, position201)
 			}
 			return true
-		l160:
-			position, tokenIndex, depth = position160, tokenIndex160, depth160
+		l200:
+			position, tokenIndex, depth = position200, tokenIndex200, depth200
 			return false
 		},
-		/* 26 Number <- <(Numbers Spacing)> */
+		/* 30 Number <- <(Numbers Spacing)> */
 		func() bool {
-			position164, tokenIndex164, depth164 := position, tokenIndex, depth
+			position204, tokenIndex204, depth204 := position, tokenIndex, depth
 			{
-				position165 := position
+				position205 := position
 				depth++
 				if !_rules[ruleNumbers]() {
-					goto l164
+					goto l204
 				}
 				if !_rules[ruleSpacing]() {
-					goto l164
+					goto l204
 				}
 				depth--
-				add(ruleNumber, position165)
+				add(ruleNumber, position205)
 			}
 			return true
-		l164:
-			position, tokenIndex, depth = position164, tokenIndex164, depth164
+		l204:
+			position, tokenIndex, depth = position204, tokenIndex204, depth204
 			return false
 		},
-		/* 27 Numbers <- <('-'? [0-9] [0-9]* ('.' [0-9] [0-9]*)?)> */
+		/* 31 Numbers <- <('-'? [0-9] [0-9]* ('.' [0-9] [0-9]*)?)> */
 		func() bool {
-			position166, tokenIndex166, depth166 := position, tokenIndex, depth
+			position206, tokenIndex206, depth206 := position, tokenIndex, depth
 			{
-				position167 := position
+				position207 := position
 				depth++
 				{
-					position168, tokenIndex168, depth168 := position, tokenIndex, depth
+					position208, tokenIndex208, depth208 := position, tokenIndex, depth
 					if buffer[position] != rune('-') {
-						goto l168
+						goto l208
 					}
 					position++
-					goto l169
-				l168:
-					position, tokenIndex, depth = position168, tokenIndex168, depth168
+					goto l209
+				l208:
+					position, tokenIndex, depth = position208, tokenIndex208, depth208
 				}
-			l169:
+			l209:
 				if c := buffer[position]; c < rune('0') || c > rune('9') {
-					goto l166
+					goto l206
 				}
 				position++
-			l170:
+			l210:
 				{
-					position171, tokenIndex171, depth171 := position, tokenIndex, depth
+					position211, tokenIndex211, depth211 := position, tokenIndex, depth
 					if c := buffer[position]; c < rune('0') || c > rune('9') {
-						goto l171
+						goto l211
 					}
 					position++
-					goto l170
-				l171:
-					position, tokenIndex, depth = position171, tokenIndex171, depth171
+					goto l210
+				l211:
+					position, tokenIndex, depth = position211, tokenIndex211, depth211
 				}
 				{
-					position172, tokenIndex172, depth172 := position, tokenIndex, depth
+					position212, tokenIndex212, depth212 := position, tokenIndex, depth
 					if buffer[position] != rune('.') {
-						goto l172
+						goto l212
 					}
 					position++
 					if c := buffer[position]; c < rune('0') || c > rune('9') {
-						goto l172
+						goto l212
 					}
 					position++
-				l174:
+				l214:
 					{
-						position175, tokenIndex175, depth175 := position, tokenIndex, depth
+						position215, tokenIndex215, depth215 := position, tokenIndex, depth
 						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l175
+							goto l215
 						}
 						position++
-						goto l174
-					l175:
-						position, tokenIndex, depth = position175, tokenIndex175, depth175
+						goto l214
+					l215:
+						position, tokenIndex, depth = position215, tokenIndex215, depth215
 					}
-					goto l173
-				l172:
-					position, tokenIndex, depth = position172, tokenIndex172, depth172
+					goto l213
+				l212:
+					position, tokenIndex, depth = position212, tokenIndex212, depth212
 				}
-			l173:
+			l213:
 				depth--
-				add(ruleNumbers, position167)
+				add(ruleNumbers, position207)
 			}
 			return true
-		l166:
-			position, tokenIndex, depth = position166, tokenIndex166, depth166
+		l206:
+			position, tokenIndex, depth = position206, tokenIndex206, depth206
 			return false
 		},
-		/* 28 LPAR <- <('(' Spacing)> */
+		/* 32 LPAR <- <('(' Spacing)> */
 		func() bool {
-			position176, tokenIndex176, depth176 := position, tokenIndex, depth
+			position216, tokenIndex216, depth216 := position, tokenIndex, depth
 			{
-				position177 := position
+				position217 := position
 				depth++
 				if buffer[position] != rune('(') {
-					goto l176
+					goto l216
 				}
 				position++
 				if !_rules[ruleSpacing]() {
-					goto l176
+					goto l216
 				}
 				depth--
-				add(ruleLPAR, position177)
+				add(ruleLPAR, position217)
 			}
 			return true
-		l176:
-			position, tokenIndex, depth = position176, tokenIndex176, depth176
+		l216:
+			position, tokenIndex, depth = position216, tokenIndex216, depth216
 			return false
 		},
-		/* 29 RPAR <- <(')' Spacing)> */
+		/* 33 RPAR <- <(')' Spacing)> */
 		func() bool {
-			position178, tokenIndex178, depth178 := position, tokenIndex, depth
+			position218, tokenIndex218, depth218 := position, tokenIndex, depth
 			{
-				position179 := position
+				position219 := position
 				depth++
 				if buffer[position] != rune(')') {
-					goto l178
+					goto l218
 				}
 				position++
 				if !_rules[ruleSpacing]() {
-					goto l178
+					goto l218
 				}
 				depth--
-				add(ruleRPAR, position179)
+				add(ruleRPAR, position219)
 			}
 			return true
-		l178:
-			position, tokenIndex, depth = position178, tokenIndex178, depth178
+		l218:
+			position, tokenIndex, depth = position218, tokenIndex218, depth218
 			return false
 		},
-		/* 30 LCURLY <- <('{' Spacing)> */
+		/* 34 LCURLY <- <('{' Spacing)> */
 		func() bool {
-			position180, tokenIndex180, depth180 := position, tokenIndex, depth
+			position220, tokenIndex220, depth220 := position, tokenIndex, depth
 			{
-				position181 := position
+				position221 := position
 				depth++
 				if buffer[position] != rune('{') {
-					goto l180
+					goto l220
 				}
 				position++
 				if !_rules[ruleSpacing]() {
-					goto l180
+					goto l220
 				}
 				depth--
-				add(ruleLCURLY, position181)
+				add(ruleLCURLY, position221)
 			}
 			return true
-		l180:
-			position, tokenIndex, depth = position180, tokenIndex180, depth180
+		l220:
+			position, tokenIndex, depth = position220, tokenIndex220, depth220
 			return false
 		},
-		/* 31 RCURLY <- <('}' Spacing)> */
+		/* 35 RCURLY <- <('}' Spacing)> */
 		func() bool {
-			position182, tokenIndex182, depth182 := position, tokenIndex, depth
+			position222, tokenIndex222, depth222 := position, tokenIndex, depth
 			{
-				position183 := position
+				position223 := position
 				depth++
 				if buffer[position] != rune('}') {
-					goto l182
+					goto l222
 				}
 				position++
 				if !_rules[ruleSpacing]() {
-					goto l182
+					goto l222
 				}
 				depth--
-				add(ruleRCURLY, position183)
+				add(ruleRCURLY, position223)
 			}
 			return true
-		l182:
-			position, tokenIndex, depth = position182, tokenIndex182, depth182
+		l222:
+			position, tokenIndex, depth = position222, tokenIndex222, depth222
 			return false
 		},
-		/* 32 LBRACKET <- <('[' Spacing)> */
+		/* 36 LBRACKET <- <('[' Spacing)> */
 		func() bool {
-			position184, tokenIndex184, depth184 := position, tokenIndex, depth
+			position224, tokenIndex224, depth224 := position, tokenIndex, depth
 			{
-				position185 := position
+				position225 := position
 				depth++
 				if buffer[position] != rune('[') {
-					goto l184
+					goto l224
 				}
 				position++
 				if !_rules[ruleSpacing]() {
-					goto l184
+					goto l224
 				}
 				depth--
-				add(ruleLBRACKET, position185)
+				add(ruleLBRACKET, position225)
 			}
 			return true
-		l184:
-			position, tokenIndex, depth = position184, tokenIndex184, depth184
+		l224:
+			position, tokenIndex, depth = position224, tokenIndex224, depth224
 			return false
 		},
-		/* 33 RBRACKET <- <(']' Spacing)> */
+		/* 37 RBRACKET <- <(']' Spacing)> */
 		func() bool {
-			position186, tokenIndex186, depth186 := position, tokenIndex, depth
+			position226, tokenIndex226, depth226 := position, tokenIndex, depth
 			{
-				position187 := position
+				position227 := position
 				depth++
 				if buffer[position] != rune(']') {
-					goto l186
+					goto l226
 				}
 				position++
 				if !_rules[ruleSpacing]() {
-					goto l186
+					goto l226
 				}
 				depth--
-				add(ruleRBRACKET, position187)
+				add(ruleRBRACKET, position227)
 			}
 			return true
-		l186:
-			position, tokenIndex, depth = position186, tokenIndex186, depth186
+		l226:
+			position, tokenIndex, depth = position226, tokenIndex226, depth226
 			return false
 		},
-		/* 34 COMMA <- <(',' Spacing)> */
+		/* 38 COMMA <- <(',' Spacing)> */
 		func() bool {
-			position188, tokenIndex188, depth188 := position, tokenIndex, depth
+			position228, tokenIndex228, depth228 := position, tokenIndex, depth
 			{
-				position189 := position
+				position229 := position
 				depth++
 				if buffer[position] != rune(',') {
-					goto l188
+					goto l228
 				}
 				position++
 				if !_rules[ruleSpacing]() {
-					goto l188
+					goto l228
 				}
 				depth--
-				add(ruleCOMMA, position189)
+				add(ruleCOMMA, position229)
 			}
 			return true
-		l188:
-			position, tokenIndex, depth = position188, tokenIndex188, depth188
+		l228:
+			position, tokenIndex, depth = position228, tokenIndex228, depth228
 			return false
 		},
-		/* 35 PCOMMA <- <(';' Spacing)> */
+		/* 39 PCOMMA <- <(';' Spacing)> */
 		func() bool {
-			position190, tokenIndex190, depth190 := position, tokenIndex, depth
+			position230, tokenIndex230, depth230 := position, tokenIndex, depth
 			{
-				position191 := position
+				position231 := position
 				depth++
 				if buffer[position] != rune(';') {
-					goto l190
+					goto l230
 				}
 				position++
 				if !_rules[ruleSpacing]() {
-					goto l190
+					goto l230
 				}
 				depth--
-				add(rulePCOMMA, position191)
+				add(rulePCOMMA, position231)
 			}
 			return true
-		l190:
-			position, tokenIndex, depth = position190, tokenIndex190, depth190
+		l230:
+			position, tokenIndex, depth = position230, tokenIndex230, depth230
 			return false
 		},
-		/* 36 COLON <- <(':' Spacing)> */
+		/* 40 COLON <- <(':' Spacing)> */
 		func() bool {
-			position192, tokenIndex192, depth192 := position, tokenIndex, depth
+			position232, tokenIndex232, depth232 := position, tokenIndex, depth
 			{
-				position193 := position
+				position233 := position
 				depth++
 				if buffer[position] != rune(':') {
-					goto l192
+					goto l232
+				}
+				position++
+				if !_rules[ruleSpacing]() {
+					goto l232
+				}
+				depth--
+				add(ruleCOLON, position233)
+			}
+			return true
+		l232:
+			position, tokenIndex, depth = position232, tokenIndex232, depth232
+			return false
+		},
+		/* 41 EQUAL <- <('=' Spacing)> */
+		func() bool {
+			position234, tokenIndex234, depth234 := position, tokenIndex, depth
+			{
+				position235 := position
+				depth++
+				if buffer[position] != rune('=') {
+					goto l234
+				}
+				position++
+				if !_rules[ruleSpacing]() {
+					goto l234
+				}
+				depth--
+				add(ruleEQUAL, position235)
+			}
+			return true
+		l234:
+			position, tokenIndex, depth = position234, tokenIndex234, depth234
+			return false
+		},
+		/* 42 DOT <- <('.' Spacing)> */
+		func() bool {
+			position236, tokenIndex236, depth236 := position, tokenIndex, depth
+			{
+				position237 := position
+				depth++
+				if buffer[position] != rune('.') {
+					goto l236
 				}
 				position++
 				if !_rules[ruleSpacing]() {
-					goto l192
+					goto l236
 				}
 				depth--
-				add(ruleCOLON, position193)
+				add(ruleDOT, position237)
 			}
 			return true
-		l192:
-			position, tokenIndex, depth = position192, tokenIndex192, depth192
+		l236:
+			position, tokenIndex, depth = position236, tokenIndex236, depth236
 			return false
 		},
-		/* 37 DOT <- <('.' Spacing)> */
+		/* 43 ELLIPSIS <- <('.' '.' '.' Spacing)> */
 		func() bool {
-			position194, tokenIndex194, depth194 := position, tokenIndex, depth
+			position238, tokenIndex238, depth238 := position, tokenIndex, depth
 			{
-				position195 := position
+				position239 := position
 				depth++
 				if buffer[position] != rune('.') {
-					goto l194
+					goto l238
+				}
+				position++
+				if buffer[position] != rune('.') {
+					goto l238
+				}
+				position++
+				if buffer[position] != rune('.') {
+					goto l238
 				}
 				position++
 				if !_rules[ruleSpacing]() {
-					goto l194
+					goto l238
 				}
 				depth--
-				add(ruleDOT, position195)
+				add(ruleELLIPSIS, position239)
 			}
 			return true
-		l194:
-			position, tokenIndex, depth = position194, tokenIndex194, depth194
+		l238:
+			position, tokenIndex, depth = position238, tokenIndex238, depth238
 			return false
 		},
-		/* 38 PIPE <- <('|' Spacing)> */
+		/* 44 PIPE <- <('|' Spacing)> */
 		func() bool {
-			position196, tokenIndex196, depth196 := position, tokenIndex, depth
+			position240, tokenIndex240, depth240 := position, tokenIndex, depth
 			{
-				position197 := position
+				position241 := position
 				depth++
 				if buffer[position] != rune('|') {
-					goto l196
+					goto l240
 				}
 				position++
 				if !_rules[ruleSpacing]() {
-					goto l196
+					goto l240
 				}
 				depth--
-				add(rulePIPE, position197)
+				add(rulePIPE, position241)
 			}
 			return true
-		l196:
-			position, tokenIndex, depth = position196, tokenIndex196, depth196
+		l240:
+			position, tokenIndex, depth = position240, tokenIndex240, depth240
 			return false
 		},
-		/* 39 DOLLAR <- <('$' Spacing)> */
+		/* 45 DOLLAR <- <('$' Spacing)> */
 		func() bool {
-			position198, tokenIndex198, depth198 := position, tokenIndex, depth
+			position242, tokenIndex242, depth242 := position, tokenIndex, depth
 			{
-				position199 := position
+				position243 := position
 				depth++
 				if buffer[position] != rune('$') {
-					goto l198
+					goto l242
 				}
 				position++
 				if !_rules[ruleSpacing]() {
-					goto l198
+					goto l242
 				}
 				depth--
-				add(ruleDOLLAR, position199)
+				add(ruleDOLLAR, position243)
 			}
 			return true
-		l198:
-			position, tokenIndex, depth = position198, tokenIndex198, depth198
+		l242:
+			position, tokenIndex, depth = position242, tokenIndex242, depth242
 			return false
 		},
-		/* 40 AMPERSAND <- <('&' Spacing)> */
+		/* 46 AMPERSAND <- <('&' Spacing)> */
 		func() bool {
-			position200, tokenIndex200, depth200 := position, tokenIndex, depth
+			position244, tokenIndex244, depth244 := position, tokenIndex, depth
 			{
-				position201 := position
+				position245 := position
 				depth++
 				if buffer[position] != rune('&') {
-					goto l200
+					goto l244
 				}
 				position++
 				if !_rules[ruleSpacing]() {
-					goto l200
+					goto l244
 				}
 				depth--
-				add(ruleAMPERSAND, position201)
+				add(ruleAMPERSAND, position245)
 			}
 			return true
-		l200:
-			position, tokenIndex, depth = position200, tokenIndex200, depth200
+		l244:
+			position, tokenIndex, depth = position244, tokenIndex244, depth244
 			return false
 		},
-		/* 41 EOT <- <!.> */
+		/* 47 EOT <- <!.> */
 		func() bool {
-			position202, tokenIndex202, depth202 := position, tokenIndex, depth
+			position246, tokenIndex246, depth246 := position, tokenIndex, depth
 			{
-				position203 := position
+				position247 := position
 				depth++
 				{
-					position204, tokenIndex204, depth204 := position, tokenIndex, depth
+					position248, tokenIndex248, depth248 := position, tokenIndex, depth
 					if !matchDot() {
-						goto l204
+						goto l248
 					}
-					goto l202
-				l204:
-					position, tokenIndex, depth = position204, tokenIndex204, depth204
+					goto l246
+				l248:
+					position, tokenIndex, depth = position248, tokenIndex248, depth248
 				}
 				depth--
-				add(ruleEOT, position203)
+				add(ruleEOT, position247)
 			}
 			return true
-		l202:
-			position, tokenIndex, depth = position202, tokenIndex202, depth202
+		l246:
+			position, tokenIndex, depth = position246, tokenIndex246, depth246
 			return false
 		},
 	}
@@ -213,6 +213,7 @@ func TestParseCommandWithBlockWithCalls(t *testing.T) {
 func TestParseCommandWithShortcutAsParameter(t *testing.T) {
 	ParseAndTest(t, "chipotle : sauce", expectOneLineContaining(t, IdentifierFollowedByShortcutAndArgument(t, ruleCOLON, ruleIdentifier)))
 	ParseAndTest(t, "chipotle: sauce", expectOneLineContaining(t, IdentifierFollowedByShortcutAndArgument(t, ruleCOLON, ruleIdentifier)))
+	ParseAndTest(t, "chipotle:sauce", expectOneLineContaining(t, IdentifierFollowedByShortcutAndArgument(t, ruleCOLON, ruleIdentifier)))
 }
 
 func TestParseCommandWithPipedOutput(t *testing.T) {
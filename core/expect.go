@@ -73,6 +73,19 @@ func ExpectErrorValueAt(t *testing.T, lineno int) func(RunContext, Value) {
 	}
 }
 
+// ExpectAbortErrorValue returns a function that expects execution has been aborted
+// by cancellation or by exceeding a limit
+//
+func ExpectAbortErrorValue(t *testing.T) func(RunContext, Value) {
+
+	return func(context RunContext, blockResult Value) {
+
+		if blockResult.Type() != TypeError || !blockResult.(ErrorValue).IsAborted() {
+			t.Errorf("expected execution to be aborted but found %v at %s", blockResult, getCallingFunc())
+		}
+	}
+}
+
 // ExpectNothing returns a function that expects evaluation returns Nothing
 //
 func ExpectNothing(t *testing.T) func(RunContext, Value) {
@@ -100,6 +113,18 @@ func ExpectValue(t *testing.T, value Value) func(RunContext, Value) {
 	}
 }
 
+// ExpectString returns a function that expects evaluation returns a value
+// with a given string representation
+//
+func ExpectString(t *testing.T, expected string) func(RunContext, Value) {
+
+	return func(context RunContext, blockResult Value) {
+		if blockResult.String() != expected {
+			t.Errorf("expected %s but found %v at %s", expected, blockResult, getCallingFunc())
+		}
+	}
+}
+
 // ExpectValues returns a function that expects evaluation returns specified values
 // as ReturnValue
 //
@@ -111,3 +136,25 @@ func ExpectValues(t *testing.T, values ...Value) func(RunContext, Value) {
 		}
 	}
 }
+
+// ExpectIterated returns a function that expects evaluation returns an
+// iterable producing the values of a given list
+//
+func ExpectIterated(t *testing.T, list Value) func(RunContext, Value) {
+
+	return func(context RunContext, blockResult Value) {
+		iterable, ok := AsIterable(blockResult)
+		if !ok {
+			t.Errorf("expected iterable but found %v at %s", blockResult, getCallingFunc())
+			return
+		}
+		values, err := CollectValues(context, iterable)
+		if err != nil {
+			t.Errorf("%v at %s", err.Error(), getCallingFunc())
+			return
+		}
+		if !reflect.DeepEqual(NewListValue(values), list) {
+			t.Errorf("expected iterable to produce %v but found %v at %s", list, values, getCallingFunc())
+		}
+	}
+}
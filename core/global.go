@@ -42,11 +42,23 @@ func NewGlobalContext() RunContext {
 	context.SetNamed(ampersand())
 	context.SetNamed(_func())
 	context.SetNamed(template())
+	context.SetNamed(_generator())
+	context.SetNamed(yield())
+	context.SetNamed(_range())
 	context.SetNamed(_if())
 	context.SetNamed(while())
 	context.SetNamed(until())
 	context.SetNamed(do())
+	context.SetNamed(_for())
+	context.SetNamed(_break())
+	context.SetNamed(_continue())
+	context.SetNamed(try())
+	context.SetNamed(match())
 	context.SetNamed(mixin())
+	context.SetNamed(extend())
+	context.SetNamed(_new())
+	context.SetNamed(isA())
+	context.SetNamed(deftype())
 	context.SetNamed(load())
 	context.SetNamed(eval())
 	context.SetNamed(parse())
@@ -69,6 +81,7 @@ func NewGlobalContext() RunContext {
 	context.SetNamed(multiply())
 	context.SetNamed(divide())
 	context.SetNamed(modulo())
+	context.SetNamed(expr())
 	context.SetNamed(assert())
 	context.SetNamed(_error())
 	context.SetNamed(_panic())
@@ -105,6 +118,9 @@ func ListConstructor(context RunContext, arguments []Argument) Value {
 
 		values[i] = value
 	}
+	if err := CheckCollectionSize(context, len(values)); err != nil {
+		return err
+	}
 	return NewListValue(values)
 }
 
@@ -145,52 +161,119 @@ func _type() NamedValue {
 		})
 }
 
-func setOrLet(convertBlockToDictionary bool, name, help string) NamedValue {
-	return NewGoFunctionWithHelp(name, help, func(context RunContext, arguments []Argument) Value {
+// assignToDictionary assigns a value to a key of a dictionary when given
+// argument is a namespaced identifier, like this.name. Returns false when
+// argument is not a namespaced identifier
+//
+func assignToDictionary(context RunContext, argument Argument, value Value) (bool, ErrorValue) {
+	if argument.Type() != TypeIdentifier {
+		return false, nil
+	}
 
-		argLen, err := CheckArguments(arguments, 2, math.MaxInt16, name, "<identifier>* value")
-		if err != nil {
-			return err
-		}
+	id, ok := argument.Value().(*identifier)
+	if !ok || len(id.value) < 2 {
+		return false, nil
+	}
 
-		var value = EvalArgument(context, arguments[argLen-1])
+	owner := NewNameSpacedIdentifier(id.value[:len(id.value)-1]).(*identifier)
+	_, found, ok := owner.LookUp(context)
+	if !ok {
+		return true, NewErrorValue(fmt.Sprintf("can not assign %v, could not resolve %v", id, owner))
+	}
 
-		// value can evaluate to a multiple return so will result
-		// in multiple assignments
-		if value.Type() == TypeReturn {
-			returnedValues := value.(*returnValue).values
-			returnedLength := len(returnedValues)
+	dict, ok := found.(DictionaryValue)
+	if !ok {
+		return true, NewErrorValue(fmt.Sprintf("can not assign %v, %v is not a dictionary", id, owner))
+	}
 
-			for i := 0; i < (argLen-1) && i < returnedLength; i++ {
-				name := EvalArgument2String(context, arguments[i])
-				context.Set(name, returnedValues[i])
-			}
-		} else {
+	if _, err := dict.Set(NewStringLiteral(id.value[len(id.value)-1]), value); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// assignFunction is the function behind set and let. The vm recognizes
+// it to assign local variables without calling it
+//
+type assignFunction struct {
+	goFunction
+	convertBlockToDictionary bool
+}
 
-			_, err := CheckArguments(arguments, 2, 2, "set", "<identifier> value")
+func setOrLet(convertBlockToDictionary bool, name, help string) NamedValue {
+	return &assignFunction{goFunction: goFunction{baseValue: baseValue{info: typeInfoGoFunction}, name: name, help: NewStringLiteral(help),
+		value: func(context RunContext, arguments []Argument) Value {
+
+			argLen, err := CheckArguments(arguments, 2, math.MaxInt16, name, "<identifier>* value")
 			if err != nil {
 				return err
 			}
 
-			// convert block to dictionary
-			//
-			if convertBlockToDictionary && value.Type() == TypeBlock {
-				value = NewDictionaryWithBlock(context, value.(Block))
+			return assign(context, arguments, EvalArgument(context, arguments[argLen-1]), convertBlockToDictionary)
+		}}, convertBlockToDictionary: convertBlockToDictionary}
+}
+
+// assign assigns an evaluated value to the variables denoted by all but
+// the last argument, like set and let do
+//
+func assign(context RunContext, arguments []Argument, value Value, convertBlockToDictionary bool) Value {
+
+	argLen := len(arguments)
+
+	// value can evaluate to a multiple return so will result
+	// in multiple assignments
+	if value.Type() == TypeReturn {
+		returnedValues := value.(*returnValue).values
+		returnedLength := len(returnedValues)
+
+		for i := 0; i < (argLen-1) && i < returnedLength; i++ {
+			name := EvalArgument2String(context, arguments[i])
+			context.Set(name, returnedValues[i])
+		}
+	} else {
+
+		_, err := CheckArguments(arguments, 2, 2, "set", "<identifier> value")
+		if err != nil {
+			return err
+		}
+
+		// convert block to dictionary
+		//
+		if (convertBlockToDictionary || isShape(arguments[0])) && value.Type() == TypeBlock {
+			value = NewDictionaryWithBlock(context, value.(Block))
+		}
+
+		// unpack list or dictionary
+		//
+		if isShape(arguments[0]) && value.Type() != TypeError {
+			if err := destructure(context, arguments[0], value); err != nil {
+				return err
 			}
+			return value
+		}
 
-			name := EvalArgument2String(context, arguments[0])
+		name := EvalArgument2String(context, arguments[0])
+		if function, isFunction := value.(*inspectableGoFunction); isFunction {
+			function.nameWhenAnonymous(name)
+		}
+
+		// assignments to keys of dictionaries, like this.name
+		//
+		if assigned, err := assignToDictionary(context, arguments[0], value); err != nil {
+			return err
+		} else if !assigned {
 			context.Set(name, value)
 		}
+	}
 
-		if value.Type() == TypeError {
-			if !value.(ErrorValue).IsFatal() {
-				// can ignore non fatal errors in assignments
-				//
-				return value.(ErrorValue).Ignore()
-			}
+	if value.Type() == TypeError {
+		if !value.(ErrorValue).IsFatal() {
+			// can ignore non fatal errors in assignments
+			//
+			return value.(ErrorValue).Ignore()
 		}
-		return value
-	})
+	}
+	return value
 }
 
 func set() NamedValue {
@@ -221,6 +304,16 @@ func set() NamedValue {
 		> b
 		will result in 2
 
+		A list or dictionary can be unpacked by using a list or dictionary
+		shape instead of a symbol. Shapes can be nested, elements can have
+		a default value and the last element of a list shape can capture
+		the remaining elements. Missing elements result in an error.
+		> set [a b ...rest] [1 2 3 4]
+		> rest
+		will result in [3 4]
+		> set {name hotness?0; origin: {country}} $pepper
+		> set [name [low high]] ["naga" [855000 1463000]]
+
 		Note, instead of using set, it's possible to use the ':' shortcut like:
 		> a: 3
 		or
@@ -419,6 +512,15 @@ func incr() NamedValue {
 
 			if found {
 
+				// dictionaries can be incremented by their _incr hook
+				//
+				if newValue, hooked := runValueHook(context, currentValue, hookIncr, incrValue); hooked {
+					if arg0.Type() == TypeIdentifier && newValue.Type() != TypeError {
+						context.Set(arg0.String(), newValue)
+					}
+					return newValue
+				}
+
 				_, isIncrementable := currentValue.(IncrementableValue)
 				if isIncrementable {
 
@@ -558,6 +660,9 @@ func _if() NamedValue {
 			}
 
 			condition := EvalArgument(context, arguments[0])
+			if condition.Type() == TypeError && condition.(ErrorValue).IsAborted() {
+				return condition
+			}
 			if condition.Type() != TypeBoolean {
 				return NewErrorValue("if condition does not evaluate to a boolean value")
 			}
@@ -596,16 +701,31 @@ func createLoop(name string, stopCondition bool) func(context RunContext, argume
 		var result Value
 		result = Nothing
 		for {
+			// every iteration counts as a step so even
+			// empty loops can be aborted
+			//
+			if err := context.step(); err != nil {
+				return err
+			}
+
 			condition := EvalArgument(context, arguments[0])
+			if condition.Type() == TypeError && condition.(ErrorValue).IsAborted() {
+				return condition
+			}
 			if condition.Type() != TypeBoolean {
 				return NewErrorValue("condition does not evaluate to a boolean value")
 			}
-			if condition.(*booleanLiteral).value == stopCondition {
-				result = EvalArgumentWithBlock(context, arguments[1])
-				if result.Type() == TypeError {
-					return result
-				}
-			} else {
+			if condition.(*booleanLiteral).value != stopCondition {
+				return result
+			}
+
+			iteration, done := RunLoopIteration(context, func(iterationContext RunContext) Value {
+				return EvalArgumentWithBlock(iterationContext, arguments[1])
+			})
+			if iteration != nil {
+				result = iteration
+			}
+			if done {
 				return result
 			}
 		}
@@ -624,7 +744,9 @@ func while() NamedValue {
 
 		Note, the result of while can be assigned to a variable.
 
-		>  c: (while (lt $a 10) { incr a; incr b })`,
+		>  c: (while (lt $a 10) { incr a; incr b })
+
+		Use break to end the loop and continue to skip to the next iteration.`,
 
 		createLoop("while", true))
 }
@@ -641,7 +763,9 @@ func until() NamedValue {
 
 		Note, the result of while can be assigned to a variable.
 
-		>  c: (until (eq $a 10) { incr a; incr b })`, createLoop("until", false))
+		>  c: (until (eq $a 10) { incr a; incr b })
+
+		Use break to end the loop and continue to skip to the next iteration.`, createLoop("until", false))
 }
 
 func do() NamedValue {
@@ -654,7 +778,9 @@ func do() NamedValue {
 		> a: 1
 		> do {puts $a; incr a} while (lt $a 10)
 		> a: 1
-		> do {puts $a; incr a} until (eq $a 10)`,
+		> do {puts $a; incr a} until (eq $a 10)
+
+		Use break to end the loop and continue to skip to the next iteration.`,
 
 		func(context RunContext, arguments []Argument) Value {
 
@@ -663,7 +789,19 @@ func do() NamedValue {
 				return err
 			}
 
-			result := EvalArgumentWithBlock(context, arguments[0])
+			var result Value = Nothing
+
+			// first iteration is always executed
+			//
+			iteration, done := RunLoopIteration(context, func(iterationContext RunContext) Value {
+				return EvalArgumentWithBlock(iterationContext, arguments[0])
+			})
+			if iteration != nil {
+				result = iteration
+			}
+			if done {
+				return result
+			}
 
 			// while => true, until => false
 			//
@@ -678,15 +816,27 @@ func do() NamedValue {
 			}
 
 			for {
+				if err := context.step(); err != nil {
+					return err
+				}
+
 				condition := EvalArgument(context, arguments[2])
+				if condition.Type() == TypeError && condition.(ErrorValue).IsAborted() {
+					return condition
+				}
 				if condition.Type() != TypeBoolean {
 					return NewErrorValue("condition does not evaluate to a boolean value")
 				}
 				if !(condition.(*booleanLiteral).value == stopCondition) {
 					return result
 				}
-				result = EvalArgumentWithBlock(context, arguments[0])
-				if result.Type() == TypeError {
+				iteration, done := RunLoopIteration(context, func(iterationContext RunContext) Value {
+					return EvalArgumentWithBlock(iterationContext, arguments[0])
+				})
+				if iteration != nil {
+					result = iteration
+				}
+				if done {
 					return result
 				}
 			}
@@ -764,11 +914,11 @@ func puts() NamedValue {
 
 		func(context RunContext, arguments []Argument) Value {
 			if len(arguments) == 1 {
-				fmt.Printf("%s\n", EvalArgument(context, arguments[0]))
+				fmt.Printf("%s\n", StringOf(context, EvalArgument(context, arguments[0])))
 			} else {
 				line := ""
 				for _, arg := range arguments {
-					line = fmt.Sprintf("%s%s", line, EvalArgument(context, arg))
+					line = fmt.Sprintf("%s%s", line, StringOf(context, EvalArgument(context, arg)))
 				}
 				fmt.Printf("%s\n", line)
 			}
@@ -818,7 +968,7 @@ func toS() NamedValue {
 				return err
 			}
 
-			return NewStringLiteral(EvalArgument(context, arguments[0]).String())
+			return NewStringLiteral(StringOf(context, EvalArgument(context, arguments[0])))
 		})
 }
 
@@ -830,7 +980,9 @@ func sleep() NamedValue {
 		Example:
 
 		> sleep 1000
-		will pause for one second`,
+		will pause for one second
+
+		Note, sleep is interrupted when execution is cancelled`,
 
 		func(context RunContext, arguments []Argument) Value {
 
@@ -846,14 +998,22 @@ func sleep() NamedValue {
 			}
 
 			sleepTime := time.Duration(duration.(*integerLiteral).value)
-			time.Sleep(time.Millisecond * sleepTime)
+			timer := time.NewTimer(time.Millisecond * sleepTime)
+			defer timer.Stop()
 
-			return Nothing
+			// wake up early when execution is cancelled
+			//
+			select {
+			case <-timer.C:
+				return Nothing
+			case <-context.Context().Done():
+				return Cancelled(context)
+			}
 		})
 }
 
 func load() NamedValue {
-	return NewGoFunctionWithHelp("load", `Loads given module or script
+	return asTraceBoundary(NewGoFunctionWithHelp("load", `Loads given module or script
 		Usage: load <module|script>
 		Returns: A dictionary containing loaded variables
 
@@ -867,7 +1027,12 @@ func load() NamedValue {
 		> helper: (load "include/functions")
 
 		Last example will load the script 'incude/functions.mo' that should be
-		located relatively from the current script
+		located relatively from the current script. Scripts that can not be
+		found there are looked up in the folders given by the -I flag and in
+		the folders of the ELMOPATH environment variable
+
+		Scripts are loaded once, loading a script again results in the same
+		dictionary. Scripts that load each other result in a load cycle error
 
 		Note, the ".mo" extension is implied and should not be specified`,
 
@@ -883,6 +1048,9 @@ func load() NamedValue {
 			module, found := context.Module(name)
 
 			if found {
+				if policy := context.Policy(); policy != nil && !policy.AllowsModule(name) {
+					return NewNotPermittedErrorValue(fmt.Sprintf("loading module %s", name))
+				}
 				content := module.Content(context)
 				return content
 			}
@@ -900,7 +1068,7 @@ func load() NamedValue {
 			}
 
 			return loaded
-		})
+		}))
 }
 
 func eval() NamedValue {
@@ -988,6 +1156,18 @@ func parse() NamedValue {
 }
 
 func compareValues(context RunContext, v1 Value, v2 Value, f func(int) Value) Value {
+
+	// a dictionary can compare itself with values that do not know about it
+	//
+	if v1.Type() != TypeDictionary {
+		if result, err, hooked := compareByHook(context, v2, v1); hooked {
+			if err != nil {
+				return err
+			}
+			return f(-result)
+		}
+	}
+
 	c1, comparable := v1.(ComparableValue)
 	if !comparable {
 		return NewErrorValue(fmt.Sprintf("invalid comparison, expected comparable values instead of %v and %v", v1, v2))
@@ -1027,6 +1207,12 @@ func eq() NamedValue {
 			v1 := EvalArgument(context, arguments[0])
 			v2 := EvalArgument(context, arguments[1])
 
+			// dictionaries can decide themselves what they equal
+			//
+			if result := equalsByHook(context, v1, v2); result != nil {
+				return result
+			}
+
 			// first try to compare the two values
 			//
 			if result := compareValues(context, v1, v2, func(result int) Value {
@@ -1073,6 +1259,15 @@ func ne() NamedValue {
 		v1 := EvalArgument(context, arguments[0])
 		v2 := EvalArgument(context, arguments[1])
 
+		// dictionaries can decide themselves what they equal
+		//
+		if result := equalsByHook(context, v1, v2); result != nil {
+			if result.Type() == TypeError {
+				return result
+			}
+			return TrueOrFalse(result == False)
+		}
+
 		// first try to compare the two values
 		//
 		if result := compareValues(context, v1, v2, func(result int) Value {
@@ -1356,19 +1551,28 @@ func not() NamedValue {
 	})
 }
 
-func arithmeticOperation(context RunContext, arguments []Argument, name string, f func(Value, Value) Value) Value {
+func arithmeticOperation(context RunContext, arguments []Argument, name string, commutative bool, f func(Value, Value) Value) Value {
 	_, err := CheckArguments(arguments, 2, 2, name, "<value> <value>")
 	if err != nil {
 		return err
 	}
 
 	v1 := EvalArgument(context, arguments[0])
+	v2 := EvalArgument(context, arguments[1])
+
+	// dictionaries can implement arithmetic with hooks like _plus
+	//
+	if v1.Type() == TypeDictionary || v2.Type() == TypeDictionary {
+		if result, hooked := arithmeticByHook(context, name, commutative, v1, v2); hooked {
+			return result
+		}
+	}
+
 	_, ok1 := v1.(MathValue)
 	if !ok1 {
 		return NewErrorValue(fmt.Sprintf("%s does not support %v", name, v1))
 	}
 
-	v2 := EvalArgument(context, arguments[1])
 	_, ok2 := v2.(MathValue)
 	if !ok2 {
 		return NewErrorValue(fmt.Sprintf("%s does not support %v", name, v2))
@@ -1391,7 +1595,7 @@ func plus() NamedValue {
 
 		func(context RunContext, arguments []Argument) Value {
 
-			return arithmeticOperation(context, arguments, "plus", func(v1 Value, v2 Value) Value {
+			return arithmeticOperation(context, arguments, "plus", true, func(v1 Value, v2 Value) Value {
 				return v1.(MathValue).Plus(v2)
 			})
 
@@ -1412,7 +1616,7 @@ func minus() NamedValue {
 
 		func(context RunContext, arguments []Argument) Value {
 
-			return arithmeticOperation(context, arguments, "minus", func(v1 Value, v2 Value) Value {
+			return arithmeticOperation(context, arguments, "minus", false, func(v1 Value, v2 Value) Value {
 				return v1.(MathValue).Minus(v2)
 			})
 
@@ -1433,7 +1637,7 @@ func multiply() NamedValue {
 
 		func(context RunContext, arguments []Argument) Value {
 
-			return arithmeticOperation(context, arguments, "multiply", func(v1 Value, v2 Value) Value {
+			return arithmeticOperation(context, arguments, "multiply", true, func(v1 Value, v2 Value) Value {
 				return v1.(MathValue).Multiply(v2)
 			})
 
@@ -1458,7 +1662,7 @@ func divide() NamedValue {
 
 		func(context RunContext, arguments []Argument) Value {
 
-			return arithmeticOperation(context, arguments, "divide", func(v1 Value, v2 Value) Value {
+			return arithmeticOperation(context, arguments, "divide", false, func(v1 Value, v2 Value) Value {
 				return v1.(MathValue).Divide(v2)
 			})
 
@@ -1483,7 +1687,7 @@ func modulo() NamedValue {
 
 		func(context RunContext, arguments []Argument) Value {
 
-			return arithmeticOperation(context, arguments, "modulo", func(v1 Value, v2 Value) Value {
+			return arithmeticOperation(context, arguments, "modulo", false, func(v1 Value, v2 Value) Value {
 				return v1.(MathValue).Modulo(v2)
 			})
 
@@ -1538,7 +1742,13 @@ func _error() NamedValue {
 		> error "chipotle not hot enought error"
 		will result in an error
 
-		Note, given message value is evaluated to a string`,
+		Note, given message value is evaluated to a string
+
+		Errors can be inspected like dictionaries with message, fatal, file, line and
+		trace (where the error occurred followed by the calls to functions, scripts
+		and modules it passed while propagating)
+		> result: (error "chipotle not hot enought error")
+		> puts $result.message`,
 
 		func(context RunContext, arguments []Argument) Value {
 
@@ -1632,15 +1842,19 @@ func help() NamedValue {
 				result = append(result, NewIdentifier(key))
 			} else if value.Type() == TypeDictionary {
 
+				// a dictionary copies its values on every call to Internal
+				//
+				subValues := value.Internal().(map[string]Value)
+
 				subkeys := []string{}
-				for k := range value.Internal().(map[string]Value) {
+				for k := range subValues {
 					subkeys = append(subkeys, k)
 				}
 
 				sort.Strings(subkeys)
 
 				for _, subkey := range subkeys {
-					subValue, _ := value.Internal().(map[string]Value)[subkey]
+					subValue := subValues[subkey]
 					if subValue.Type() == TypeGoFunction {
 						result = append(result, NewNameSpacedIdentifier([]string{key, subkey}))
 					}
@@ -1666,6 +1880,13 @@ func _close() NamedValue {
 			}
 
 			value := EvalArgument(context, arguments[0])
+			if result, hooked := runValueHook(context, value, hookClose); hooked {
+				if result.Type() == TypeError {
+					return result
+				}
+				return value
+			}
+
 			if closeable, ok := value.(CloseableValue); ok {
 				closeable.Close()
 			} else {
@@ -1697,6 +1918,13 @@ func _len() NamedValue {
 			}
 
 			value := EvalArgument(context, arguments[0])
+			if result, hooked := runValueHook(context, value, hookLen); hooked {
+				if result.Type() != TypeError && result.Type() != TypeInteger {
+					return NewErrorValue("found _len did not return an integer")
+				}
+				return result
+			}
+
 			if withLength, ok := value.(ValueWithLength); ok {
 				return withLength.Length()
 			}
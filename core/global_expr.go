@@ -0,0 +1,446 @@
+package elmo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// exprNode is a parsed part of an infix expression
+//
+type exprNode interface {
+	eval(context RunContext) Value
+}
+
+type exprLiteral struct {
+	value Value
+}
+
+type exprVariable struct {
+	path []string
+}
+
+type exprCall struct {
+	path      []string
+	arguments []exprNode
+}
+
+type exprUnary struct {
+	operator string
+	operand  exprNode
+}
+
+type exprBinary struct {
+	operator    string
+	left, right exprNode
+}
+
+// exprOperators maps infix operators on the functions implementing them, so
+// expressions behave exactly like their prefix counterparts and work on all
+// values implementing MathValue and ComparableValue
+//
+var exprOperators = map[string]NamedValue{
+	"+":  plus(),
+	"-":  minus(),
+	"*":  multiply(),
+	"/":  divide(),
+	"%":  modulo(),
+	"==": eq(),
+	"!=": ne(),
+	"<":  lt(),
+	"<=": lte(),
+	">":  gt(),
+	">=": gte(),
+	"!":  not()}
+
+var exprPrecedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6}
+
+// exprCacheSize is the maximum number of parsed expressions that are cached
+//
+const exprCacheSize = 256
+
+// exprCache caches parsed expressions, so expressions used within loops or
+// functions are only parsed once
+//
+var exprCache = NewLRUCache(exprCacheSize)
+
+func (literal *exprLiteral) eval(context RunContext) Value {
+	return literal.value
+}
+
+func resolvePath(context RunContext, path []string) (DictionaryValue, Value, bool) {
+	return NewNameSpacedIdentifier(path).(IdentifierValue).LookUp(context)
+}
+
+func (variable *exprVariable) eval(context RunContext) Value {
+	_, value, found := resolvePath(context, variable.path)
+	if !found {
+		return NewErrorValue(fmt.Sprintf("could not resolve %s", strings.Join(variable.path, ".")))
+	}
+	return value
+}
+
+func (call *exprCall) eval(context RunContext) Value {
+	name := strings.Join(call.path, ".")
+
+	inDict, value, found := resolvePath(context, call.path)
+	if !found {
+		return NewErrorValue(fmt.Sprintf("call to undefined \"%s\"", name))
+	}
+
+	runnable, isRunnable := value.(Runnable)
+	if !isRunnable {
+		return NewErrorValue(fmt.Sprintf("%s can not be called", name))
+	}
+
+	arguments := make([]Argument, len(call.arguments))
+	for i, argument := range call.arguments {
+		evaluated := argument.eval(context)
+		if evaluated.Type() == TypeError {
+			return evaluated
+		}
+		arguments[i] = NewDynamicArgument(evaluated)
+	}
+
+	if inDict != nil {
+		defer bindMethod(context, inDict, call.path[len(call.path)-1], value)()
+	}
+
+	return runnable.Run(context, arguments)
+}
+
+func (unary *exprUnary) eval(context RunContext) Value {
+	operand := unary.operand.eval(context)
+	if operand.Type() == TypeError {
+		return operand
+	}
+
+	if unary.operator == "-" {
+		math, isMath := operand.(MathValue)
+		if !isMath {
+			return NewErrorValue(fmt.Sprintf("can not negate %v", operand))
+		}
+		return math.Multiply(NewIntegerLiteral(-1))
+	}
+
+	return exprOperators[unary.operator].(Runnable).Run(context, []Argument{NewDynamicArgument(operand)})
+}
+
+func (binary *exprBinary) eval(context RunContext) Value {
+	left := binary.left.eval(context)
+	if left.Type() == TypeError {
+		return left
+	}
+
+	// logical operators only evaluate their right operand when needed
+	//
+	if binary.operator == "&&" || binary.operator == "||" {
+		if left.Type() != TypeBoolean {
+			return NewErrorValue(fmt.Sprintf("%s expects boolean values, not %v", binary.operator, left))
+		}
+		if (left == True) == (binary.operator == "||") {
+			return left
+		}
+		right := binary.right.eval(context)
+		if right.Type() != TypeBoolean && right.Type() != TypeError {
+			return NewErrorValue(fmt.Sprintf("%s expects boolean values, not %v", binary.operator, right))
+		}
+		return right
+	}
+
+	right := binary.right.eval(context)
+	if right.Type() == TypeError {
+		return right
+	}
+
+	return exprOperators[binary.operator].(Runnable).Run(context, []Argument{NewDynamicArgument(left), NewDynamicArgument(right)})
+}
+
+type exprToken struct {
+	kind     rune
+	text     string
+	position int
+}
+
+const (
+	exprEnd      = 'e'
+	exprNumber   = 'n'
+	exprString   = 's'
+	exprName     = 'i'
+	exprOperator = 'o'
+	exprPunct    = 'p'
+)
+
+func isExprNameChar(r rune, first bool) bool {
+	return r == '_' || unicode.IsLetter(r) || (!first && (unicode.IsDigit(r) || r == '.'))
+}
+
+func tokenizeExpr(source string) ([]exprToken, error) {
+	runes := []rune(source)
+	tokens := []exprToken{}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case unicode.IsDigit(r):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: exprNumber, text: string(runes[start:i]), position: start})
+		case r == '\'':
+			i++
+			for i < len(runes) && runes[i] != '\'' {
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			i++
+			tokens = append(tokens, exprToken{kind: exprString, text: string(runes[start+1 : i-1]), position: start})
+		case r == '$' || isExprNameChar(r, true):
+			if r == '$' {
+				i++
+				start = i
+			}
+			for i < len(runes) && isExprNameChar(runes[i], i == start) {
+				i++
+			}
+			if i == start {
+				return nil, fmt.Errorf("expected a name after $ at position %d", start)
+			}
+			tokens = append(tokens, exprToken{kind: exprName, text: string(runes[start:i]), position: start})
+		case strings.ContainsRune("(),", r):
+			i++
+			tokens = append(tokens, exprToken{kind: exprPunct, text: string(r), position: start})
+		default:
+			if i+1 < len(runes) {
+				if _, isOperator := exprPrecedence[string(runes[i:i+2])]; isOperator {
+					i = i + 2
+					tokens = append(tokens, exprToken{kind: exprOperator, text: string(runes[start:i]), position: start})
+					continue
+				}
+			}
+			if _, isOperator := exprPrecedence[string(r)]; !isOperator && r != '!' {
+				return nil, fmt.Errorf("unexpected %q at position %d", r, start)
+			}
+			i++
+			tokens = append(tokens, exprToken{kind: exprOperator, text: string(r), position: start})
+		}
+	}
+
+	return append(tokens, exprToken{kind: exprEnd, position: len(runes)}), nil
+}
+
+type exprParser struct {
+	tokens  []exprToken
+	current int
+}
+
+func (parser *exprParser) peek() exprToken {
+	return parser.tokens[parser.current]
+}
+
+func (parser *exprParser) next() exprToken {
+	token := parser.tokens[parser.current]
+	if token.kind != exprEnd {
+		parser.current++
+	}
+	return token
+}
+
+func (parser *exprParser) expect(text string) error {
+	token := parser.next()
+	if token.kind != exprPunct || token.text != text {
+		return fmt.Errorf("expected %s at position %d", text, token.position)
+	}
+	return nil
+}
+
+// parseExpression parses binary operations using precedence climbing
+//
+func (parser *exprParser) parseExpression(minPrecedence int) (exprNode, error) {
+	left, err := parser.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		token := parser.peek()
+		precedence, isBinary := exprPrecedence[token.text]
+		if token.kind != exprOperator || !isBinary || precedence < minPrecedence {
+			return left, nil
+		}
+		parser.next()
+
+		right, err := parser.parseExpression(precedence + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &exprBinary{operator: token.text, left: left, right: right}
+	}
+}
+
+func (parser *exprParser) parseUnary() (exprNode, error) {
+	token := parser.peek()
+	if token.kind == exprOperator && (token.text == "-" || token.text == "!") {
+		parser.next()
+		operand, err := parser.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &exprUnary{operator: token.text, operand: operand}, nil
+	}
+	return parser.parsePrimary()
+}
+
+func (parser *exprParser) parsePrimary() (exprNode, error) {
+	token := parser.next()
+
+	switch token.kind {
+	case exprNumber:
+		if i, err := strconv.ParseInt(token.text, 10, 64); err == nil {
+			return &exprLiteral{value: NewIntegerLiteral(i)}, nil
+		}
+		if i, err := NewIntegerFromString(token.text); err == nil {
+			return &exprLiteral{value: i}, nil
+		}
+		f, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s at position %d", token.text, token.position)
+		}
+		return &exprLiteral{value: NewFloatLiteral(f)}, nil
+	case exprString:
+		return &exprLiteral{value: NewStringLiteral(token.text)}, nil
+	case exprName:
+		switch token.text {
+		case "true":
+			return &exprLiteral{value: True}, nil
+		case "false":
+			return &exprLiteral{value: False}, nil
+		case "nil":
+			return &exprLiteral{value: Nothing}, nil
+		}
+
+		path := strings.Split(token.text, ".")
+		if next := parser.peek(); next.kind != exprPunct || next.text != "(" {
+			return &exprVariable{path: path}, nil
+		}
+		parser.next()
+
+		call := &exprCall{path: path}
+		if next := parser.peek(); next.kind == exprPunct && next.text == ")" {
+			parser.next()
+			return call, nil
+		}
+		for {
+			argument, err := parser.parseExpression(1)
+			if err != nil {
+				return nil, err
+			}
+			call.arguments = append(call.arguments, argument)
+			if next := parser.peek(); next.kind == exprPunct && next.text == "," {
+				parser.next()
+				continue
+			}
+			return call, parser.expect(")")
+		}
+	case exprPunct:
+		if token.text == "(" {
+			node, err := parser.parseExpression(1)
+			if err != nil {
+				return nil, err
+			}
+			return node, parser.expect(")")
+		}
+	case exprEnd:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	return nil, fmt.Errorf("unexpected %s at position %d", token.text, token.position)
+}
+
+// parseExpr parses an infix expression into a tree of nodes that can be evaluated
+//
+func parseExpr(source string) (exprNode, ErrorValue) {
+	if cached, found := exprCache.Get(source); found {
+		return cached.(exprNode), nil
+	}
+
+	tokens, err := tokenizeExpr(source)
+	if err != nil {
+		return nil, NewErrorValue(fmt.Sprintf("invalid expression \"%s\": %v", source, err))
+	}
+
+	parser := &exprParser{tokens: tokens}
+	node, err := parser.parseExpression(1)
+	if err == nil && parser.peek().kind != exprEnd {
+		err = fmt.Errorf("unexpected %s at position %d", parser.peek().text, parser.peek().position)
+	}
+	if err != nil {
+		return nil, NewErrorValue(fmt.Sprintf("invalid expression \"%s\": %v", source, err))
+	}
+
+	exprCache.Put(source, node)
+	return node, nil
+}
+
+func expr() NamedValue {
+	return NewGoFunctionWithHelp("expr", `Evaluates an infix expression
+		Usage: expr <string>
+		Returns: the value of the expression
+
+		Expressions support the infix operators + - * / % == != < <= > >= && ||
+		and the prefix operators - and !, with the usual precedence. Parentheses
+		can be used for grouping. Operators behave like their prefix function
+		counterparts (plus, minus, eq, gt, ...) so they work on all values that
+		support these functions.
+
+		Variables can be used by name, with or without $. Functions are called
+		using name(argument, ...) where arguments are expressions as well.
+		Strings are written between single quotes.
+
+		Examples:
+
+		> a: 3
+		> expr "a * 2 + 1"
+		will result in 7
+		> expr "($a + 1) * 2 >= 8 && !false"
+		will result in true
+		> square: (func x { multiply $x $x })
+		> expr "square(a - 1) % 3"
+		will result in 1`,
+
+		func(context RunContext, arguments []Argument) Value {
+
+			if _, err := CheckArguments(arguments, 1, 1, "expr", "<string>"); err != nil {
+				return err
+			}
+
+			source := EvalArgument(context, arguments[0])
+			if source.Type() == TypeError {
+				return source
+			}
+			if source.Type() != TypeString {
+				return NewErrorValue(fmt.Sprintf("invalid call to expr, expected a string instead of %v", source))
+			}
+
+			node, err := parseExpr(source.String())
+			if err != nil {
+				return err
+			}
+
+			return node.eval(context)
+		})
+}
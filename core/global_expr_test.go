@@ -0,0 +1,107 @@
+package elmo
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestExpr(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`expr`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`expr 3`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`expr "1 + 2 * 3"`, ExpectValue(t, NewIntegerLiteral(7)))
+
+	ParseTestAndRunBlock(t,
+		`expr "(1 + 2) * 3"`, ExpectValue(t, NewIntegerLiteral(9)))
+
+	ParseTestAndRunBlock(t,
+		`expr "10 - 4 - 3"`, ExpectValue(t, NewIntegerLiteral(3)))
+
+	ParseTestAndRunBlock(t,
+		`expr "7 % 4 + 8 / 2"`, ExpectValue(t, NewIntegerLiteral(7)))
+
+	ParseTestAndRunBlock(t,
+		`expr "1.5 * 2"`, ExpectValue(t, NewFloatLiteral(3.0)))
+
+	ParseTestAndRunBlock(t,
+		`expr "-2 * -(1 + 2)"`, ExpectValue(t, NewIntegerLiteral(6)))
+
+	ParseTestAndRunBlock(t,
+		`expr "'chipotle' == 'chipotle'"`, ExpectValue(t, True))
+
+	ParseTestAndRunBlock(t,
+		`expr "1 + 1 == 2 && 3 > 2 && !(2 >= 3) && 1 != 2 && 1 <= 1 && 1 < 2"`, ExpectValue(t, True))
+
+	ParseTestAndRunBlock(t,
+		`expr "false || 1 > 2 || true"`, ExpectValue(t, True))
+
+	ParseTestAndRunBlock(t,
+		`expr "1 / 0"`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`expr "1 +"`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`expr "(1 + 2"`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`expr "1 # 2"`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`expr "1 && true"`, ExpectErrorValueAt(t, 1))
+}
+
+func TestExprWithVariablesAndCalls(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`a: 3
+		 expr "a * 2 + $a"`, ExpectValue(t, NewIntegerLiteral(9)))
+
+	ParseTestAndRunBlock(t,
+		`expr "chipotle + 1"`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`pepper: {hotness: 5}
+		 expr "pepper.hotness * 2"`, ExpectValue(t, NewIntegerLiteral(10)))
+
+	ParseTestAndRunBlock(t,
+		`square: (func x { multiply $x $x })
+		 a: 3
+		 expr "square(a - 1) + square(2)"`, ExpectValue(t, NewIntegerLiteral(8)))
+
+	ParseTestAndRunBlock(t,
+		`l: [1 2 3]
+		 expr "l(2) * 2"`, ExpectValue(t, NewIntegerLiteral(6)))
+
+	ParseTestAndRunBlock(t,
+		`pepper: {hotness: 5; hotter: (func by { plus $this.hotness $by })}
+		 expr "pepper.hotter(2 * 2)"`, ExpectValue(t, NewIntegerLiteral(9)))
+
+	// right operand of logical operators is only evaluated when needed
+	//
+	ParseTestAndRunBlock(t,
+		`expr "false && chipotle()"`, ExpectValue(t, False))
+
+	ParseTestAndRunBlock(t,
+		`expr "true && chipotle()"`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`f: (func n { expr "n * 2" })
+		 list: (func { return [(f 1) (f 2)] })
+		 list`, ExpectValue(t, ParseAndRun(NewGlobalContext(), "[2 4]")))
+}
+
+func TestExprCacheIsBounded(t *testing.T) {
+
+	for i := 0; i < 2*exprCacheSize; i++ {
+		ParseTestAndRunBlock(t, fmt.Sprintf(`expr "%d + 1"`, i), ExpectValue(t, NewIntegerLiteral(int64(i+1))))
+	}
+	if exprCache.Len() > exprCacheSize {
+		t.Errorf("expected at most %d cached expressions, found %d", exprCacheSize, exprCache.Len())
+	}
+}
@@ -1,28 +1,32 @@
 package elmo
 
 import (
+	"bufio"
 	"io/ioutil"
 	"os"
-	"path/filepath"
 )
 
-func newFileDictionary(path string) Value {
-	info, err := os.Stat(path)
+// newFileDictionary creates a dictionary describing a file. Within a
+// sandbox, given path is relative to the file root of the sandbox
+//
+func newFileDictionary(context RunContext, path string) Value {
+
+	realPath, absPath, jailErr := fileSystemPath(context, path)
+	if jailErr != nil {
+		return jailErr
+	}
+
+	info, err := os.Stat(realPath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return addFunctionsToFile(NewDictionaryValue(nil, map[string]Value{
 				"exists": False,
-				"path":   NewStringLiteral(path)}))
+				"path":   NewStringLiteral(path)}).(DictionaryValue), path, realPath)
 
 		}
 		return NewErrorValue(err.Error())
 	}
 
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		return NewErrorValue(err.Error())
-	}
-
 	return addFunctionsToFile(NewDictionaryValue(nil, map[string]Value{
 		"exists":  True,
 		"name":    NewStringLiteral(info.Name()),
@@ -30,7 +34,7 @@ func newFileDictionary(path string) Value {
 		"absPath": NewStringLiteral(absPath),
 		"mode":    NewStringLiteral(info.Mode().String()),
 		"size":    NewIntegerLiteral(info.Size()),
-		"isDir":   TrueOrFalse(info.IsDir())}).(DictionaryValue))
+		"isDir":   TrueOrFalse(info.IsDir())}).(DictionaryValue), path, realPath)
 }
 
 // file return the file command which creates a dictionary with file info and
@@ -49,6 +53,7 @@ func file() NamedValue {
 		  size (integer)
 		  binary (function)
 		  string (function)
+		  lines (function)
 		  write (function)
 		  append (function)
 		}
@@ -64,7 +69,7 @@ func file() NamedValue {
 				return path
 			}
 
-			return newFileDictionary(path.String())
+			return newFileDictionary(context, path.String())
 		})
 }
 
@@ -97,14 +102,28 @@ func tempFile() NamedValue {
 				return NewErrorValue("tmpFile expects a block of elmo code as last parameter")
 			}
 
-			tmpFile, err := ioutil.TempFile("", "elmo")
+			dir, jailErr := tempDir(context)
+			if jailErr != nil {
+				return jailErr
+			}
+
+			tmpFile, err := ioutil.TempFile(dir, "elmo")
 			if err != nil {
 				return NewErrorValue(err.Error())
 			}
+			tmpFile.Close()
 
 			defer os.Remove(tmpFile.Name())
 
-			file := newFileDictionary(tmpFile.Name())
+			path := tmpFile.Name()
+			if policy := context.Policy(); policy != nil {
+				path = policy.virtual(path)
+			}
+
+			file := newFileDictionary(context, path)
+			if file.Type() == TypeError {
+				return file
+			}
 
 			subContext := context.CreateSubContext()
 			subContext.Set(name, file)
@@ -114,22 +133,26 @@ func tempFile() NamedValue {
 		})
 }
 
-func addFunctionsToFile(file DictionaryValue) DictionaryValue {
-	file.Set(NewIdentifier("binary"), fileBinaryContent(file))
-	file.Set(NewIdentifier("string"), fileStringContent(file))
-	file.Set(NewIdentifier("write"), fileWrite(file))
-	file.Set(NewIdentifier("append"), fileAppend(file))
+// addFunctionsToFile adds functions to a file dictionary. These functions
+// operate on the path they were created with, so changing the dictionary
+// can not be used to access other files
+//
+func addFunctionsToFile(file DictionaryValue, path string, realPath string) DictionaryValue {
+	file.Set(NewIdentifier("binary"), fileBinaryContent(file, realPath))
+	file.Set(NewIdentifier("string"), fileStringContent(file, realPath))
+	file.Set(NewIdentifier("lines"), fileLines(file, realPath))
+	file.Set(NewIdentifier("write"), fileWrite(path, realPath))
+	file.Set(NewIdentifier("append"), fileAppend(path, realPath))
 	return file
 }
 
-func getFileContent(file DictionaryValue, transform func([]byte) Value) Value {
+func getFileContent(file DictionaryValue, realPath string, transform func([]byte) Value) Value {
 	isDir, found := file.Resolve("isDir")
 	if found && isDir.Internal().(bool) {
 		return NewErrorValue("can not read the content of a directory")
 	}
 
-	path, found := file.Resolve("path")
-	content, err := ioutil.ReadFile(path.String())
+	content, err := ioutil.ReadFile(realPath)
 	if err != nil {
 		return NewErrorValue(err.Error())
 	}
@@ -137,67 +160,91 @@ func getFileContent(file DictionaryValue, transform func([]byte) Value) Value {
 
 }
 
-func fileBinaryContent(file DictionaryValue) NamedValue {
+func fileBinaryContent(file DictionaryValue, realPath string) NamedValue {
 	return NewGoFunctionWithHelp("binary", `Returns the binary content of a file
 		Usage: file.binary 
 		Returns: file content as a binary value`,
 
 		func(context RunContext, arguments []Argument) Value {
-			return getFileContent(file, func(content []byte) Value {
+			return getFileContent(file, realPath, func(content []byte) Value {
 				return NewBinaryValue(content)
 			})
 		})
 }
 
-func fileStringContent(file DictionaryValue) NamedValue {
+func fileStringContent(file DictionaryValue, realPath string) NamedValue {
 	return NewGoFunctionWithHelp("string", `Returns the content of a file as string
 		Usage: file.string
 		Returns: file content as a string value`,
 
 		func(context RunContext, arguments []Argument) Value {
-			return getFileContent(file, func(content []byte) Value {
+			return getFileContent(file, realPath, func(content []byte) Value {
 				return NewStringLiteral(string(content))
 			})
 		})
 }
 
-func fileWrite(file DictionaryValue) NamedValue {
+func fileLines(file DictionaryValue, realPath string) NamedValue {
+	return NewGoFunctionWithHelp("lines", `Returns the lines of a file
+		Usage: file.lines
+		Returns: an iterable producing every line of the file as string value
+
+		The file is read while its lines are iterated, so it is never kept
+		in memory as a whole`,
+
+		func(context RunContext, arguments []Argument) Value {
+			isDir, found := file.Resolve("isDir")
+			if found && isDir.Internal().(bool) {
+				return NewErrorValue("can not read the lines of a directory")
+			}
+
+			return NewIterableValue(func() Iterator {
+				f, err := os.Open(realPath)
+				if err != nil {
+					return NewErrorIterator(NewErrorValue(err.Error()))
+				}
+
+				scanner := bufio.NewScanner(f)
+				return NewIterator(func() (Value, bool) {
+					if scanner.Scan() {
+						return NewStringLiteral(scanner.Text()), true
+					}
+					if err := scanner.Err(); err != nil {
+						return NewErrorValue(err.Error()), true
+					}
+					return nil, false
+				}, func() { f.Close() })
+			})
+		})
+}
+
+func fileWrite(path string, realPath string) NamedValue {
 	return NewGoFunctionWithHelp("write", `Writes content to a file
 		Usage: file.write <value> 
 		Returns: the file itself`,
 
 		func(context RunContext, arguments []Argument) Value {
 
-			path, found := file.Resolve("path")
-			if !found {
-				return NewErrorValue("missing path in file dictionary")
-			}
-
 			buf := EvalArguments2Buffer(context, arguments)
 
-			if err := ioutil.WriteFile(path.String(), buf.Bytes(), 0644); err != nil {
+			if err := ioutil.WriteFile(realPath, buf.Bytes(), 0644); err != nil {
 				return NewErrorValue(err.Error())
 			}
 
-			return newFileDictionary(path.String())
+			return newFileDictionary(context, path)
 		})
 }
 
-func fileAppend(file DictionaryValue) NamedValue {
+func fileAppend(path string, realPath string) NamedValue {
 	return NewGoFunctionWithHelp("append", `Append content to a file
 		Usage: file.append <value> 
 		Returns: the file itself`,
 
 		func(context RunContext, arguments []Argument) Value {
 
-			path, found := file.Resolve("path")
-			if !found {
-				return NewErrorValue("missing path in file dictionary")
-			}
-
 			buf := EvalArguments2Buffer(context, arguments)
 
-			f, err := os.OpenFile(path.String(), os.O_APPEND|os.O_WRONLY, 0644)
+			f, err := os.OpenFile(realPath, os.O_APPEND|os.O_WRONLY, 0644)
 			if err != nil {
 				return NewErrorValue(err.Error())
 			}
@@ -208,6 +255,6 @@ func fileAppend(file DictionaryValue) NamedValue {
 				panic(err)
 			}
 
-			return newFileDictionary(path.String())
+			return newFileDictionary(context, path)
 		})
 }
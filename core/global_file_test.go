@@ -42,6 +42,15 @@ func TestFile(t *testing.T) {
 
 }
 
+func TestFileLines(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`((file "file_testdata/peppers.txt") lines)`, ExpectIterated(t, ParseAndRun(NewGlobalContext(), `["chipotle,jalapeno,habanero"]`)))
+
+	ParseTestAndRunBlock(t,
+		`((file "file_testdata") lines)`, ExpectErrorValueAt(t, 1))
+}
+
 func TestTempFile(t *testing.T) {
 	ParseTestAndRunBlock(t,
 		`f: (file (tempFile tmp { return $tmp.absPath }))
@@ -1,47 +1,250 @@
 package elmo
 
-// func arguments can have default values. This is done by giving the
-// argument a name that ends with an '?' and by using the next argument
-// as default value. So it's possible to create a function like:
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parameter describes a single parameter of a user defined function
 //
-// greet: (func name greeting?"Hello" { echo "\{$greeting} \{$name}"})
+type parameter struct {
+	name         string
+	typeName     string
+	optional     bool
+	rest         bool
+	defaultValue Value
+}
+
+func (parameter *parameter) String() string {
+	s := parameter.name
+	if parameter.typeName != "" {
+		s = s + ":" + parameter.typeName
+	}
+	if parameter.rest {
+		return s + "..."
+	}
+	if parameter.optional {
+		return s + "?" + defaultUsage(parameter.defaultValue)
+	}
+	return s
+}
+
+// defaultUsage shows the default value of an optional parameter the way
+// it is declared, so strings are quoted
 //
-// in this case ["name", "greeting"] and {"greeting":"Hello"} are returned
+func defaultUsage(value Value) string {
+	if value == nil {
+		return ""
+	}
+	if value.Type() == TypeString {
+		return strconv.Quote(value.String())
+	}
+	return value.String()
+}
+
+// accepts checks if a value can be bound to this parameter
 //
-func extractArgNamesAndDefaultValues(argNameValues []Value) ([]string, map[string]Value) {
-	useArgNames := make([]string, 0, len(argNameValues))
-	defaultValues := make(map[string]Value, 0)
+func (parameter *parameter) accepts(value Value) bool {
+	return parameter.typeName == "" || parameter.typeName == "any" || value.Info().Name().String() == parameter.typeName
+}
 
-	useNextAsDefault := false
-	name := ""
+// func parameters can have a type, can have a default value and the last one
+// can collect all remaining arguments. Types are given after a colon, default
+// values by giving the parameter a name that ends with an '?' and using the next
+// argument as default value and the rest parameter by ending its name
+// with '...'. So it's possible to create a function like:
+//
+// greet: (func name:string greeting?"Hello" others... { echo "\{$greeting} \{$name}"})
+//
+func extractParameters(argNameValues []Value) ([]*parameter, ErrorValue) {
+	parameters := make([]*parameter, 0, len(argNameValues))
 
+	var previous *parameter
 	for _, nameValue := range argNameValues {
-		if !useNextAsDefault {
-			name = nameValue.String()
-			if name[len(name)-1] == '?' {
-				useNextAsDefault = true
-				name = name[:len(name)-1]
+		if previous != nil && previous.optional && previous.defaultValue == nil {
+			// argument is default value for previous parameter
+			previous.defaultValue = nameValue
+			continue
+		}
+
+		if previous != nil && previous.rest {
+			return nil, NewErrorValue(fmt.Sprintf("rest parameter %s must be the last parameter", previous.name))
+		}
+
+		current, err := newParameter(nameValue.String())
+		if err != nil {
+			return nil, err
+		}
+
+		parameters = append(parameters, current)
+		previous = current
+	}
+
+	return parameters, nil
+}
+
+// newParameter parses a single (annotated) parameter name
+//
+func newParameter(annotated string) (*parameter, ErrorValue) {
+	name := annotated
+	current := &parameter{}
+
+	if strings.HasSuffix(name, "?") {
+		current.optional = true
+		name = name[:len(name)-1]
+	}
+	if strings.HasSuffix(name, "...") {
+		current.rest = true
+		name = name[:len(name)-3]
+	}
+	if colon := strings.Index(name, ":"); colon >= 0 {
+		current.typeName = name[colon+1:]
+		name = name[:colon]
+	}
+	if name == "" || (current.optional && current.rest) {
+		return nil, NewErrorValue(fmt.Sprintf("invalid parameter %s", annotated))
+	}
+
+	current.name = name
+	return current, nil
+}
+
+// declarationArguments returns all arguments of a line that declares
+// parameters, like a field of a record or a case of a match. A line that
+// starts with name:type is parsed as an assignment so its first two
+// arguments are joined into a single parameter again
+//
+func declarationArguments(line *call) []Argument {
+	arguments := append([]Argument{line.firstArgument}, line.arguments...)
+	if !isAssignmentSyntax(line) || len(line.arguments) < 2 {
+		return arguments
+	}
+
+	name, isName := line.arguments[0].(*argument)
+	annotation, isAnnotation := line.arguments[1].(*argument)
+	if !isName || !isAnnotation || !isSimpleIdentifier(name.value) || !isSimpleIdentifier(annotation.value) {
+		return arguments
+	}
+
+	parameter := &argument{astNode: astNode{meta: name.meta, node: name.node, endNode: annotation.node},
+		value: NewIdentifier(name.value.String() + ":" + annotation.value.String())}
+
+	return append([]Argument{parameter}, line.arguments[2:]...)
+}
+
+func isSimpleIdentifier(value Value) bool {
+	id, isIdentifier := value.(*identifier)
+	return isIdentifier && len(id.value) == 1
+}
+
+func parameterNames(parameters []*parameter) []string {
+	names := make([]string, len(parameters))
+	for i, parameter := range parameters {
+		names[i] = parameter.name
+	}
+	return names
+}
+
+func parametersUsage(parameters []*parameter) string {
+	usage := make([]string, len(parameters))
+	for i, parameter := range parameters {
+		usage[i] = parameter.String()
+	}
+	return strings.Join(usage, " ")
+}
+
+// bindArguments evaluates the arguments of a call to a user defined function
+// and binds them to the parameters of the function. Keyword arguments are bound
+// to the parameter with the same name. Positional arguments without a
+// parameter are ignored so functions can be used as callbacks that are
+// only interested in the first arguments they receive
+//
+func bindArguments(fname string, parameters []*parameter, context RunContext, subContext RunContext, arguments []Argument) ErrorValue {
+
+	positional, keywords := SplitArguments(arguments)
+
+	for i, parameter := range parameters {
+		keyword, hasKeyword := keywords[parameter.name]
+		if hasKeyword {
+			delete(keywords, parameter.name)
+		}
+
+		if parameter.rest {
+			if hasKeyword {
+				return NewErrorValue(fmt.Sprintf("rest argument %s for %s can not be passed by name", parameter.name, fname))
+			}
+			values := make([]Value, 0, len(positional))
+			for _, argument := range positional[minInt(i, len(positional)):] {
+				value := EvalArgument(context, argument)
+				if !parameter.accepts(value) {
+					return invalidArgument(fname, parameter, value)
+				}
+				values = append(values, value)
 			}
+			subContext.Set(parameter.name, NewListValue(values))
+			break
+		}
 
-			useArgNames = append(useArgNames, name)
-		} else {
-			// argument is default value for previous arg
-			defaultValues[name] = nameValue
-			useNextAsDefault = false
+		var argument Argument
+		if i < len(positional) {
+			if hasKeyword {
+				return NewErrorValue(fmt.Sprintf("argument %s for %s is passed twice", parameter.name, fname))
+			}
+			argument = positional[i]
+		} else if hasKeyword {
+			argument = keyword
 		}
+
+		if argument == nil {
+			if !parameter.optional {
+				return NewErrorValue(fmt.Sprintf("missing argument %s for %s. Usage: %s %s", parameter.name, fname, fname, parametersUsage(parameters)))
+			}
+			if parameter.defaultValue != nil {
+				subContext.Set(parameter.name, parameter.defaultValue)
+			}
+			continue
+		}
+
+		value := EvalArgument(context, argument)
+		if !parameter.accepts(value) {
+			return invalidArgument(fname, parameter, value)
+		}
+		subContext.Set(parameter.name, value)
 	}
 
-	if name != "" && useNextAsDefault {
-		useArgNames = append(useArgNames, name)
+	for keyword := range keywords {
+		return NewErrorValue(fmt.Sprintf("unknown argument %s for %s. Usage: %s %s", keyword, fname, fname, parametersUsage(parameters)))
 	}
 
-	return useArgNames, defaultValues
+	return nil
 }
 
-func createGoFunc(argNameValues []Value, evaluator func(evalContext RunContext) Value) (func(innerContext RunContext, innerArguments []Argument) Value, []string) {
+func invalidArgument(fname string, parameter *parameter, value Value) ErrorValue {
+	if value.Type() == TypeError {
+		return value.(ErrorValue)
+	}
+	return NewErrorValue(fmt.Sprintf("invalid argument %s for %s: expected %s, not %s", parameter.name, fname, parameter.typeName, value.Info().Name()))
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
 
-	useArgNames, defaultValues := extractArgNamesAndDefaultValues(argNameValues)
-	injectDefaultValues := len(defaultValues) > 0
+// createGoFunc creates the go function that binds arguments and evaluates
+// the function body. When body is a block and the vm is used, local
+// variables are stored in slots. Name is used to report invalid calls,
+// self returns the function value so methods can find their super
+//
+func createGoFunc(name func() string, self func() Value, parameters []*parameter, body Block, evaluator func(evalContext RunContext) Value) GoFunction {
+
+	var layout *slotLayout
+	if compiled, isBlock := body.(*block); isBlock {
+		layout = compiled.code.frameLayout(compiled, parameterNames(parameters))
+	}
 
 	return func(innerContext RunContext, innerArguments []Argument) Value {
 
@@ -49,28 +252,32 @@ func createGoFunc(argNameValues []Value, evaluator func(evalContext RunContext)
 		if cloneFrom.Parent() != nil {
 			cloneFrom = cloneFrom.Parent()
 		}
-		subContext := cloneFrom.CreateSubContext()
 
-		if innerContext.This() != nil {
-			subContext.Set("this", innerContext.This())
+		var subContext RunContext
+		if layout != nil && GlobalSettings().VM {
+			subContext = newFrameContext(cloneFrom, layout)
+		} else {
+			subContext = cloneFrom.CreateSubContext()
 		}
 
-		if injectDefaultValues {
-			for k, v := range defaultValues {
-				subContext.Set(k, v)
-			}
+		if err := subContext.enter(innerContext); err != nil {
+			return err
 		}
 
-		maxArgs := len(useArgNames)
-		for i, v := range innerArguments {
-			if i < maxArgs {
-				subContext.Set(useArgNames[i], EvalArgument(innerContext, v))
+		if this := innerContext.This(); this != nil {
+			subContext.Set("this", this)
+			if super := superOf(innerContext, this, self()); super != nil {
+				subContext.Set("super", super)
 			}
 		}
 
-		return evaluator(subContext)
+		if err := bindArguments(name(), parameters, innerContext, subContext, innerArguments); err != nil {
+			return err
+		}
+
+		return outsideLoop(evaluator(subContext))
 
-	}, useArgNames
+	}
 }
 
 func splitArgumentsForFunc(context RunContext, argStart int, arguments []Argument) ([]Value, Value) {
@@ -91,7 +298,14 @@ func _func() NamedValue {
 
 		When first argument is a string, this value will be used as help text
 
-		Given symbols denote function parameter names.
+		Given symbols denote function parameter names. A parameter can be
+		annotated with a type (a:int), can be optional with a default value
+		(a?3 or a:int?3) and the last parameter can collect all remaining
+		arguments into a list (a... or a:int...). Types and missing arguments
+		are checked when the function is called.
+
+		Arguments can also be passed by name (f a=3) and a list or dictionary
+		can be spread into positional or named arguments (f ...$values).
 
 		Examples:
 
@@ -99,6 +313,10 @@ func _func() NamedValue {
 		will create a function that accepts one parameter called 'a' (and does nothing)
 		> func a { return $a }
 		will create an echo function
+		> func name:string times:int?1 { ... }
+		will create a function that accepts a string and optionally an integer
+		> func first others... { ... }
+		will create a function that stores all but the first argument in others
 
 		Note, function can be used once they are assigned to a variable
 
@@ -111,39 +329,58 @@ func _func() NamedValue {
 		will result in "we need more chipotles"`,
 
 		func(context RunContext, arguments []Argument) Value {
+			return newFunction(context, "func", arguments, func(block Block) func(RunContext) Value {
+				return func(evalContext RunContext) Value {
+					return block.Run(evalContext, NoArguments)
+				}
+			})
+		})
+}
 
-			argLen := len(arguments)
-			if argLen == 0 {
-				return NewErrorValue("func expects <help>? <identifier>* {...}")
-			}
+// newFunction creates a user defined function out of the arguments of a
+// func like call. Given evaluator constructor determines what happens when
+// the function is called
+//
+func newFunction(context RunContext, fname string, arguments []Argument, evaluatorFor func(block Block) func(RunContext) Value) Value {
 
-			argStart := 0
+	argLen := len(arguments)
+	if argLen == 0 {
+		return NewErrorValue(fmt.Sprintf("%s expects <help>? <identifier>* {...}", fname))
+	}
 
-			help := ""
-			if arguments[0].Type() == TypeString {
-				if argLen == 1 {
-					return NewErrorValue("func with help should at least have a body also")
-				}
-				help = EvalArgument2String(context, arguments[0])
-				argStart = 1
-			}
+	argStart := 0
 
-			argNames, code := splitArgumentsForFunc(context, argStart, arguments)
+	help := ""
+	if arguments[0].Type() == TypeString {
+		if argLen == 1 {
+			return NewErrorValue(fmt.Sprintf("%s with help should at least have a body also", fname))
+		}
+		help = EvalArgument2String(context, arguments[0])
+		argStart = 1
+	}
 
-			if code.Type() != TypeBlock {
-				return NewErrorValue("invalid call to func, last parameter must be a block")
-			}
+	argNames, code := splitArgumentsForFunc(context, argStart, arguments)
 
-			block := code.(Block)
+	if code.Type() != TypeBlock {
+		return NewErrorValue(fmt.Sprintf("invalid call to %s, last parameter must be a block", fname))
+	}
 
-			evaluator := func(evalContext RunContext) Value {
-				return block.Run(evalContext, NoArguments)
-			}
+	parameters, err := extractParameters(argNames)
+	if err != nil {
+		return err
+	}
 
-			f, useArgNames := createGoFunc(argNames, evaluator)
-			return NewGoFunctionWithBlock("anonymous", help, f, useArgNames, code.(Block))
+	block := code.(Block)
 
-		})
+	function := &inspectableGoFunction{goFunction: goFunction{
+		baseValue: baseValue{info: typeInfoGoFunction},
+		name:      "anonymous",
+		help:      NewStringLiteral(help),
+		block:     block,
+		keywords:  parameterNames(parameters)}, argNames: parameterNames(parameters), parameters: parameters}
+	function.value = createGoFunc(function.Name, func() Value { return function }, parameters, block, evaluatorFor(block))
+
+	return function
 }
 
 func template() NamedValue {
@@ -193,8 +430,14 @@ func template() NamedValue {
 				return str.ResolveBlocks(evalContext)
 			}
 
-			f, _ := createGoFunc(argNames, evaluator)
-			return NewGoFunctionWithHelp("template", help, f)
+			parameters, err := extractParameters(argNames)
+			if err != nil {
+				return err
+			}
+
+			var function NamedValue
+			function = NewGoFunctionWithKeywords("template", help, parameterNames(parameters), createGoFunc(func() string { return "template" }, func() Value { return function }, parameters, nil, evaluator))
+			return function
 
 		})
 }
@@ -1,6 +1,10 @@
 package elmo
 
-import "testing"
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
 
 func TestUserDefinedFunctionWithoutArguments(t *testing.T) {
 
@@ -208,3 +212,206 @@ func TestFuncCallUsesCorrectScope(t *testing.T) {
 func TestLoadedTemplatesUsesCorrectContext(t *testing.T) {
 	TestMoFile(t, "loadtemplates", func(context RunContext) {})
 }
+
+func TestTypedArgumentsDoNotChangeAssignments(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`chipotle: 3
+		 sauce:chipotle
+		 $sauce`, ExpectValue(t, NewIntegerLiteral(3)))
+
+	ParseTestAndRunBlock(t,
+		`sauce:chipotle
+		 hot: (func sauce:int { return $sauce })
+		 hot 3`, ExpectValue(t, NewIntegerLiteral(3)))
+}
+
+func TestFuncWithTypedArguments(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`hot: (func name:string hotness:int { return "\{$name}:\{$hotness}" })
+		 hot "chipotle" 3`, ExpectValue(t, NewStringLiteral("chipotle:3")))
+
+	ParseTestAndRunBlock(t,
+		`hot: (func name:string hotness:int { return "\{$name}:\{$hotness}" })
+		 hot "chipotle" "very"`, ExpectErrorValueAt(t, 2))
+
+	ParseTestAndRunBlock(t,
+		`hot: (func name:string hotness:int { return "\{$name}:\{$hotness}" })
+		 hot "chipotle"`, ExpectErrorValueAt(t, 2))
+
+	ParseTestAndRunBlock(t,
+		`hot: (func name:any { return $name })
+		 hot 3`, ExpectValue(t, NewIntegerLiteral(3)))
+
+	ParseTestAndRunBlock(t,
+		`hot: (func name:string hotness:int?5 { return "\{$name}:\{$hotness}" })
+		 hot "chipotle"`, ExpectValue(t, NewStringLiteral("chipotle:5")))
+
+	ParseTestAndRunBlock(t,
+		`hot: (func name:string hotness:int?5 { return "\{$name}:\{$hotness}" })
+		 hot "chipotle" 3.5`, ExpectErrorValueAt(t, 2))
+
+	ParseTestAndRunBlock(t,
+		`hot: (func name:string hotness:int { return "\{$name}:\{$hotness}" })
+		 hot 3 4`, func(context RunContext, result Value) {
+			if result.Type() != TypeError || !strings.Contains(result.String(), "invalid argument name for hot: expected string, not int") {
+				t.Errorf("expected error naming function and parameter, found %v", result)
+			}
+		})
+}
+
+func TestFuncWithRestArgument(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`peppers: (func first others... { return $others })
+		 peppers "chipotle" "jalapeno" "habanero"`,
+		ExpectValue(t, NewListValueFromStrings([]string{"jalapeno", "habanero"})))
+
+	ParseTestAndRunBlock(t,
+		`peppers: (func first others... { return $others })
+		 peppers "chipotle"`,
+		ExpectValue(t, NewListValue([]Value{})))
+
+	ParseTestAndRunBlock(t,
+		`total: (func values:int... { return (len $values) })
+		 total 1 2 3`, ExpectValue(t, NewIntegerLiteral(3)))
+
+	ParseTestAndRunBlock(t,
+		`total: (func values:int... { return (len $values) })
+		 total 1 "2" 3`, ExpectErrorValueAt(t, 2))
+
+	ParseTestAndRunBlock(t,
+		`func others... first {}`, ExpectErrorValueAt(t, 1))
+}
+
+func TestFuncWithTypedArgumentsHasHelp(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`hot: (func name:string hotness:int?5 others... {})
+		 help hot`, ExpectValue(t, NewStringLiteral("usage: hot name:string hotness:int?5 others...")))
+
+	ParseTestAndRunBlock(t,
+		`hot: (func name greeting?"hot" rest? {})
+		 help hot`, ExpectValue(t, NewStringLiteral("usage: hot name greeting?\"hot\" rest?")))
+
+	ParseTestAndRunBlock(t,
+		`hot: (func "is it hot?" name:string {})
+		 help hot`, ExpectValue(t, NewStringLiteral("is it hot?\nusage: hot name:string")))
+
+	ParseTestAndRunBlock(t,
+		`hot: (func name {})
+		 help hot`, ExpectValue(t, NewStringLiteral("usage: hot name")))
+}
+
+func TestFuncWithKeywordArguments(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`hot: (func name:string hotness:int?5 { return "\{$name}:\{$hotness}" })
+		 hot "chipotle" hotness=3`, ExpectValue(t, NewStringLiteral("chipotle:3")))
+
+	ParseTestAndRunBlock(t,
+		`hot: (func name:string hotness:int?5 { return "\{$name}:\{$hotness}" })
+		 hot hotness=3 name="chipotle"`, ExpectValue(t, NewStringLiteral("chipotle:3")))
+
+	ParseTestAndRunBlock(t,
+		`hot: (func name:string hotness:int?5 { return "\{$name}:\{$hotness}" })
+		 hot "chipotle" hotness="very"`, ExpectErrorValueAt(t, 2))
+
+	ParseTestAndRunBlock(t,
+		`hot: (func name:string hotness:int?5 { return "\{$name}:\{$hotness}" })
+		 hot "chipotle" name="jalapeno"`, ExpectErrorValueAt(t, 2))
+
+	ParseTestAndRunBlock(t,
+		`hot: (func name:string hotness:int?5 { return "\{$name}:\{$hotness}" })
+		 hot "chipotle" color="red"`, ExpectErrorValueAt(t, 2))
+
+	ParseTestAndRunBlock(t,
+		`hot: (func name others... { return $others })
+		 hot "chipotle" others=[1 2]`, ExpectErrorValueAt(t, 2))
+}
+
+func TestSpreadArguments(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`hot: (func name:string hotness:int?5 { return "\{$name}:\{$hotness}" })
+		 hot ...["chipotle" 3]`, ExpectValue(t, NewStringLiteral("chipotle:3")))
+
+	ParseTestAndRunBlock(t,
+		`hot: (func name:string hotness:int?5 { return "\{$name}:\{$hotness}" })
+		 options: {hotness: 3}
+		 hot "chipotle" ...$options`, ExpectValue(t, NewStringLiteral("chipotle:3")))
+
+	ParseTestAndRunBlock(t,
+		`peppers: ["jalapeno" "habanero"]
+		 ["chipotle" ...$peppers "naga"]`,
+		ExpectValue(t, NewListValueFromStrings([]string{"chipotle", "jalapeno", "habanero", "naga"})))
+
+	ParseTestAndRunBlock(t,
+		`peppers: ["chipotle" "jalapeno"]
+		 eq ...$peppers`, ExpectValue(t, False))
+
+	ParseTestAndRunBlock(t,
+		`hot: (func name { return $name })
+		 hot ..."chipotle"`, ExpectErrorValueAt(t, 2))
+}
+
+func TestGoFunctionWithKeywordArguments(t *testing.T) {
+
+	global := NewGlobalContext()
+	global.SetNamed(NewGoFunctionWithKeywords("hot", "", []string{"hotness"}, func(context RunContext, arguments []Argument) Value {
+		arguments, keywords := SplitArguments(arguments)
+		if _, err := CheckArguments(arguments, 1, 1, "hot", "<name> hotness=<int>?"); err != nil {
+			return err
+		}
+		hotness, found := keywords["hotness"]
+		if !found {
+			return EvalArgument(context, arguments[0])
+		}
+		return NewStringLiteral(fmt.Sprintf("%v:%v", EvalArgument(context, arguments[0]), EvalArgument(context, hotness)))
+	}))
+
+	ParseTestAndRunBlockWithinContext(t, global,
+		`hot "chipotle" hotness=(plus 1 2)`, ExpectValue(t, NewStringLiteral("chipotle:3")))
+
+	ParseTestAndRunBlockWithinContext(t, global,
+		`hot hotness=3 "chipotle"`, ExpectValue(t, NewStringLiteral("chipotle:3")))
+
+	ParseTestAndRunBlockWithinContext(t, global,
+		`hot ...{hotness: 3} "chipotle"`, ExpectValue(t, NewStringLiteral("chipotle:3")))
+
+	ParseTestAndRunBlockWithinContext(t, global,
+		`hot "chipotle" color="red"`, ExpectErrorValueAt(t, 1))
+}
+
+func TestKeywordArgumentsMustBeDeclared(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`plus 1 x=2`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`plus 1 ...{x: 2}`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`hot: {peppers: ["chipotle"]}
+		 hot.peppers index=0`, ExpectErrorValueAt(t, 2))
+}
+
+func TestKeywordArgumentsArePassedOnceToCalls(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`f: (func a b { return [$a $b] })
+		 f a=1 a=2 b=3`, ExpectErrorValueAt(t, 2))
+
+	ParseTestAndRunBlock(t,
+		`f: (func a b { return [$a $b] })
+		 f a=1 ...{a: 2; b: 3}`, ExpectErrorValueAt(t, 2))
+
+	// keyword arguments can only be passed to calls
+	//
+	for _, script := range []string{`x=3`, `[a=1 2]`, `f: (func a { return $a }); f [a=1]`} {
+		if value := ParseAndRun(NewGlobalContext(), script); value.Type() != TypeError {
+			t.Errorf("expected %s to fail, found %v", script, value)
+		}
+	}
+}
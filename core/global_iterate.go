@@ -0,0 +1,201 @@
+package elmo
+
+import "fmt"
+
+func _range() NamedValue {
+	return NewGoFunctionWithHelp("range", `Creates a lazy sequence of integers
+		Usage: range <from>? <to> <step>?
+		Returns: an iterable producing integers from 'from' (default 0) up to,
+		but not including, 'to'
+
+		Values are produced when the sequence is iterated so even very
+		large ranges do not take any memory.
+
+		Examples:
+
+		> range 3
+		produces 0, 1 and 2
+		> range 1 10 2
+		produces 1, 3, 5, 7 and 9
+		> range 3 0 -1
+		produces 3, 2 and 1`,
+
+		func(context RunContext, arguments []Argument) Value {
+
+			argLen, err := CheckArguments(arguments, 1, 3, "range", "<from>? <to> <step>?")
+			if err != nil {
+				return err
+			}
+
+			bounds := []int64{0, 0, 1}
+			for i, argument := range arguments {
+				value := EvalArgument(context, argument)
+				if value.Type() == TypeError {
+					return value
+				}
+				if value.Type() != TypeInteger {
+					return NewErrorValue(fmt.Sprintf("invalid call to range, expected integers, not %v", value))
+				}
+				if argLen == 1 {
+					i = 1
+				}
+				bounds[i] = value.Internal().(int64)
+			}
+
+			from, to, step := bounds[0], bounds[1], bounds[2]
+			if step == 0 {
+				return NewErrorValue("invalid call to range, step can not be 0")
+			}
+
+			return NewIterableValue(func() Iterator {
+				current := from
+				return NewIterator(func() (Value, bool) {
+					if (step > 0 && current >= to) || (step < 0 && current <= to) {
+						return nil, false
+					}
+					value := current
+					current = current + step
+					return NewIntegerLiteral(value), true
+				}, nil)
+			})
+		})
+}
+
+// generator runs the code of a generator function in its own goroutine.
+// Every yield hands over a value and waits until the next value is asked
+// for, so code only runs ahead of its consumer by one value at most
+//
+type generator struct {
+	context  RunContext
+	block    Block
+	values   chan Value
+	resume   chan struct{}
+	done     chan struct{}
+	finished chan struct{}
+	started  bool
+	ended    bool
+	closed   bool
+}
+
+func newGenerator(context RunContext, block Block) Iterator {
+	return &generator{
+		context:  context,
+		block:    block,
+		values:   make(chan Value),
+		resume:   make(chan struct{}),
+		done:     make(chan struct{}),
+		finished: make(chan struct{})}
+}
+
+func (generator *generator) run() {
+	defer close(generator.finished)
+	defer close(generator.values)
+
+	runContext := generator.context.CreateSubContext()
+	runContext.SetNamed(generator.yield())
+
+	result := generator.block.Run(runContext, NoArguments)
+	if result.Type() == TypeError {
+		select {
+		case generator.values <- result:
+		case <-generator.done:
+		}
+	}
+}
+
+func (generator *generator) yield() NamedValue {
+	return NewGoFunctionWithHelp("yield", `Hands over a value to the code iterating over a generator`,
+		func(context RunContext, arguments []Argument) Value {
+
+			if _, err := CheckArguments(arguments, 1, 1, "yield", "<value>"); err != nil {
+				return err
+			}
+
+			value := EvalArgument(context, arguments[0])
+			if value.Type() == TypeError {
+				return value
+			}
+
+			select {
+			case generator.values <- value:
+			case <-generator.done:
+				return NewAbortErrorValue("generator closed")
+			}
+
+			select {
+			case <-generator.resume:
+				return Nothing
+			case <-generator.done:
+				return NewAbortErrorValue("generator closed")
+			}
+		})
+}
+
+func (generator *generator) Next() (Value, bool) {
+	if generator.ended {
+		return nil, false
+	}
+
+	if generator.started {
+		generator.resume <- struct{}{}
+	} else {
+		generator.started = true
+		go generator.run()
+	}
+
+	value, ok := <-generator.values
+	if !ok || value.Type() == TypeError {
+		generator.ended = true
+	}
+	return value, ok
+}
+
+func (generator *generator) Close() {
+	generator.ended = true
+	if !generator.started || generator.closed {
+		return
+	}
+	generator.closed = true
+	close(generator.done)
+	<-generator.finished
+}
+
+func _generator() NamedValue {
+	return NewGoFunctionWithHelp("generator", `Create a new generator function
+		Usage: generator <help>? <symbol>* {...}
+		Returns: a new function that returns an iterable
+
+		A generator is defined like a function but instead of returning a
+		single value, it produces values by calling yield. Calling a
+		generator does not run its code, the code runs while the returned
+		iterable is iterated and pauses at every yield until the next value
+		is needed. So generators can produce endless sequences.
+
+		Examples:
+
+		> countdown: (generator n { while (gt $n 0) { yield $n; incr n -1 } })
+		> countdown 3
+		produces 3, 2 and 1`,
+
+		func(context RunContext, arguments []Argument) Value {
+			return newFunction(context, "generator", arguments, func(block Block) func(RunContext) Value {
+				return func(evalContext RunContext) Value {
+					return NewIterableValue(func() Iterator {
+						return newGenerator(evalContext, block)
+					})
+				}
+			})
+		})
+}
+
+func yield() NamedValue {
+	return NewGoFunctionWithHelp("yield", `Hands over a value to the code iterating over a generator
+		Usage: yield <value>
+		Returns: nil
+
+		yield can only be used within the code of a generator, see help generator`,
+
+		func(context RunContext, arguments []Argument) Value {
+			return NewErrorValue("invalid call to yield, not within a generator")
+		})
+}
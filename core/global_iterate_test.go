@@ -0,0 +1,114 @@
+package elmo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRange(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`range`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`range "chipotle"`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`range 1 10 0`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`range 3`, ExpectIterated(t, ParseAndRun(NewGlobalContext(), "[0 1 2]")))
+
+	ParseTestAndRunBlock(t,
+		`range 1 10 3`, ExpectIterated(t, ParseAndRun(NewGlobalContext(), "[1 4 7]")))
+
+	ParseTestAndRunBlock(t,
+		`range 3 0 -1`, ExpectIterated(t, ParseAndRun(NewGlobalContext(), "[3 2 1]")))
+
+	ParseTestAndRunBlock(t,
+		`range 3 3`, ExpectIterated(t, ParseAndRun(NewGlobalContext(), "[]")))
+
+	ParseTestAndRunBlock(t,
+		`type (range 3)`, ExpectValue(t, NewIdentifier("iterable")))
+}
+
+func TestGenerator(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`yield 3`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`generator`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`countdown: (generator n {
+		   while (gt $n 0) {
+		     yield $n
+		     incr n -1
+		   }
+		 })
+		 countdown 3`, ExpectIterated(t, ParseAndRun(NewGlobalContext(), "[3 2 1]")))
+
+	ParseTestAndRunBlock(t,
+		`g: (generator { yield 1; return 2; yield 3 })
+		 g`, ExpectIterated(t, ParseAndRun(NewGlobalContext(), "[1]")))
+
+	ParseTestAndRunBlock(t,
+		`g: (generator n:int { yield $n })
+		 g "chipotle"`, ExpectErrorValueAt(t, 2))
+
+	// every iteration runs the code of a generator again
+	//
+	ParseTestAndRunBlock(t,
+		`g: (generator from { yield $from; from: (plus $from 1); yield $from })
+		 numbers: (g 1)
+		 numbers`, ExpectIterated(t, ParseAndRun(NewGlobalContext(), "[1 2]")))
+}
+
+func TestGeneratorReportsErrors(t *testing.T) {
+
+	context := NewGlobalContext()
+	value := ParseAndRun(context, `(generator { yield 1; error "chipotle" }) |eval`)
+
+	iterable, ok := AsIterable(value)
+	if !ok {
+		t.Fatalf("expected iterable but found %v", value)
+	}
+
+	if _, err := CollectValues(context, iterable); err == nil || !strings.Contains(err.Error(), "chipotle") {
+		t.Errorf("expected chipotle error but found %v", err)
+	}
+}
+
+func TestGeneratorStopsWhenClosed(t *testing.T) {
+
+	produced := int64(0)
+
+	context := NewGlobalContext()
+	context.SetNamed(NewGoFunctionWithHelp("produce", "", func(context RunContext, arguments []Argument) Value {
+		produced = produced + 1
+		return NewIntegerLiteral(produced)
+	}))
+	value := ParseAndRun(context, `(generator { while (true) { yield (produce) } }) |eval`)
+
+	iterable, ok := AsIterable(value)
+	if !ok {
+		t.Fatalf("expected iterable but found %v", value)
+	}
+
+	iterator := iterable.Iterate()
+	for i := 1; i <= 3; i++ {
+		if next, _ := iterator.Next(); next.Internal().(int64) != int64(i) {
+			t.Errorf("expected %d but found %v", i, next)
+		}
+	}
+	iterator.Close()
+
+	if _, more := iterator.Next(); more {
+		t.Error("expected closed generator to produce no values")
+	}
+
+	if produced != 3 {
+		t.Errorf("expected generator to stop after 3 values but it produced %v", produced)
+	}
+}
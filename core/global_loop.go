@@ -0,0 +1,250 @@
+package elmo
+
+import "fmt"
+
+// loopSignal is the result of break and continue. The signal stops the
+// code of a loop iteration and is picked up by the loop running it
+//
+type loopSignal struct {
+	baseValue
+	name string
+}
+
+func (loopSignal *loopSignal) String() string {
+	return loopSignal.name
+}
+
+func (loopSignal *loopSignal) Type() Type {
+	return TypeInternal
+}
+
+func (loopSignal *loopSignal) Internal() interface{} {
+	return loopSignal.name
+}
+
+var breakSignal = &loopSignal{baseValue: baseValue{info: typeInfoLoopSignal}, name: "break"}
+var continueSignal = &loopSignal{baseValue: baseValue{info: typeInfoLoopSignal}, name: "continue"}
+
+// RunLoopIteration runs the code of a single loop iteration within a context
+// that can be stopped by break and continue without stopping given context.
+// It returns the result of the iteration, or nil when the iteration is ended
+// by break or continue, and whether the loop should end. A return within the
+// iteration stops given context
+//
+func RunLoopIteration(context RunContext, run func(iterationContext RunContext) Value) (Value, bool) {
+
+	iterationContext := context.detach()
+	result := run(iterationContext)
+
+	if result.Type() == TypeError {
+		return result, true
+	}
+
+	if !iterationContext.isStopped() {
+		return result, false
+	}
+
+	switch result {
+	case breakSignal:
+		return nil, true
+	case continueSignal:
+		return nil, false
+	}
+
+	context.Stop()
+	return result, true
+}
+
+// outsideLoop converts a loop signal that ended up outside of a loop into an error
+//
+func outsideLoop(value Value) Value {
+	if signal, isSignal := value.(*loopSignal); isSignal {
+		return NewErrorValue(fmt.Sprintf("invalid call to %s, not within a loop", signal.name))
+	}
+	return value
+}
+
+func _break() NamedValue {
+	return NewGoFunctionWithHelp("break", `Stops the loop it is used in
+		Usage: break
+		Returns: nothing, the loop ends and returns the result of its last complete iteration
+
+		break can be used in all loops (for, while, until, do and list.each)
+		and only stops the loop, not the function the loop is part of
+
+		Example:
+
+		> for i in (range 100) { if (gt $i 10) { break }; puts $i }`,
+
+		func(context RunContext, arguments []Argument) Value {
+			if _, err := CheckArguments(arguments, 0, 0, "break", ""); err != nil {
+				return err
+			}
+
+			context.Stop()
+			return breakSignal
+		})
+}
+
+func _continue() NamedValue {
+	return NewGoFunctionWithHelp("continue", `Skips the rest of the current loop iteration
+		Usage: continue
+		Returns: nothing, the loop continues with its next iteration
+
+		continue can be used in all loops (for, while, until, do and list.each)
+
+		Example:
+
+		> for i in (range 10) { if (eq (modulo $i 2) 0) { continue }; puts $i }`,
+
+		func(context RunContext, arguments []Argument) Value {
+			if _, err := CheckArguments(arguments, 0, 0, "continue", ""); err != nil {
+				return err
+			}
+
+			context.Stop()
+			return continueSignal
+		})
+}
+
+// forIterator returns an iterator producing pairs of values for a for loop.
+// Dictionaries produce keys and values in insertion order, all other values
+// produce values and their positions
+//
+func forIterator(context RunContext, collection Value) (func() (Value, Value, bool), func(), ErrorValue) {
+
+	if collection.Type() == TypeBlock {
+		collection = NewDictionaryWithBlock(context, collection.(Block))
+	}
+
+	switch collection.Type() {
+	case TypeError:
+		return nil, nil, collection.(ErrorValue)
+	case TypeDictionary:
+
+		// dictionaries can provide their own values to iterate over
+		//
+		if values, hooked := runValueHook(context, collection, hookIter); hooked {
+			if values.Type() == TypeError {
+				return nil, nil, values.(ErrorValue)
+			}
+			if values.Type() == TypeDictionary {
+				return nil, nil, NewErrorValue("found _iter did not return a list or sequence")
+			}
+			return forIterator(context, values)
+		}
+
+		dict := collection.(DictionaryValue)
+		keys := dict.KeyValues()
+		index := 0
+		return func() (Value, Value, bool) {
+			for index < len(keys) {
+				index = index + 1
+				if value, found := dict.ResolveKey(keys[index-1]); found {
+					return keys[index-1], value, true
+				}
+			}
+			return nil, nil, false
+		}, nil, nil
+	case TypeString:
+		runes := []rune(collection.String())
+		index := 0
+		return func() (Value, Value, bool) {
+			if index >= len(runes) {
+				return nil, nil, false
+			}
+			index = index + 1
+			return NewStringLiteral(string(runes[index-1])), NewIntegerLiteral(int64(index - 1)), true
+		}, nil, nil
+	}
+
+	iterator, err := Iterate(collection)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	index := 0
+	return func() (Value, Value, bool) {
+		value, ok := iterator.Next()
+		if !ok {
+			return nil, nil, false
+		}
+		index = index + 1
+		return value, NewIntegerLiteral(int64(index - 1)), true
+	}, iterator.Close, nil
+}
+
+func _for() NamedValue {
+	return NewGoFunctionWithHelp("for", `Repeat (a block of) code for all values of a collection
+		Usage: for <symbol> <symbol>? in <collection> {...}
+		Returns: result of the last iteration or nil when code is not executed
+
+		Lists, ranges and other iterables provide their values and, optionally,
+		the position of these values. Dictionaries provide their keys and,
+		optionally, their values. Strings provide their characters and,
+		optionally, the position of these characters.
+
+		Examples:
+
+		> for pepper in [chipotle jalapeno] { puts $pepper }
+		> for pepper i in [chipotle jalapeno] { puts $i $pepper }
+		> for key value in {a: 1; b: 2} { puts $key $value }
+		> for c in "chipotle" { puts $c }
+		> for i in (range 10) { puts $i }
+
+		Loops can be ended using break and iterations can be skipped using continue.`,
+
+		func(context RunContext, arguments []Argument) Value {
+
+			argLen, err := CheckArguments(arguments, 4, 5, "for", "<identifier> <identifier>? in <collection> {...}")
+			if err != nil {
+				return err
+			}
+
+			if arguments[argLen-3].Value().String() != "in" {
+				return NewErrorValue("invalid call to for, expected in before collection. Usage: for <identifier> <identifier>? in <collection> {...}")
+			}
+
+			names := make([]string, argLen-3)
+			for i := range names {
+				names[i] = EvalArgument2String(context, arguments[i])
+			}
+
+			next, closeIterator, err := forIterator(context, EvalArgument(context, arguments[argLen-2]))
+			if err != nil {
+				return err
+			}
+			if closeIterator != nil {
+				defer closeIterator()
+			}
+
+			var result Value = Nothing
+			for {
+				if err := context.step(); err != nil {
+					return err
+				}
+
+				first, second, ok := next()
+				if !ok {
+					return result
+				}
+				if first.Type() == TypeError {
+					return first
+				}
+
+				iteration, done := RunLoopIteration(context, func(iterationContext RunContext) Value {
+					iterationContext.Set(names[0], first)
+					if len(names) == 2 {
+						iterationContext.Set(names[1], second)
+					}
+					return EvalArgumentWithBlock(iterationContext, arguments[argLen-1])
+				})
+				if iteration != nil {
+					result = iteration
+				}
+				if done {
+					return result
+				}
+			}
+		})
+}
@@ -0,0 +1,133 @@
+package elmo
+
+import "testing"
+
+func TestFor(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`for x [1 2 3] {}`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`for x at [1 2 3] {}`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`for x in 3 {}`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`for x in [] { 3 }`, ExpectNothing(t))
+
+	ParseTestAndRunBlock(t,
+		`s: ""
+		 for x in ["chipotle" "jalapeno"] { s: "\{$s}\{$x}," }
+		 $s`, ExpectValue(t, NewStringLiteral("chipotle,jalapeno,")))
+
+	ParseTestAndRunBlock(t,
+		`s: ""
+		 for x i in ["chipotle" "jalapeno"] { s: "\{$s}\{$i}:\{$x}," }
+		 $s`, ExpectValue(t, NewStringLiteral("0:chipotle,1:jalapeno,")))
+
+	ParseTestAndRunBlock(t,
+		`s: ""
+		 for k v in {chipotle: 1; jalapeno: 2} { s: "\{$s}\{$k}=\{$v}," }
+		 $s`, ExpectValue(t, NewStringLiteral("chipotle=1,jalapeno=2,")))
+
+	ParseTestAndRunBlock(t,
+		`s: ""
+		 d: {chipotle: 1; jalapeno: 2}
+		 for k in $d { s: "\{$s}\{$k}," }
+		 $s`, ExpectValue(t, NewStringLiteral("chipotle,jalapeno,")))
+
+	ParseTestAndRunBlock(t,
+		`s: ""
+		 for k in {jalapeno: 1; chipotle: 2; habanero: 3} { s: "\{$s}\{$k}," }
+		 $s`, ExpectValue(t, NewStringLiteral("jalapeno,chipotle,habanero,")))
+
+	ParseTestAndRunBlock(t,
+		`s: ""
+		 for c i in "jalapeño" { s: "\{$c}\{$s}" }
+		 $s`, ExpectValue(t, NewStringLiteral("oñepalaj")))
+
+	ParseTestAndRunBlock(t,
+		`for i in (range 4) { multiply $i $i }`, ExpectValue(t, NewIntegerLiteral(9)))
+
+	ParseTestAndRunBlock(t,
+		`g: (generator { yield 1; yield 2 })
+		 for i in (g) { $i }`, ExpectValue(t, NewIntegerLiteral(2)))
+}
+
+func TestBreakAndContinue(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`break 3`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`f: (func { break })
+		 f`, ExpectErrorValueAt(t, 2))
+
+	ParseTestAndRunBlock(t,
+		`for i in (range 1000000) {
+		   if (eq $i 3) { break }
+		   $i
+		 }`, ExpectValue(t, NewIntegerLiteral(2)))
+
+	ParseTestAndRunBlock(t,
+		`s: ""
+		 for i in (range 6) {
+		   if (eq (modulo $i 2) 0) { continue }
+		   s: "\{$s}\{$i}"
+		 }
+		 $s`, ExpectValue(t, NewStringLiteral("135")))
+
+	ParseTestAndRunBlock(t,
+		`i: 0
+		 while (lt $i 10) {
+		   incr i
+		   if (eq $i 5) { break }
+		 }
+		 $i`, ExpectValue(t, NewIntegerLiteral(5)))
+
+	ParseTestAndRunBlock(t,
+		`i: 0
+		 s: ""
+		 until (eq $i 5) {
+		   incr i
+		   if (eq $i 3) { continue }
+		   s: "\{$s}\{$i}"
+		 }
+		 $s`, ExpectValue(t, NewStringLiteral("1245")))
+
+	ParseTestAndRunBlock(t,
+		`i: 0
+		 do {
+		   incr i
+		   if (gt $i 3) { break }
+		 } while (true)
+		 $i`, ExpectValue(t, NewIntegerLiteral(4)))
+
+	// break only ends the innermost loop
+	//
+	ParseTestAndRunBlock(t,
+		`s: ""
+		 for i in [1 2] {
+		   for j in [1 2 3] {
+		     if (eq $j 2) { break }
+		     s: "\{$s}\{$i}\{$j},"
+		   }
+		 }
+		 $s`, ExpectValue(t, NewStringLiteral("11,21,")))
+
+	// break does not end the function containing the loop
+	// while return does
+	//
+	ParseTestAndRunBlock(t,
+		`f: (func { for i in [1 2 3] { break }; return "chipotle" })
+		 f`, ExpectValue(t, NewStringLiteral("chipotle")))
+
+	ParseTestAndRunBlock(t,
+		`f: (func { for i in [1 2 3] { if (eq $i 2) { return $i } }; return "chipotle" })
+		 f`, ExpectValue(t, NewIntegerLiteral(2)))
+
+	ParseTestAndRunBlock(t,
+		`f: (func { i: 0; while (true) { incr i; if (eq $i 4) { return $i } } })
+		 f`, ExpectValue(t, NewIntegerLiteral(4)))
+}
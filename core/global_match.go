@@ -0,0 +1,448 @@
+package elmo
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// matchCase is a single pattern of a match together with its
+// optional guard and the code to execute when it matches
+//
+type matchCase struct {
+	pattern Argument
+	guard   Argument
+	code    Argument
+}
+
+func parseMatchCases(block Block) ([]*matchCase, ErrorValue) {
+	cases := make([]*matchCase, 0, len(block.Calls()))
+
+	for _, c := range block.Calls() {
+		line, isLine := c.(*call)
+		if !isLine || line.function != nil || line.WillPipe() {
+			return nil, NewErrorValue("invalid call to match, expected <pattern> (if <guard>)? {...}")
+		}
+
+		declaration := declarationArguments(line)
+		pattern, arguments := declaration[0], declaration[1:]
+		switch {
+		case len(arguments) == 1:
+			cases = append(cases, &matchCase{pattern: pattern, code: arguments[0]})
+		case len(arguments) == 3 && arguments[0].Type() == TypeIdentifier && arguments[0].String() == "if":
+			cases = append(cases, &matchCase{pattern: pattern, guard: arguments[1], code: arguments[2]})
+		default:
+			return nil, NewErrorValue(fmt.Sprintf("invalid case %v in match, expected <pattern> (if <guard>)? {...}", pattern))
+		}
+	}
+
+	return cases, nil
+}
+
+// matcher matches values against patterns and collects the values captured
+// by the patterns. When destructuring, a value that does not match results
+// in an error explaining why
+//
+type matcher struct {
+	context       RunContext
+	captures      map[string]Value
+	destructuring bool
+}
+
+func (matcher *matcher) capture(name string, value Value) {
+	if name != "_" {
+		matcher.captures[name] = value
+	}
+}
+
+func (matcher *matcher) mismatch(format string, a ...interface{}) (bool, ErrorValue) {
+	if matcher.destructuring {
+		return false, NewErrorValue(fmt.Sprintf(format, a...))
+	}
+	return false, nil
+}
+
+// match checks if a value matches a pattern. Outside of list and dictionary
+// shapes, identifiers denote the name of a type. Within shapes, identifiers
+// capture the value they match
+//
+func (matcher *matcher) match(pattern Argument, value Value, inShape bool) (bool, ErrorValue) {
+
+	switch pattern.Type() {
+	case TypeIdentifier:
+		if len(pattern.Value().(*identifier).value) > 1 {
+			return matcher.matchValue(pattern, value)
+		}
+
+		parameter, err := newParameter(pattern.String())
+		if err != nil {
+			return false, err
+		}
+		if parameter.rest || parameter.optional {
+			return false, NewErrorValue(fmt.Sprintf("invalid pattern %v, only list elements and dictionary keys can have a default or capture the rest", pattern))
+		}
+
+		if !inShape && parameter.typeName == "" && parameter.name != "_" {
+			return value.Info().Name().String() == parameter.name, nil
+		}
+
+		return matcher.matchParameter(parameter, value)
+
+	case TypeBlock:
+		return matcher.matchDictionary(pattern.Value().(Block), value)
+
+	case TypeCall:
+		if isListLiteral(pattern) {
+			return matcher.matchList(pattern.Value().(*call).arguments, value)
+		}
+	}
+
+	return matcher.matchValue(pattern, value)
+}
+
+func (matcher *matcher) matchParameter(parameter *parameter, value Value) (bool, ErrorValue) {
+	if !parameter.accepts(value) {
+		return matcher.mismatch("expected %s for %s, not %v", parameter.typeName, parameter.name, value.Info().Name())
+	}
+	matcher.capture(parameter.name, value)
+	return true, nil
+}
+
+func (matcher *matcher) matchValue(pattern Argument, value Value) (bool, ErrorValue) {
+	expected := EvalArgument(matcher.context, pattern)
+	if expected.Type() == TypeError && !expected.(ErrorValue).CanBeIgnored() {
+		return false, expected.(ErrorValue)
+	}
+	if !equalValues(matcher.context, expected, value) {
+		return matcher.mismatch("expected %v, not %v", expected, value)
+	}
+	return true, nil
+}
+
+func isListLiteral(argument Argument) bool {
+	literal, isCall := argument.Value().(*call)
+	return isCall && literal.node != nil && literal.node.pegRule == ruleList
+}
+
+// shapeElement is an element of a list shape or a key of a dictionary
+// shape. Elements given as name? can have a default value
+//
+type shapeElement struct {
+	pattern      Argument
+	capture      *parameter
+	defaultValue Argument
+}
+
+// shapeElements parses the elements of a shape, separating an optional
+// rest element (given as rest... or ...rest) at the end
+//
+func shapeElements(arguments []Argument) ([]*shapeElement, *parameter, ErrorValue) {
+	elements := make([]*shapeElement, 0, len(arguments))
+
+	for i := 0; i < len(arguments); i++ {
+		argument := arguments[i]
+		last := i == len(arguments)-1
+
+		if spread, isSpread := argument.(*spreadArgument); isSpread {
+			if !last || spread.Value().Type() != TypeIdentifier {
+				return nil, nil, NewErrorValue(fmt.Sprintf("invalid pattern %v, only the last element can capture the rest", spread))
+			}
+			return elements, &parameter{name: spread.Value().String(), rest: true}, nil
+		}
+
+		if argument.Type() != TypeIdentifier || len(argument.Value().(*identifier).value) > 1 {
+			elements = append(elements, &shapeElement{pattern: argument})
+			continue
+		}
+
+		parameter, err := newParameter(argument.String())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch {
+		case parameter.rest:
+			if !last {
+				return nil, nil, NewErrorValue(fmt.Sprintf("invalid pattern %v, only the last element can capture the rest", argument))
+			}
+			return elements, parameter, nil
+		case parameter.optional:
+			element := &shapeElement{pattern: argument, capture: parameter}
+			if !last {
+				i++
+				element.defaultValue = arguments[i]
+			}
+			elements = append(elements, element)
+		default:
+			elements = append(elements, &shapeElement{pattern: argument})
+		}
+	}
+
+	return elements, nil, nil
+}
+
+// matchElement matches a single element of a shape
+//
+func (matcher *matcher) matchElement(element *shapeElement, value Value) (bool, ErrorValue) {
+	if element.capture != nil {
+		return matcher.matchParameter(element.capture, value)
+	}
+	return matcher.match(element.pattern, value, true)
+}
+
+// matchMissing handles an element of a shape that has no value
+//
+func (matcher *matcher) matchMissing(element *shapeElement, format string, a ...interface{}) (bool, ErrorValue) {
+	if element.capture == nil || !element.capture.optional {
+		return matcher.mismatch(format, a...)
+	}
+	if element.defaultValue == nil {
+		matcher.capture(element.capture.name, Nothing)
+		return true, nil
+	}
+	return matcher.matchParameter(element.capture, EvalArgument(matcher.context, element.defaultValue))
+}
+
+// matchList matches a list shape. When the last element of the shape is
+// a rest pattern (like tail... or ...tail), it captures all remaining values.
+// When destructuring, values without an element in the shape are ignored
+//
+func (matcher *matcher) matchList(arguments []Argument, value Value) (bool, ErrorValue) {
+	if value.Type() != TypeList {
+		return matcher.mismatch("expected a list, not %v", value.Info().Name())
+	}
+	values := value.Internal().([]Value)
+
+	elements, rest, err := shapeElements(arguments)
+	if err != nil {
+		return false, err
+	}
+
+	if rest == nil && len(values) > len(elements) && !matcher.destructuring {
+		return false, nil
+	}
+
+	for i, element := range elements {
+		var matches bool
+		if i < len(values) {
+			matches, err = matcher.matchElement(element, values[i])
+		} else {
+			matches, err = matcher.matchMissing(element, "missing element %d of list with %d elements", i+1, len(values))
+		}
+		if !matches || err != nil {
+			return false, err
+		}
+	}
+
+	if rest != nil {
+		remaining := []Value{}
+		if len(values) > len(elements) {
+			remaining = values[len(elements):]
+		}
+		for _, v := range remaining {
+			if !rest.accepts(v) {
+				return matcher.mismatch("expected %s for %s, not %v", rest.typeName, rest.name, v.Info().Name())
+			}
+		}
+		matcher.capture(rest.name, NewListValue(append([]Value{}, remaining...)))
+	}
+
+	return true, nil
+}
+
+// matchDictionary matches a dictionary shape like {name: n; hotness}. Keys
+// without a pattern are captured using the name of the key
+//
+func (matcher *matcher) matchDictionary(shape Block, value Value) (bool, ErrorValue) {
+	if value.Type() != TypeDictionary {
+		return matcher.mismatch("expected a dictionary, not %v", value.Info().Name())
+	}
+	dict := value.(DictionaryValue)
+
+	for _, c := range shape.Calls() {
+		line, isLine := c.(*call)
+		if !isLine || line.function != nil {
+			return false, NewErrorValue("invalid dictionary pattern, expected <key>: <pattern> or <key>*")
+		}
+
+		// key: pattern
+		//
+		if line.firstArgument.String() == "set" && len(line.arguments) > 1 {
+			key := EvalArgument2String(matcher.context, line.arguments[0])
+			elements, rest, err := shapeElements(line.arguments[1:])
+			if err != nil {
+				return false, err
+			}
+			if len(elements) != 1 || rest != nil {
+				return false, NewErrorValue(fmt.Sprintf("invalid dictionary pattern for %s, expected <key>: <pattern>", key))
+			}
+			if matches, err := matcher.matchKey(dict, key, elements[0]); !matches || err != nil {
+				return false, err
+			}
+			continue
+		}
+
+		// key*
+		//
+		elements, rest, err := shapeElements(append([]Argument{line.firstArgument}, line.arguments...))
+		if err != nil {
+			return false, err
+		}
+		if rest != nil {
+			return false, NewErrorValue(fmt.Sprintf("invalid dictionary pattern, %s can not capture the rest of a dictionary", rest.name))
+		}
+
+		for _, element := range elements {
+			if element.capture == nil {
+				if element.pattern.Type() != TypeIdentifier {
+					return false, NewErrorValue(fmt.Sprintf("invalid dictionary pattern %v, expected a key", element.pattern))
+				}
+				if element.capture, err = newParameter(element.pattern.String()); err != nil {
+					return false, err
+				}
+			}
+			if matches, err := matcher.matchKey(dict, element.capture.name, element); !matches || err != nil {
+				return false, err
+			}
+		}
+	}
+
+	return true, nil
+}
+
+func (matcher *matcher) matchKey(dict DictionaryValue, key string, element *shapeElement) (bool, ErrorValue) {
+	found, exists := dict.Resolve(key)
+	if !exists {
+		return matcher.matchMissing(element, "missing key %s", key)
+	}
+	return matcher.matchElement(element, found)
+}
+
+// isShape checks if an argument is a list or dictionary shape
+//
+func isShape(argument Argument) bool {
+	return argument.Type() == TypeBlock || isListLiteral(argument)
+}
+
+// destructure assigns all values captured by matching a list or dictionary
+// shape. Values that do not fit the shape result in an error
+//
+func destructure(context RunContext, shape Argument, value Value) ErrorValue {
+	matcher := &matcher{context: context, captures: map[string]Value{}, destructuring: true}
+	if _, err := matcher.match(shape, value, true); err != nil {
+		return err
+	}
+	for name, captured := range matcher.captures {
+		context.Set(name, captured)
+	}
+	return nil
+}
+
+// equalValues checks if two values are equal, first by comparing
+// them and when they can not be compared by a deep equal
+//
+func equalValues(context RunContext, v1 Value, v2 Value) bool {
+	if result := compareValues(context, v1, v2, func(result int) Value {
+		return TrueOrFalse(result == 0)
+	}); result.Type() != TypeError {
+		return result.(*booleanLiteral).value
+	}
+	return reflect.DeepEqual(v1, v2)
+}
+
+func match() NamedValue {
+	return NewGoFunctionWithHelp("match", `Execute the code of the first pattern that matches a value
+		Usage: match <value> { <pattern> (if <guard>)? {...} ... }
+		Returns: value of the executed code or nil when no pattern matches
+
+		Patterns can be:
+		  literal values, like 3 or "chipotle", or calls like $pepper or (plus 1 2)
+		  the name of a type, like int, string, list or dict
+		  _ which matches everything
+		  name:type which matches values of given type and captures them as name
+		  list shapes, like [first second], where the last element can capture the rest as in [head tail...]
+		  dictionary shapes, like {name: n; hotness}, where keys without a pattern are captured by their own name
+
+		Within list and dictionary shapes, identifiers capture the value they match.
+		Captured values are only available within the guard and the code of the
+		matching pattern.
+
+		Examples:
+
+		> match $pepper {
+		>   "chipotle" { "smoked" }
+		>   [head tail...] { $tail }
+		>   {name: n; hotness: h} if (gt $h 1000) { "hot \{$n}" }
+		>   string { "unknown pepper" }
+		>   _ { "not a pepper" }
+		> }`,
+
+		func(context RunContext, arguments []Argument) Value {
+
+			_, err := CheckArguments(arguments, 2, 2, "match", "<value> { <pattern> (if <guard>)? {...} ... }")
+			if err != nil {
+				return err
+			}
+
+			value := EvalArgument(context, arguments[0])
+			if value.Type() == TypeError && value.(ErrorValue).IsAborted() {
+				return value
+			}
+			if value.Type() == TypeBlock {
+				value = NewDictionaryWithBlock(context, value.(Block))
+			}
+
+			if arguments[1].Type() != TypeBlock {
+				return NewErrorValue("invalid call to match, expected a block with patterns")
+			}
+
+			cases, err := parseMatchCases(arguments[1].Value().(Block))
+			if err != nil {
+				return err
+			}
+
+			for _, matchCase := range cases {
+				matcher := &matcher{context: context, captures: map[string]Value{}}
+
+				matches, err := matcher.match(matchCase.pattern, value, false)
+				if err != nil {
+					return err
+				}
+				if !matches {
+					continue
+				}
+
+				branchContext := context.CreateSubContext()
+				for name, captured := range matcher.captures {
+					branchContext.Set(name, captured)
+				}
+
+				if matchCase.guard != nil {
+					guard := EvalArgument(branchContext, matchCase.guard)
+					if guard.Type() == TypeError {
+						return guard
+					}
+					if guard.Type() != TypeBoolean {
+						return NewErrorValue("match guard does not evaluate to a boolean value")
+					}
+					if !guard.(*booleanLiteral).value {
+						continue
+					}
+				}
+
+				if matchCase.code.Type() != TypeBlock {
+					return EvalArgument(branchContext, matchCase.code)
+				}
+
+				// a return within the code of a pattern should also
+				// stop the code calling match
+				//
+				result, returned := runDetached(branchContext, matchCase.code.Value().(Block))
+				if returned {
+					context.Stop()
+				}
+				return result
+			}
+
+			return Nothing
+		})
+}
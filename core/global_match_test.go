@@ -0,0 +1,158 @@
+package elmo
+
+import "testing"
+
+func TestMatchLiterals(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`match 3`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`match 3 4`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`match 3 {
+		   1 { "one" }
+		   3 { "three" }
+		 }`, ExpectValue(t, NewStringLiteral("three")))
+
+	ParseTestAndRunBlock(t,
+		`match "chipotle" {
+		   "jalapeno" { "fresh" }
+		   "chipotle" { "smoked" }
+		 }`, ExpectValue(t, NewStringLiteral("smoked")))
+
+	ParseTestAndRunBlock(t,
+		`pepper: "chipotle"
+		 match "chipotle" {
+		   $pepper "smoked"
+		 }`, ExpectValue(t, NewStringLiteral("smoked")))
+
+	ParseTestAndRunBlock(t,
+		`match 5 {
+		   1 { "one" }
+		 }`, ExpectNothing(t))
+
+	ParseTestAndRunBlock(t,
+		`match 5 {
+		   1 { "one" }
+		   _ { "many" }
+		 }`, ExpectValue(t, NewStringLiteral("many")))
+
+	ParseTestAndRunBlock(t,
+		`match 5 {
+		   1 { "one" } { "two" }
+		 }`, ExpectErrorValueAt(t, 1))
+}
+
+func TestMatchTypes(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`match "chipotle" {
+		   int { "number" }
+		   string { "text" }
+		 }`, ExpectValue(t, NewStringLiteral("text")))
+
+	ParseTestAndRunBlock(t,
+		`match 3 {
+		   s:string { $s }
+		   i:int { (plus $i 1) }
+		 }`, ExpectValue(t, NewIntegerLiteral(4)))
+
+	ParseTestAndRunBlock(t,
+		`match 3 {
+		   i:any { $i }
+		 }`, ExpectValue(t, NewIntegerLiteral(3)))
+}
+
+func TestMatchLists(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`match [1 2] {
+		   [a] { $a }
+		   [a b] { (plus $a $b) }
+		 }`, ExpectValue(t, NewIntegerLiteral(3)))
+
+	ParseTestAndRunBlock(t,
+		`match ["chipotle" "jalapeno" "habanero"] {
+		   [head tail...] { $tail }
+		 }`, ExpectValue(t, NewListValueFromStrings([]string{"jalapeno", "habanero"})))
+
+	ParseTestAndRunBlock(t,
+		`match [] {
+		   [head tail...] { $tail }
+		   [] { "empty" }
+		 }`, ExpectValue(t, NewStringLiteral("empty")))
+
+	ParseTestAndRunBlock(t,
+		`match [1 "two"] {
+		   [a:int b:int] { "numbers" }
+		   [1 b:string] { $b }
+		 }`, ExpectValue(t, NewStringLiteral("two")))
+
+	ParseTestAndRunBlock(t,
+		`match [[1 2] 3] {
+		   [[a _] b] { (plus $a $b) }
+		 }`, ExpectValue(t, NewIntegerLiteral(4)))
+
+	ParseTestAndRunBlock(t,
+		`match [1 2] {
+		   [a...  b] { $a }
+		 }`, ExpectErrorValueAt(t, 1))
+}
+
+func TestMatchDictionaries(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`match {name: "chipotle"; hotness: 3} {
+		   {name: n; hotness: 1} { "mild \{$n}" }
+		   {name: n; hotness: h} { "\{$n}:\{$h}" }
+		 }`, ExpectValue(t, NewStringLiteral("chipotle:3")))
+
+	ParseTestAndRunBlock(t,
+		`match {name: "chipotle"; hotness: 3} {
+		   {color} { $color }
+		   {name} { $name }
+		 }`, ExpectValue(t, NewStringLiteral("chipotle")))
+
+	ParseTestAndRunBlock(t,
+		`match {name: "chipotle"; hotness: 3} {
+		   {name; hotness: h} if (gt $h 1000) { "hot \{$name}" }
+		   {name} { "mild \{$name}" }
+		 }`, ExpectValue(t, NewStringLiteral("mild chipotle")))
+
+	ParseTestAndRunBlock(t,
+		`match {name: "naga"; hotness: 1000000} {
+		   {name; hotness: h} if (gt $h 1000) { "hot \{$name}" }
+		   {name} { "mild \{$name}" }
+		 }`, ExpectValue(t, NewStringLiteral("hot naga")))
+
+	ParseTestAndRunBlock(t,
+		`match {peppers: ["chipotle" "jalapeno"]} {
+		   {peppers: [first rest...]} { $first }
+		 }`, ExpectValue(t, NewStringLiteral("chipotle")))
+
+	ParseTestAndRunBlock(t,
+		`match 3 {
+		   int if 3 { 3 }
+		 }`, ExpectErrorValueAt(t, 1))
+}
+
+func TestMatchUsesOwnScope(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`a: 1
+		 match [2] {
+		   [a] { $a }
+		 }
+		 $a`, ExpectValue(t, NewIntegerLiteral(1)))
+
+	ParseTestAndRunBlock(t,
+		`f: (func v {
+		   match $v {
+		     int { return "number" }
+		   }
+		   return "other"
+		 })
+		 f 3`, ExpectValue(t, NewStringLiteral("number")))
+}
@@ -0,0 +1,168 @@
+package elmo
+
+import (
+	"fmt"
+	"math"
+)
+
+// evalPrototype evaluates the prototype argument of object functions which
+// can be an identifier with the name of the prototype
+//
+func evalPrototype(context RunContext, name string, argument Argument) (DictionaryValue, ErrorValue) {
+	value := EvalArgumentOrSolveIdentifier(context, argument)
+	if value.Type() == TypeError {
+		return nil, value.(ErrorValue)
+	}
+
+	prototype, ok := value.(DictionaryValue)
+	if !ok {
+		return nil, NewErrorValue(fmt.Sprintf("invalid call to %s, expected a dictionary as prototype instead of %v", name, value))
+	}
+	return prototype, nil
+}
+
+func extend() NamedValue {
+	return NewGoFunctionWithHelp("extend", `Create an object that inherits from a prototype
+		Usage: extend <prototype> <block|dictionary>?
+		Returns: a new dictionary with given prototype as parent
+
+		Keys that can not be found in the new dictionary are looked up in its
+		prototype. Functions called through a dictionary know the dictionary
+		as this. Functions that override a function of the prototype can call
+		the original function through super.
+
+		Examples:
+
+		> pepper: {
+		>   name: "pepper"
+		>   describe: (func { return "\{$this.name} pepper" })
+		> }
+		> chipotle: (extend pepper {
+		>   name: "chipotle"
+		>   describe: (func { return "smoked \{(super.describe)}" })
+		> })
+		> chipotle.describe
+		will result in "smoked chipotle pepper"`,
+
+		func(context RunContext, arguments []Argument) Value {
+
+			argLen, err := CheckArguments(arguments, 1, 2, "extend", "<prototype> <block|dictionary>?")
+			if err != nil {
+				return err
+			}
+
+			prototype, err := evalPrototype(context, "extend", arguments[0])
+			if err != nil {
+				return err
+			}
+
+			if argLen == 1 {
+				object, _ := NewObject(prototype, nil)
+				return object
+			}
+
+			values := EvalArgument(context, arguments[1])
+			if values.Type() == TypeBlock {
+				values = NewDictionaryWithBlock(context, values.(Block))
+			}
+
+			dict, ok := values.(DictionaryValue)
+			if !ok {
+				return NewErrorValue(fmt.Sprintf("invalid call to extend, expected a block or dictionary instead of %v", values))
+			}
+
+			object, err := NewObject(prototype, dict)
+			if err != nil {
+				return err
+			}
+			return object
+		})
+}
+
+func _new() NamedValue {
+	return NewGoFunctionWithHelp("new", `Construct an object that inherits from a prototype
+		Usage: new <prototype> <value>*
+		Returns: a new dictionary with given prototype as parent
+
+		When the prototype has an init function, it's called on the new object
+		with all given values as arguments. Its result is ignored unless it's an error.
+
+		Examples:
+
+		> pepper: {
+		>   init: (func name hotness { set this.name $name; set this.hotness $hotness })
+		>   hot: (func { return (gt $this.hotness 1000) })
+		> }
+		> habanero: (new pepper "habanero" 100000)
+		> habanero.hot
+		will result in true`,
+
+		func(context RunContext, arguments []Argument) Value {
+
+			argLen, err := CheckArguments(arguments, 1, math.MaxInt16, "new", "<prototype> <value>*")
+			if err != nil {
+				return err
+			}
+
+			prototype, err := evalPrototype(context, "new", arguments[0])
+			if err != nil {
+				return err
+			}
+
+			object, _ := NewObject(prototype, nil)
+
+			init, found := object.Resolve("init")
+			if !found {
+				if argLen > 1 {
+					return NewErrorValue(fmt.Sprintf("invalid call to new, %v has no init function to pass arguments to", prototype))
+				}
+				return object
+			}
+
+			runnable, isRunnable := init.(Runnable)
+			if !isRunnable || init.Type() != TypeGoFunction {
+				return NewErrorValue(fmt.Sprintf("invalid call to new, init of %v is not a function", prototype))
+			}
+
+			defer bindMethod(context, object, "init", init)()
+
+			if result := runnable.Run(context, arguments[1:]); result.Type() == TypeError {
+				return result
+			}
+
+			return object
+		})
+}
+
+func isA() NamedValue {
+	return NewGoFunctionWithHelp("isA", `Check if a value is an object that inherits from a prototype
+		Usage: isA <value> <prototype>
+		Returns: true when value is the prototype or has it somewhere in its chain of prototypes
+
+		Examples:
+
+		> pepper: {}
+		> chipotle: (extend pepper)
+		> isA $chipotle pepper
+		will result in true`,
+
+		func(context RunContext, arguments []Argument) Value {
+
+			_, err := CheckArguments(arguments, 2, 2, "isA", "<value> <prototype>")
+			if err != nil {
+				return err
+			}
+
+			value := EvalArgument(context, arguments[0])
+			if value.Type() == TypeError {
+				return value
+			}
+
+			prototype, err := evalPrototype(context, "isA", arguments[1])
+			if err != nil {
+				return err
+			}
+
+			return TrueOrFalse(IsA(value, prototype))
+		})
+}
@@ -0,0 +1,151 @@
+package elmo
+
+import "testing"
+
+func TestExtend(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`extend`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`extend 3 {}`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`pepper: {}
+		 extend pepper 3`, ExpectErrorValueAt(t, 2))
+
+	ParseTestAndRunBlock(t,
+		`pepper: {name: "pepper"; hotness: 1}
+		 chipotle: (extend pepper {name: "chipotle"})
+		 [$chipotle.name $chipotle.hotness $pepper.name]`, ExpectValue(t, ParseAndRun(NewGlobalContext(), `["chipotle" 1 "pepper"]`)))
+
+	ParseTestAndRunBlock(t,
+		`pepper: {name: "pepper"}
+		 chipotle: (extend $pepper)
+		 pepper.name: "changed"
+		 chipotle.name`, ExpectValue(t, NewStringLiteral("changed")))
+
+	ParseTestAndRunBlock(t,
+		`pepper: {name: "pepper"}
+		 extras: {hotness: 2}
+		 chipotle: (extend pepper $extras)
+		 [$chipotle.name $chipotle.hotness]`, ExpectValue(t, ParseAndRun(NewGlobalContext(), `["pepper" 2]`)))
+}
+
+func TestThisAndSuper(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`pepper: {
+		   name: "pepper"
+		   describe: (func { return "\{$this.name} pepper" })
+		 }
+		 chipotle: (extend pepper {name: "chipotle"})
+		 chipotle.describe`, ExpectValue(t, NewStringLiteral("chipotle pepper")))
+
+	ParseTestAndRunBlock(t,
+		`pepper: {
+		   name: "pepper"
+		   describe: (func { return "\{$this.name} pepper" })
+		 }
+		 chipotle: (extend pepper {
+		   name: "chipotle"
+		   describe: (func { return "smoked \{(super.describe)}" })
+		 })
+		 morita: (extend chipotle {
+		   name: "morita"
+		   describe: (func { return "dried \{(super.describe)}" })
+		 })
+		 morita.describe`, ExpectValue(t, NewStringLiteral("dried smoked morita pepper")))
+
+	ParseTestAndRunBlock(t,
+		`pepper: {
+		   hotter: (func by { return (plus $this.hotness $by) })
+		 }
+		 habanero: (extend pepper {
+		   hotness: 100
+		   hotter: (func by { return (super.hotter (multiply $by 2)) })
+		 })
+		 habanero.hotter 5`, ExpectValue(t, NewIntegerLiteral(110)))
+
+	// super is found through the object defining the called method, even
+	// when the same function is also stored in one of its extensions
+	//
+	ParseTestAndRunBlock(t,
+		`base: {describe: (func { return "base" })}
+		 middle: (extend base {describe: (func { return "middle \{(super.describe)}" })})
+		 top: (extend middle {})
+		 top.alias: &middle.describe
+		 [(top.describe) (top.alias)]`, ExpectValue(t, ParseAndRun(NewGlobalContext(), `["middle base" "middle middle base"]`)))
+
+	// functions without prototype have no super
+	//
+	ParseTestAndRunBlock(t,
+		`pepper: {
+		   describe: (func { return (super.describe) })
+		 }
+		 pepper.describe`, ExpectErrorValueAt(t, 2))
+}
+
+func TestNew(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`new`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`pepper: {}
+		 new pepper "chipotle"`, ExpectErrorValueAt(t, 2))
+
+	ParseTestAndRunBlock(t,
+		`pepper: {init: 3}
+		 new pepper`, ExpectErrorValueAt(t, 2))
+
+	ParseTestAndRunBlock(t,
+		`pepper: {
+		   init: (func name hotness?1 {
+		     this.name: $name
+		     this.hotness: $hotness
+		   })
+		   hot: (func { return (gt $this.hotness 1000) })
+		 }
+		 habanero: (new pepper "habanero" 100000)
+		 jalapeno: (new pepper "jalapeno")
+		 [(habanero.hot) (jalapeno.hot) $jalapeno.name (isA $jalapeno pepper)]`, ExpectValue(t, ParseAndRun(NewGlobalContext(), `[$true $false "jalapeno" $true]`)))
+
+	ParseTestAndRunBlock(t,
+		`pepper: {
+		   init: (func { error "no peppers today" })
+		 }
+		 new pepper`, ExpectErrorValueAt(t, 2))
+}
+
+func TestIsA(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`isA {}`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`pepper: {}
+		 chipotle: (extend pepper)
+		 morita: (extend chipotle)
+		 [(isA $morita pepper) (isA $morita morita) (isA $pepper chipotle) (isA 3 pepper) (isA {} pepper)]`, ExpectValue(t, ParseAndRun(NewGlobalContext(), `[$true $true $false $false $false]`)))
+}
+
+func TestAssignToDictionary(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`pepper: {sauce: {}}
+		 pepper.sauce.name: "chipotle"
+		 pepper.sauce.name`, ExpectValue(t, NewStringLiteral("chipotle")))
+
+	ParseTestAndRunBlock(t,
+		`pepper: 3
+		 pepper.name: "chipotle"`, ExpectErrorValueAt(t, 2))
+
+	ParseTestAndRunBlock(t,
+		`pepper.name: "chipotle"`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`pepper: {}
+		 freeze! $pepper
+		 pepper.name: "chipotle"`, ExpectErrorValueAt(t, 3))
+}
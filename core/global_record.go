@@ -0,0 +1,110 @@
+package elmo
+
+import "fmt"
+
+// recordFields extracts the fields of a record type from a block of
+// field declarations. Fields are declared like function parameters
+//
+func recordFields(context RunContext, name string, block Block) ([]*parameter, ErrorValue) {
+	declarations := []Value{}
+	for _, c := range block.Calls() {
+		line, isLine := c.(*call)
+		if !isLine || line.function != nil || line.WillPipe() {
+			return nil, NewErrorValue(fmt.Sprintf("invalid fields for %s, expected <field>:<type>?", name))
+		}
+		for _, argument := range declarationArguments(line) {
+			declarations = append(declarations, EvalArgument(context, argument))
+		}
+	}
+
+	fields, err := extractParameters(declarations)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	for _, field := range fields {
+		if field.rest {
+			return nil, NewErrorValue(fmt.Sprintf("invalid field %s for %s, fields can not collect other values", field.name, name))
+		}
+		if seen[field.name] {
+			return nil, NewErrorValue(fmt.Sprintf("field %s of %s is declared twice", field.name, name))
+		}
+		if field.defaultValue != nil && !field.accepts(field.defaultValue) {
+			return nil, NewErrorValue(fmt.Sprintf("invalid default value for field %s of %s: expected %s, not %s", field.name, name, field.typeName, field.defaultValue.Info().Name()))
+		}
+		seen[field.name] = true
+	}
+	return fields, nil
+}
+
+func deftype() NamedValue {
+	return NewGoFunctionWithHelp("deftype", `Declare a record type
+		Usage: deftype <name> {<field>*} <invariant>?
+		Returns: the constructor of the new type, which is also stored under given name
+
+		Fields are declared like function parameters. A field can be annotated
+		with a type (x:int) and can be optional with a default value (x:int?0).
+		The constructor accepts field values by position or by name and checks
+		them against the type of their field. Fields of a record are checked
+		again whenever they are changed. Records can't get other fields and
+		their fields can't be removed.
+
+		The optional invariant is a function that's called with the record as
+		this whenever a record is created or changed. It must return true
+		for the record to be valid.
+
+		Examples:
+
+		> deftype Point {x:int y:int}
+		> p: (Point 1 2)
+		> type $p
+		will result in Point
+
+		> deftype Range {from:int to:int} (func { return (lte $this.from $this.to) })
+		> Range from=3 to=1
+		will result in an error`,
+
+		func(context RunContext, arguments []Argument) Value {
+
+			argLen, err := CheckArguments(arguments, 2, 3, "deftype", "<name> {<field>*} <invariant>?")
+			if err != nil {
+				return err
+			}
+
+			name := EvalArgument2String(context, arguments[0])
+
+			block, isBlock := EvalArgument(context, arguments[1]).(Block)
+			if !isBlock {
+				return NewErrorValue("invalid call to deftype, expected a block with fields as second argument")
+			}
+
+			fields, err := recordFields(context, name, block)
+			if err != nil {
+				return err
+			}
+
+			recordType := &recordType{info: NewTypeInfo(name), fields: fields, context: context}
+
+			if argLen == 3 {
+				invariant := EvalArgument(context, arguments[2])
+				runnable, isRunnable := invariant.(Runnable)
+				if !isRunnable || invariant.Type() != TypeGoFunction {
+					return NewErrorValue(fmt.Sprintf("invalid call to deftype, invariant of %s is not a function", name))
+				}
+				recordType.invariant = runnable
+			}
+
+			registerRecordType(context, recordType)
+
+			constructor := NewGoFunctionWithKeywords(name, fmt.Sprintf(`Construct a %s
+				Usage: %s %s`, name, name, parametersUsage(fields)), parameterNames(fields),
+				func(context RunContext, arguments []Argument) Value {
+					return recordType.construct(context, arguments)
+				})
+
+			context.Set(name, constructor)
+
+			return constructor
+		})
+}
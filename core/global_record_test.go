@@ -0,0 +1,165 @@
+package elmo
+
+import "testing"
+
+func TestDeftype(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`deftype Point`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`deftype Point 3`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`deftype Point {x:int x:int}`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`deftype Point {x:int points:int...}`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`deftype Point {x:int?"zero"}`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`deftype Point {x:int y:int} 3`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`deftype Point {x:int y:int}
+		 type (Point 1 2)`, ExpectValue(t, NewIdentifier("Point")))
+
+	ParseTestAndRunBlock(t,
+		`deftype Point {x:int y:int}
+		 p: (Point y=2 x=1)
+		 [$p.x $p.y]`, ExpectValue(t, ParseAndRun(NewGlobalContext(), `[1 2]`)))
+
+	ParseTestAndRunBlock(t,
+		`deftype Point {
+		   x:int
+		   y:int?0
+		   label?
+		 }
+		 p: (Point 1)
+		 to_s $p`, ExpectValue(t, NewStringLiteral("{x: 1; y: 0}")))
+
+	ParseTestAndRunBlock(t,
+		`deftype Point {x:int y:int}
+		 Point 1`, ExpectErrorValueAt(t, 2))
+
+	ParseTestAndRunBlock(t,
+		`deftype Point {x:int y:int}
+		 Point 1 "2"`, ExpectErrorValueAt(t, 2))
+
+	ParseTestAndRunBlock(t,
+		`deftype Point {x:int y:int}
+		 deftype Line {from:Point to:Point}
+		 l: (Line (Point 1 1) (Point 2 2))
+		 type $l.to`, ExpectValue(t, NewIdentifier("Point")))
+
+	ParseTestAndRunBlock(t,
+		`deftype Point {x:int y:int}
+		 deftype Line {from:Point to:Point}
+		 Line {x: 1; y: 1} (Point 2 2)`, ExpectErrorValueAt(t, 3))
+}
+
+func TestRecordMutation(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`deftype Point {x:int y:int}
+		 p: (Point 1 2)
+		 p.x: 3
+		 p.x`, ExpectValue(t, NewIntegerLiteral(3)))
+
+	ParseTestAndRunBlock(t,
+		`deftype Point {x:int y:int}
+		 p: (Point 1 2)
+		 p.x: "3"`, ExpectErrorValueAt(t, 3))
+
+	ParseTestAndRunBlock(t,
+		`deftype Point {x:int y:int}
+		 p: (Point 1 2)
+		 p.z: 3`, ExpectErrorValueAt(t, 3))
+
+	ParseTestAndRunBlock(t,
+		`deftype Point {x:int y:int}
+		 p: (Point 1 2)
+		 freeze! $p
+		 p.x: 3`, ExpectErrorValueAt(t, 4))
+}
+
+func TestRecordInvariant(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`deftype Range {from:int to:int} (func { return (lte $this.from $this.to) })
+		 r: (Range 1 3)
+		 r.from: 2
+		 r.from`, ExpectValue(t, NewIntegerLiteral(2)))
+
+	ParseTestAndRunBlock(t,
+		`deftype Range {from:int to:int} (func { return (lte $this.from $this.to) })
+		 Range 3 1`, ExpectErrorValueAt(t, 2))
+
+	// values that break the invariant are not stored
+	//
+	ParseTestAndRunBlock(t,
+		`deftype Range {from:int to:int} (func { return (lte $this.from $this.to) })
+		 r: (Range 1 3)
+		 try { r.from: 5 } catch e {}
+		 r.from`, ExpectValue(t, NewIntegerLiteral(1)))
+
+	ParseTestAndRunBlock(t,
+		`deftype Range {from:int to:int} (func { return 1 })
+		 Range 1 3`, ExpectErrorValueAt(t, 2))
+}
+
+func TestRecordToBinaryAndBack(t *testing.T) {
+
+	context := NewGlobalContext()
+	line := ParseAndRun(context,
+		`deftype Point {x:int y:int}
+		 deftype Line {from:Point to:Point}
+		 Line (Point 1 1) (Point 2 2)`)
+
+	context.Set("copy", line.(SerializableValue).ToBinary().(BinaryValue).ToRegularWithinContext(context))
+
+	ParseTestAndRunBlockWithinContext(t, context,
+		`[(type $copy) (type $copy.from) $copy.to.x]`, ExpectValue(t, ParseAndRun(NewGlobalContext(), `[Line Point 2]`)))
+
+	// fields of reconstructed records are checked as well
+	//
+	ParseTestAndRunBlockWithinContext(t, context,
+		`copy.from: 3`, ExpectErrorValueAt(t, 1))
+
+	// record types are only known within the execution that declared them
+	//
+	other := NewGlobalContext()
+	other.Set("copy", line.(SerializableValue).ToBinary().(BinaryValue).ToRegularWithinContext(other))
+
+	ParseTestAndRunBlockWithinContext(t, other,
+		`type $copy`, ExpectValue(t, NewIdentifier("dict")))
+}
+
+func TestReconstructedRecordsAreChecked(t *testing.T) {
+
+	reconstruct := func(from string, within string) Value {
+		record := ParseAndRun(NewGlobalContext(), from)
+		context := NewGlobalContext()
+		ParseAndRun(context, within)
+		return record.(SerializableValue).ToBinary().(BinaryValue).ToRegularWithinContext(context)
+	}
+
+	if value := reconstruct(`deftype Point {x:int y:int}; Point 1 2`, `deftype Point {x:int y:int}`); value.Type() != TypeDictionary {
+		t.Error("expected a record, not", value)
+	}
+
+	if value := reconstruct(`deftype Point {x:string y:int}; Point "1" 2`, `deftype Point {x:int y:int}`); value.Type() != TypeError {
+		t.Error("expected an error for a field of the wrong type, not", value)
+	}
+
+	if value := reconstruct(`deftype Point {x:int}; Point 1`, `deftype Point {x:int y:int}`); value.Type() != TypeError {
+		t.Error("expected an error for a missing field, not", value)
+	}
+
+	if value := reconstruct(`deftype Range {from:int to:int}; Range 3 1`,
+		`deftype Range {from:int to:int} (func { return (lte $this.from $this.to) })`); value.Type() != TypeError {
+		t.Error("expected an error for a broken invariant, not", value)
+	}
+}
@@ -1,6 +1,10 @@
 package elmo
 
-import "sync"
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
 
 // GlobalSettingData holds all global settings
 //
@@ -8,6 +12,14 @@ type GlobalSettingData struct {
 	Debug     bool
 	HotReload bool
 	StartRepl bool
+
+	// VM runs code using the bytecode vm instead of
+	// interpreting the syntax tree
+	VM bool
+
+	// Path holds the folders scripts are loaded from when they can not
+	// be found relative from the loading script, initialized by ELMOPATH
+	Path []string
 }
 
 var createGlobalSettingsOnce sync.Once
@@ -17,7 +29,8 @@ var globalSettingsSingletonDictionary DictionaryValue
 func createGlobalSettingSingletons() {
 	createGlobalSettingsOnce.Do(func() {
 
-		globalSettingSingleton = &GlobalSettingData{}
+		globalSettingSingleton = &GlobalSettingData{VM: os.Getenv("ELMO_VM") != "",
+			Path: filepath.SplitList(os.Getenv("ELMOPATH"))}
 		globalSettingsSingletonDictionary = NewDictionaryFromStruct(nil, globalSettingSingleton)
 
 	})
@@ -45,6 +58,9 @@ func globalSettings() NamedValue {
 
 	return NewGoFunctionWithHelp("globalSettings", `returns a dictionary with global elmo settings`,
 		func(context RunContext, arguments []Argument) Value {
+			if policy := context.Policy(); policy != nil && !policy.Settings {
+				return NewNotPermittedErrorValue("accessing global settings")
+			}
 			return GlobalSettingsDictionary()
 		})
 }
@@ -19,6 +19,69 @@ func TestAssignBlockWithLet(t *testing.T) {
 	ParseTestAndRunBlock(t, "let peppers {jalapeno:1; habanero: 2} |type", ExpectValue(t, NewIdentifier("block")))
 }
 
+func TestSetWithListShape(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`set [a b] [1 2]`, ExpectValueSetTo(t, "a", "1"), ExpectValueSetTo(t, "b", "2"))
+
+	ParseTestAndRunBlock(t,
+		`[a b]: ["chipotle" "jalapeno" "habanero"]`, ExpectValueSetTo(t, "b", "jalapeno"))
+
+	ParseTestAndRunBlock(t,
+		`set [a b ...rest] [1 2 3 4]
+		 $rest`, ExpectValue(t, NewListValue([]Value{NewIntegerLiteral(3), NewIntegerLiteral(4)})))
+
+	ParseTestAndRunBlock(t,
+		`set [a rest...] [1]
+		 $rest`, ExpectValue(t, NewListValue([]Value{})))
+
+	ParseTestAndRunBlock(t,
+		`set [a [b c]] [1 [2 3]]
+		 $c`, ExpectValue(t, NewIntegerLiteral(3)))
+
+	ParseTestAndRunBlock(t,
+		`set [a b?"habanero"] ["chipotle"]
+		 $b`, ExpectValue(t, NewStringLiteral("habanero")))
+
+	ParseTestAndRunBlock(t,
+		`set [a b] [1]`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`set [a b] "chipotle"`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`set [a:int b:int] [1 "2"]`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`set [...rest a] [1 2]`, ExpectErrorValueAt(t, 1))
+}
+
+func TestSetWithDictionaryShape(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`set {name hotness} {name: "chipotle"; hotness: 3}`,
+		ExpectValueSetTo(t, "name", "chipotle"), ExpectValueSetTo(t, "hotness", "3"))
+
+	ParseTestAndRunBlock(t,
+		`pepper: {name: "chipotle"; origin: {country: "mexico"}}
+		 set {name: n; origin: {country}} $pepper
+		 "\{$n} from \{$country}"`, ExpectValue(t, NewStringLiteral("chipotle from mexico")))
+
+	ParseTestAndRunBlock(t,
+		`set {name hotness?0} {name: "chipotle"}
+		 $hotness`, ExpectValue(t, NewIntegerLiteral(0)))
+
+	ParseTestAndRunBlock(t,
+		`set {peppers: [first]} {peppers: ["chipotle"]}
+		 $first`, ExpectValue(t, NewStringLiteral("chipotle")))
+
+	ParseTestAndRunBlock(t,
+		`set {name hotness} {name: "chipotle"}`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`set {name} [1 2]`, ExpectErrorValueAt(t, 1))
+}
+
 func TestEcho(t *testing.T) {
 	ParseTestAndRunBlock(t, `echo chipotle`, ExpectValue(t, NewIdentifier("chipotle")))
 	ParseTestAndRunBlock(t, `echo chipotle # njam`, ExpectValue(t, NewIdentifier("chipotle")))
@@ -0,0 +1,177 @@
+package elmo
+
+import "fmt"
+
+type tryClauses struct {
+	code       Block
+	catchName  string
+	catch      Block
+	catchFatal bool
+	finally    Block
+}
+
+func blockArgument(context RunContext, argument Argument, name string) (Block, ErrorValue) {
+	value := EvalArgument(context, argument)
+	if value.Type() != TypeBlock {
+		return nil, NewErrorValue(fmt.Sprintf("invalid call to try, expected a block after %s", name))
+	}
+	return value.(Block), nil
+}
+
+func parseTryClauses(context RunContext, arguments []Argument) (*tryClauses, ErrorValue) {
+
+	argLen := len(arguments)
+	if argLen == 0 {
+		return nil, NewErrorValue("Invalid call to try. Usage: try {...} (catch <identifier>? {...})? (finally {...})?")
+	}
+
+	code, err := blockArgument(context, arguments[0], "try")
+	if err != nil {
+		return nil, err
+	}
+
+	clauses := &tryClauses{code: code}
+
+	for i := 1; i < argLen; i++ {
+
+		if arguments[i].Type() != TypeIdentifier {
+			return nil, NewErrorValue(fmt.Sprintf("invalid call to try, expected catch or finally instead of %v", arguments[i]))
+		}
+
+		switch keyword := arguments[i].String(); keyword {
+		case "catch", "catch!":
+			if clauses.catch != nil {
+				return nil, NewErrorValue("invalid call to try, only one catch is allowed")
+			}
+			clauses.catchFatal = (keyword == "catch!")
+
+			if i+1 < argLen && arguments[i+1].Type() == TypeIdentifier {
+				i++
+				clauses.catchName = arguments[i].String()
+			}
+
+			if i+1 >= argLen {
+				return nil, NewErrorValue(fmt.Sprintf("invalid call to try, expected a block after %s", keyword))
+			}
+			i++
+			if clauses.catch, err = blockArgument(context, arguments[i], keyword); err != nil {
+				return nil, err
+			}
+
+		case "finally":
+			if clauses.finally != nil {
+				return nil, NewErrorValue("invalid call to try, only one finally is allowed")
+			}
+			if i+1 >= argLen {
+				return nil, NewErrorValue("invalid call to try, expected a block after finally")
+			}
+			i++
+			if clauses.finally, err = blockArgument(context, arguments[i], keyword); err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, NewErrorValue(fmt.Sprintf("invalid call to try, expected catch or finally instead of %s", keyword))
+		}
+	}
+
+	return clauses, nil
+}
+
+// canCatch checks if given value is an error that would otherwise have
+// stopped the execution of a block
+//
+func (clauses *tryClauses) canCatch(value Value) bool {
+	if clauses.catch == nil || value.Type() != TypeError {
+		return false
+	}
+
+	err := value.(ErrorValue)
+	if err.CanBeIgnored() || err.IsAborted() {
+		return false
+	}
+
+	return clauses.catchFatal || !err.IsFatal()
+}
+
+// runDetached runs a block in a context that shares all variables but
+// which is stopped independently. It returns the result of the block and
+// whether the block was stopped by a return
+//
+func runDetached(context RunContext, block Block) (Value, bool) {
+	detached := context.detach()
+	result := block.Run(detached, NoArguments)
+	return result, detached.isStopped() && result.Type() != TypeError
+}
+
+func try() NamedValue {
+	return NewGoFunctionWithHelp("try", `Execute a block of code and recover from errors
+		Usage: try {...} (catch <identifier>? {...})? (finally {...})?
+		Returns: value of the try block, or value of the catch block when an error occurred
+
+		When the try block results in an error, the catch block is executed with
+		the error available as a dictionary containing:
+		  message (string)
+		  fatal (boolean)
+		  file (string, when known)
+		  line (integer, when known)
+		  trace (origin and function, script and module calls, as dictionaries
+		         with script, line, column and function)
+		  error (the original error value)
+
+		The finally block is always executed, after the try and catch blocks.
+
+		Fatal errors, like the ones created by panic, are only caught when
+		catch! is used instead of catch. Errors caused by cancellation or by
+		exceeding execution limits can not be caught.
+
+		Note, when an identifier is given, the catch block is executed in its own
+		scope so the error does not override existing variables.
+
+		Examples:
+
+		> try {
+		>   error "no more chipotles"
+		> } catch e {
+		>   puts $e.message
+		> } finally {
+		>   puts "done"
+		> }
+
+		> try { panic "out of sauce" } catch! e { "recovered" }
+		will result in "recovered"`,
+
+		func(context RunContext, arguments []Argument) Value {
+
+			clauses, err := parseTryClauses(context, arguments)
+			if err != nil {
+				return err
+			}
+
+			result, returned := runDetached(context, clauses.code)
+
+			if clauses.canCatch(result) {
+				catchContext := context
+				if clauses.catchName != "" {
+					catchContext = context.CreateSubContext()
+					catchContext.Set(clauses.catchName, errorDictionary(result.(ErrorValue)))
+				}
+				result, returned = runDetached(catchContext, clauses.catch)
+			}
+
+			if clauses.finally != nil {
+				if final, _ := runDetached(context, clauses.finally); final.Type() == TypeError && !final.(ErrorValue).CanBeIgnored() {
+					return final
+				}
+			}
+
+			// a return within try or catch should also stop
+			// the code calling try
+			//
+			if returned {
+				context.Stop()
+			}
+
+			return result
+		})
+}
@@ -0,0 +1,133 @@
+package elmo
+
+import "testing"
+
+func TestTry(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`try`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`try 3`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`try {} chipotle {}`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`try {} catch`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`try { "chipotle" }`, ExpectValue(t, NewStringLiteral("chipotle")))
+
+	ParseTestAndRunBlock(t,
+		`try { "chipotle" } catch e { "jalapeno" }`, ExpectValue(t, NewStringLiteral("chipotle")))
+
+	ParseTestAndRunBlock(t,
+		`try { error "chipotle" } catch e { "jalapeno" }`, ExpectValue(t, NewStringLiteral("jalapeno")))
+
+	ParseTestAndRunBlock(t,
+		`try { error "chipotle" } catch { "jalapeno" }`, ExpectValue(t, NewStringLiteral("jalapeno")))
+
+	ParseTestAndRunBlock(t,
+		`try { error "chipotle" } catch e { $e.message }`, ExpectValue(t, NewStringLiteral("chipotle")))
+
+	ParseTestAndRunBlock(t,
+		`try {
+		   error "chipotle"
+		 } catch e { $e.line }`, ExpectValue(t, NewIntegerLiteral(2)))
+
+	ParseTestAndRunBlock(t,
+		`try { error "chipotle" } catch e { $e.fatal }`, ExpectValue(t, False))
+
+	// code after the error should not be executed
+	//
+	ParseTestAndRunBlock(t,
+		`pepper: "jalapeno"
+		 try {
+		   error "chipotle"
+		   pepper: "habanero"
+		 } catch {}
+		 pepper`, ExpectValue(t, NewStringLiteral("jalapeno")))
+
+	// code after try should be executed
+	//
+	ParseTestAndRunBlock(t,
+		`try { error "chipotle" } catch {}
+		 "habanero"`, ExpectValue(t, NewStringLiteral("habanero")))
+
+	// without catch, errors are not caught
+	//
+	ParseTestAndRunBlock(t,
+		`try {
+		   error "chipotle"
+		 }
+		 "habanero"`, ExpectErrorValueAt(t, 2))
+}
+
+func TestTryWithFatalErrors(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`try {
+		   panic "chipotle"
+		 } catch e { "jalapeno" }`, ExpectErrorValueAt(t, 2))
+
+	ParseTestAndRunBlock(t,
+		`try { panic "chipotle" } catch! e { $e.fatal }`, ExpectValue(t, True))
+
+	ParseTestAndRunBlock(t,
+		`try { load "no_such_script" } catch! e { "jalapeno" }`, ExpectValue(t, NewStringLiteral("jalapeno")))
+}
+
+func TestTryWithFinally(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`try { "chipotle" } finally { pepper: "jalapeno" }
+		 pepper`, ExpectValue(t, NewStringLiteral("jalapeno")))
+
+	ParseTestAndRunBlock(t,
+		`try { "chipotle" } finally { "jalapeno" }`, ExpectValue(t, NewStringLiteral("chipotle")))
+
+	ParseTestAndRunBlock(t,
+		`try { error "chipotle" } catch { "habanero" } finally { pepper: "jalapeno" }
+		 pepper`, ExpectValue(t, NewStringLiteral("jalapeno")))
+
+	ParseTestAndRunBlock(t,
+		`try { error "chipotle" } catch { error "habanero" } finally { pepper: "jalapeno" }`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`result: (try { error "chipotle" } finally { pepper: "jalapeno" })
+		 pepper`, ExpectValue(t, NewStringLiteral("jalapeno")))
+
+	ParseTestAndRunBlock(t,
+		`result: (try { error "chipotle" } finally {})
+		 type $result`, ExpectValue(t, NewIdentifier("error")))
+
+	ParseTestAndRunBlock(t,
+		`try { "chipotle" } finally {
+		   error "jalapeno"
+		 }`, ExpectErrorValueAt(t, 2))
+}
+
+func TestTryWithReturn(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`f: (func {
+		   try { return "chipotle" } finally {}
+		   return "jalapeno"
+		 })
+		 f`, ExpectValue(t, NewStringLiteral("chipotle")))
+
+	ParseTestAndRunBlock(t,
+		`f: (func {
+		   try { error "chipotle" } catch { return "habanero" }
+		   return "jalapeno"
+		 })
+		 f`, ExpectValue(t, NewStringLiteral("habanero")))
+
+	ParseTestAndRunBlock(t,
+		`f: (func {
+		   try { error "chipotle" } catch {}
+		   return "jalapeno"
+		 })
+		 f`, ExpectValue(t, NewStringLiteral("jalapeno")))
+}
@@ -0,0 +1,144 @@
+package elmo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// hashPrefix starts the hash key of all values that are not strings or
+// identifiers. Strings and identifiers are their own hash key so they can
+// be used to access dictionaries by name
+//
+const hashPrefix = "\x00"
+
+// HashKey returns a key that is the same for values that are structurally
+// equal. Strings, identifiers, numbers, booleans, nil and frozen lists,
+// dictionaries and sets can be hashed. Exact numbers (integers and decimals)
+// with the same value share their key
+//
+func HashKey(value Value) (string, ErrorValue) {
+	return hashKey(value, nil)
+}
+
+// stringHashKey returns the hash key of a string or identifier
+//
+func stringHashKey(s string) string {
+	if strings.HasPrefix(s, hashPrefix) {
+		return hashPrefix + "s" + s
+	}
+	return s
+}
+
+// nameOfHashKey converts the hash key of a string back into the string
+//
+func nameOfHashKey(key string) string {
+	if strings.HasPrefix(key, hashPrefix+"s") {
+		return key[2:]
+	}
+	return key
+}
+
+func isStringHashKey(key string) bool {
+	return !strings.HasPrefix(key, hashPrefix) || strings.HasPrefix(key, hashPrefix+"s")
+}
+
+func notHashable(value Value) ErrorValue {
+	return NewErrorValue(fmt.Sprintf("can not use %v of type %v as key, only strings, numbers, booleans and frozen lists, dictionaries and sets can be hashed", value, value.Info().Name()))
+}
+
+// hashKey hashes a value. Visited holds all lists, dictionaries and sets
+// that contain given value so circular structures can be detected
+//
+func hashKey(value Value, visited map[Value]bool) (string, ErrorValue) {
+
+	switch value.Type() {
+	case TypeError:
+		return "", value.(ErrorValue)
+	case TypeString, TypeIdentifier:
+		return stringHashKey(value.String()), nil
+	case TypeNil:
+		return hashPrefix + "z", nil
+	case TypeBoolean:
+		return hashPrefix + "b" + value.String(), nil
+	case TypeInteger:
+		return hashPrefix + "n" + value.String(), nil
+	case TypeBigInteger, TypeDecimal:
+		rat, _ := ToRat(value)
+		return hashPrefix + "n" + rat.RatString(), nil
+	case TypeFloat:
+		f := value.Internal().(float64)
+		if f == 0 {
+			f = 0 // -0 and 0 are equal
+		}
+		if math.IsNaN(f) {
+			return "", notHashable(value)
+		}
+		return hashPrefix + "f" + strconv.FormatFloat(f, 'g', -1, 64), nil
+	}
+
+	freezable, isFreezable := value.(FreezableValue)
+	if !isFreezable || !freezable.Frozen() {
+		return "", notHashable(value)
+	}
+
+	if visited[value] {
+		return "", NewErrorValue("can not hash circular value")
+	}
+	if visited == nil {
+		visited = map[Value]bool{}
+	}
+	visited[value] = true
+	defer delete(visited, value)
+
+	var parts []string
+	var tag string
+
+	switch value.Type() {
+	case TypeList:
+		tag = "l"
+		for _, element := range value.(ListValue).List() {
+			key, err := hashKey(element, visited)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, key)
+		}
+	case TypeDictionary:
+		tag = "m"
+		dict := value.(DictionaryValue)
+		for _, k := range dict.KeyValues() {
+			v, _ := dict.ResolveKey(k)
+			key, err := hashKey(k, visited)
+			if err != nil {
+				return "", err
+			}
+			hashedValue, err := hashKey(v, visited)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, lengthPrefixed(key)+lengthPrefixed(hashedValue))
+		}
+		sort.Strings(parts)
+	case TypeSet:
+		tag = "e"
+		parts = value.(SetValue).hashKeys()
+		sort.Strings(parts)
+	default:
+		return "", notHashable(value)
+	}
+
+	var builder strings.Builder
+	builder.WriteString(hashPrefix)
+	builder.WriteString(tag)
+	for _, part := range parts {
+		builder.WriteString(lengthPrefixed(part))
+	}
+	return builder.String(), nil
+}
+
+func lengthPrefixed(s string) string {
+	return strconv.Itoa(len(s)) + ":" + s
+}
@@ -0,0 +1,153 @@
+package elmo
+
+import (
+	"fmt"
+	"testing"
+)
+
+func frozenList(values ...Value) Value {
+	return NewListValue(values).(FreezableValue).Freeze()
+}
+
+func TestHashKey(t *testing.T) {
+
+	hash := func(value Value) string {
+		key, err := HashKey(value)
+		if err != nil {
+			t.Errorf("expected %v to be hashable, found %v", value, err)
+		}
+		return key
+	}
+
+	two, _ := NewDecimalFromString("2.00")
+
+	if hash(NewIntegerLiteral(1)) == hash(NewStringLiteral("1")) {
+		t.Error("expected integer 1 and string 1 to differ")
+	}
+
+	if hash(NewIntegerLiteral(2)) != hash(two) {
+		t.Error("expected integer 2 and decimal 2.00 to be the same")
+	}
+
+	if hash(NewIntegerLiteral(2)) == hash(NewFloatLiteral(2)) {
+		t.Error("expected integer 2 and float 2 to differ")
+	}
+
+	if hash(NewStringLiteral("chipotle")) != hash(NewIdentifier("chipotle")) {
+		t.Error("expected strings and identifiers to be the same")
+	}
+
+	if hash(NewStringLiteral("\x00n1")) == hash(NewIntegerLiteral(1)) {
+		t.Error("expected strings to never look like other values")
+	}
+
+	if _, err := HashKey(NewListValue([]Value{NewIntegerLiteral(1)})); err == nil {
+		t.Error("expected unfrozen list to be unhashable")
+	}
+
+	list1 := frozenList(NewIntegerLiteral(1), NewStringLiteral("2"))
+	list2 := frozenList(NewIntegerLiteral(1), NewStringLiteral("2"))
+	list3 := frozenList(NewIntegerLiteral(1), NewIntegerLiteral(2))
+
+	if hash(list1) != hash(list2) {
+		t.Error("expected frozen lists with equal values to be the same")
+	}
+
+	if hash(list1) == hash(list3) {
+		t.Error("expected frozen lists with different values to differ")
+	}
+
+	dict1 := NewOrderedDictionaryValue(nil, []string{"a", "b"}, map[string]Value{"a": True, "b": False})
+	dict2 := NewOrderedDictionaryValue(nil, []string{"b", "a"}, map[string]Value{"a": True, "b": False})
+	dict1.(FreezableValue).Freeze()
+	dict2.(FreezableValue).Freeze()
+
+	if hash(dict1) != hash(dict2) {
+		t.Error("expected frozen dictionaries with the same keys and values to be the same")
+	}
+
+	if _, err := HashKey(NewGoFunctionWithHelp("f", "", func(RunContext, []Argument) Value { return Nothing })); err == nil {
+		t.Error("expected functions to be unhashable")
+	}
+}
+
+func TestDictionaryWithValueKeys(t *testing.T) {
+
+	key := frozenList(NewIntegerLiteral(1))
+
+	dict := NewDictionaryFromList(nil, []Value{
+		NewIntegerLiteral(1), NewStringLiteral("integer"),
+		NewStringLiteral("1"), NewStringLiteral("string"),
+		key, NewStringLiteral("list")}).(DictionaryValue)
+
+	if value, _ := dict.ResolveKey(NewIntegerLiteral(1)); value.String() != "integer" {
+		t.Errorf("expected integer key to resolve, found %v", value)
+	}
+
+	if value, _ := dict.Resolve("1"); value.String() != "string" {
+		t.Errorf("expected string key to resolve by name, found %v", value)
+	}
+
+	if value, _ := dict.ResolveKey(frozenList(NewIntegerLiteral(1))); value.String() != "list" {
+		t.Errorf("expected equal list to resolve, found %v", value)
+	}
+
+	if keys := fmt.Sprint(dict.KeyValues()); keys != "[1 1 [1]]" {
+		t.Errorf("expected keys in insertion order, found %s", keys)
+	}
+
+	if _, err := dict.Set(NewListValue([]Value{}), True); err == nil {
+		t.Error("expected unfrozen list to be refused as key")
+	}
+
+	reconstructed := reconstruct(dict).(DictionaryValue)
+	if value, _ := reconstructed.ResolveKey(key); value.String() != "list" {
+		t.Errorf("expected list key to survive serialization, found %v", reconstructed)
+	}
+	if value, _ := reconstructed.ResolveKey(NewIntegerLiteral(1)); value.String() != "integer" {
+		t.Errorf("expected integer key to survive serialization, found %v", reconstructed)
+	}
+}
+
+func TestSet(t *testing.T) {
+
+	set1, _ := NewSetValue([]Value{NewIntegerLiteral(1), NewIntegerLiteral(2), NewIntegerLiteral(2)})
+	set2, _ := NewSetValue([]Value{NewIntegerLiteral(3), NewIntegerLiteral(2)})
+
+	if set1.String() != "#{1 2}" {
+		t.Errorf("expected duplicates to be ignored, found %v", set1)
+	}
+
+	if !set1.Contains(NewIntegerLiteral(1)) || set1.Contains(NewStringLiteral("1")) {
+		t.Error("expected set to contain integer 1 only")
+	}
+
+	if union := set1.Union(set2); union.String() != "#{1 2 3}" {
+		t.Errorf("expected union, found %v", union)
+	}
+
+	if intersection := set1.Intersection(set2); intersection.String() != "#{2}" {
+		t.Errorf("expected intersection, found %v", intersection)
+	}
+
+	if difference := set1.Difference(set2); difference.String() != "#{1}" {
+		t.Errorf("expected difference, found %v", difference)
+	}
+
+	if _, err := set1.Add(NewListValue([]Value{})); err == nil {
+		t.Error("expected unfrozen list to be refused")
+	}
+
+	reconstructed := reconstruct(set1)
+	if reconstructed.Type() != TypeSet || reconstructed.String() != "#{1 2}" {
+		t.Errorf("expected set to survive serialization, found %v", reconstructed)
+	}
+
+	if compared, _ := set1.(ComparableValue).Compare(nil, reconstructed); compared != 0 {
+		t.Error("expected reconstructed set to be equal")
+	}
+
+	if converted := fmt.Sprint(ConvertValueToInterface(set1)); converted != "[1 2]" {
+		t.Errorf("expected set to convert to an array, found %s", converted)
+	}
+}
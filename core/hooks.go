@@ -0,0 +1,114 @@
+package elmo
+
+import "fmt"
+
+// Hooks are functions stored in a dictionary under a reserved key. Build in
+// functions call the hook of a dictionary, with the dictionary as this,
+// so dictionaries can behave like build in values
+//
+const (
+	hookCompare = "_compare"
+	hookEq      = "_eq"
+	hookString  = "_string"
+	hookLen     = "_len"
+	hookCall    = "_call"
+	hookClose   = "_close"
+	hookIter    = "_iter"
+	hookIncr    = "_incr"
+)
+
+// runHook runs the hook of a dictionary, if it has one
+//
+func runHook(context RunContext, value Value, hook string, arguments []Argument) (Value, bool) {
+	dict, isDict := value.(DictionaryValue)
+	if !isDict {
+		return nil, false
+	}
+
+	function, found := dict.Resolve(hook)
+	if !found {
+		return nil, false
+	}
+
+	runnable, isRunnable := function.(Runnable)
+	if !isRunnable || function.Type() != TypeGoFunction {
+		return NewErrorValue(fmt.Sprintf("found %s is not runnable", hook)), true
+	}
+
+	subContext := context.CreateSubContext()
+	bindMethod(subContext, dict, hook, function)
+
+	return runnable.Run(subContext, arguments), true
+}
+
+// runValueHook runs the hook of a dictionary with values as arguments
+//
+func runValueHook(context RunContext, value Value, hook string, values ...Value) (Value, bool) {
+	arguments := make([]Argument, len(values))
+	for i, v := range values {
+		arguments[i] = NewDynamicArgument(v)
+	}
+	return runHook(context, value, hook, arguments)
+}
+
+// StringOf converts a value to a string. Dictionaries with a _string hook
+// are converted by their hook, all other values by their String function
+//
+func StringOf(context RunContext, value Value) string {
+	if result, hooked := runValueHook(context, value, hookString); hooked {
+		return result.String()
+	}
+	return value.String()
+}
+
+// equalsByHook checks if a dictionary with an _eq hook equals another value.
+// When value has no _eq hook, the hook of other is used. Returns nil when
+// neither has an _eq hook
+//
+func equalsByHook(context RunContext, value Value, other Value) Value {
+	result, hooked := runValueHook(context, value, hookEq, other)
+	if !hooked {
+		result, hooked = runValueHook(context, other, hookEq, value)
+	}
+	if !hooked {
+		return nil
+	}
+	if result.Type() != TypeError && result.Type() != TypeBoolean {
+		return NewErrorValue(fmt.Sprintf("found %s did not return a boolean", hookEq))
+	}
+	return result
+}
+
+// compareByHook compares a dictionary with a _compare hook with another value
+//
+func compareByHook(context RunContext, value Value, other Value) (int, ErrorValue, bool) {
+	result, hooked := runValueHook(context, value, hookCompare, other)
+	if !hooked {
+		return 0, nil, false
+	}
+	if result.Type() == TypeError {
+		return -1, result.(ErrorValue), true
+	}
+	if result.Type() != TypeInteger {
+		return -1, NewErrorValue(fmt.Sprintf("found %s did not return an integer", hookCompare)), true
+	}
+	return int(result.Internal().(int64)), nil, true
+}
+
+// arithmeticByHook runs the hook of a dictionary implementing an arithmetic
+// operation like _plus. When the left operand has no hook, the right operand
+// is asked with a reflected hook like _rminus, which gets the left operand.
+// Commutative operations also use the normal hook of the right operand
+//
+func arithmeticByHook(context RunContext, name string, commutative bool, v1 Value, v2 Value) (Value, bool) {
+	if result, hooked := runValueHook(context, v1, "_"+name, v2); hooked {
+		return result, true
+	}
+	if result, hooked := runValueHook(context, v2, "_r"+name, v1); hooked {
+		return result, true
+	}
+	if commutative {
+		return runValueHook(context, v2, "_"+name, v1)
+	}
+	return nil, false
+}
@@ -0,0 +1,159 @@
+package elmo
+
+import "testing"
+
+const testVector = `vector: {
+  init: (func x y { this.x: $x; this.y: $y })
+  _plus: (func other { return (new vector (plus $this.x $other.x) (plus $this.y $other.y)) })
+  _minus: (func other { return (new vector (minus $this.x $other.x) (minus $this.y $other.y)) })
+  _multiply: (func factor { return (new vector (multiply $this.x $factor) (multiply $this.y $factor)) })
+  _string: (func { return "(\{$this.x}, \{$this.y})" })
+  _len: (func { return 2 })
+  _eq: (func other { return (and (eq $this.x $other.x) (eq $this.y $other.y)) })
+  _iter: (func { return [$this.x $this.y] })
+  _call: (func i { if (eq $i 0) { return $this.x } { return $this.y } })
+}
+`
+
+func TestArithmeticHooks(t *testing.T) {
+
+	ParseTestAndRunBlock(t, testVector+
+		`to_s (plus (new vector 1 2) (new vector 3 4))`, ExpectValue(t, NewStringLiteral("(4, 6)")))
+
+	ParseTestAndRunBlock(t, testVector+
+		`to_s (minus (new vector 1 2) (new vector 3 4))`, ExpectValue(t, NewStringLiteral("(-2, -2)")))
+
+	ParseTestAndRunBlock(t, testVector+
+		`to_s (multiply (new vector 1 2) 3)`, ExpectValue(t, NewStringLiteral("(3, 6)")))
+
+	ParseTestAndRunBlock(t, testVector+
+		`a: (new vector 1 2)
+		 to_s (expr "$a + $a * 2")`, ExpectValue(t, NewStringLiteral("(3, 6)")))
+
+	// the right operand is used when the left operand has no hook
+	//
+	ParseTestAndRunBlock(t, testVector+
+		`to_s (multiply 3 (new vector 1 2))`, ExpectValue(t, NewStringLiteral("(3, 6)")))
+
+	ParseTestAndRunBlock(t,
+		`money: {
+		   init: (func amount { this.amount: $amount })
+		   _plus: (func other { return (new money (plus $this.amount $other)) })
+		   _minus: (func other { return (new money (minus $this.amount $other)) })
+		   _rminus: (func other { return (new money (minus $other $this.amount)) })
+		 }
+		 a: (plus 1 (new money 2))
+		 b: (plus (new money 2) 1)
+		 c: (minus 10 (new money 3))
+		 d: (minus (new money 10) 3)
+		 [$a.amount $b.amount $c.amount $d.amount]`,
+		ExpectValue(t, NewListValue([]Value{NewIntegerLiteral(3), NewIntegerLiteral(3), NewIntegerLiteral(7), NewIntegerLiteral(7)})))
+
+	// non commutative operations do not use the normal hook of the right operand
+	//
+	ParseTestAndRunBlock(t,
+		`d: {_minus: (func other { return 0 })}
+		 minus 1 $d`, ExpectErrorValueAt(t, 2))
+
+	// dictionaries without hook still do not support arithmetic
+	//
+	ParseTestAndRunBlock(t,
+		`d: {}
+		 plus $d 1`, ExpectErrorValueAt(t, 2))
+
+	ParseTestAndRunBlock(t,
+		`d: {_plus: 3}
+		 plus $d 1`, ExpectErrorValueAt(t, 2))
+}
+
+func TestStringHook(t *testing.T) {
+
+	ParseTestAndRunBlock(t, testVector+
+		`a: (new vector 1 2)
+		 "vector \{$a}"`, ExpectValue(t, NewStringLiteral("vector (1, 2)")))
+
+}
+
+func TestLenHook(t *testing.T) {
+
+	ParseTestAndRunBlock(t, testVector+
+		`len (new vector 1 2)`, ExpectValue(t, NewIntegerLiteral(2)))
+
+	ParseTestAndRunBlock(t,
+		`d: {_len: (func { return "two" })}
+		 len $d`, ExpectErrorValueAt(t, 2))
+}
+
+func TestEqHook(t *testing.T) {
+
+	ParseTestAndRunBlock(t, testVector+
+		`eq (new vector 1 2) (new vector 1 2)`, ExpectValue(t, True))
+
+	ParseTestAndRunBlock(t, testVector+
+		`eq (new vector 1 2) (new vector 2 1)`, ExpectValue(t, False))
+
+	ParseTestAndRunBlock(t, testVector+
+		`ne (new vector 1 2) (new vector 2 1)`, ExpectValue(t, True))
+
+	ParseTestAndRunBlock(t,
+		`d: {_eq: (func other { return 1 })}
+		 eq $d 1`, ExpectErrorValueAt(t, 2))
+
+	// the right operand is used when the left operand has no hook
+	//
+	ParseTestAndRunBlock(t,
+		`p: {_eq: (func other { return (eq $other 3) })}
+		 [(eq $p 3) (eq 3 $p) (ne 3 $p) (eq 4 $p)]`, ExpectValue(t, NewListValue([]Value{True, True, False, False})))
+}
+
+func TestCompareHook(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`p: {_compare: (func other { return (compare 3 $other) })}
+		 [(lt $p 4) (gt 4 $p) (lt 2 $p) (gte 3 $p)]`, ExpectValue(t, NewListValue([]Value{True, True, True, True})))
+}
+
+func TestIterHook(t *testing.T) {
+
+	ParseTestAndRunBlock(t, testVector+
+		`total: 0
+		 for v in (new vector 3 4) { total: (plus $total $v) }
+		 total`, ExpectValue(t, NewIntegerLiteral(7)))
+
+	ParseTestAndRunBlock(t,
+		`d: {_iter: (func { return $this })}
+		 for v in $d {}`, ExpectErrorValueAt(t, 2))
+}
+
+func TestCallHook(t *testing.T) {
+
+	ParseTestAndRunBlock(t, testVector+
+		`a: (new vector 3 4)
+		 a 1`, ExpectValue(t, NewIntegerLiteral(4)))
+
+	// without hook, dictionaries return the value of a key
+	//
+	ParseTestAndRunBlock(t,
+		`d: {x: 3}
+		 d x`, ExpectValue(t, NewIntegerLiteral(3)))
+}
+
+func TestIncrAndCloseHooks(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`counter: {
+		   count: 0
+		   _incr: (func by { this.count: (plus $this.count $by); return $this })
+		 }
+		 incr counter
+		 incr counter 5
+		 counter.count`, ExpectValue(t, NewIntegerLiteral(6)))
+
+	ParseTestAndRunBlock(t,
+		`resource: {
+		   closed: $false
+		   _close: (func { this.closed: $true })
+		 }
+		 close $resource
+		 resource.closed`, ExpectValue(t, True))
+}
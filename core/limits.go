@@ -0,0 +1,162 @@
+package elmo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Limits holds the budgets of a script execution. A zero value
+// means there is no limit
+//
+type Limits struct {
+	// MaxSteps is the maximum number of calls an execution may perform
+	MaxSteps int64
+
+	// MaxDepth is the maximum number of nested function calls
+	MaxDepth int
+
+	// MaxCollectionSize is the maximum number of items in a list, dictionary or
+	// set that is constructed, changed or returned by a function
+	MaxCollectionSize int
+}
+
+// execution is shared by all run contexts that take part in the
+// same script execution, including the ones used by actors
+//
+type execution struct {
+	// settings holds the current *executionSettings. Settings are replaced
+	// as a whole, guarded by lock, so running scripts can read them safely
+	settings atomic.Value
+	lock     sync.Mutex
+
+	steps int64
+
+	// policy restricts what scripts can do, nil when unrestricted
+	policy *Policy
+
+	// scripts loaded within the execution
+	scripts *loadedScripts
+
+	// record types declared within the execution
+	records *recordTypes
+}
+
+// executionSettings are the settings of an execution that can be changed by its host
+//
+type executionSettings struct {
+	ctx    context.Context
+	done   <-chan struct{}
+	limits Limits
+}
+
+func newExecution() *execution {
+	execution := &execution{scripts: newLoadedScripts(), records: newRecordTypes()}
+	execution.settings.Store(&executionSettings{ctx: context.Background()})
+	return execution
+}
+
+func (execution *execution) current() *executionSettings {
+	return execution.settings.Load().(*executionSettings)
+}
+
+// change replaces the settings of an execution by a changed copy
+//
+func (execution *execution) change(with func(settings *executionSettings)) {
+	execution.lock.Lock()
+	defer execution.lock.Unlock()
+
+	settings := *execution.current()
+	with(&settings)
+	execution.settings.Store(&settings)
+}
+
+// executionOf returns the execution given context takes part in
+// or a new one when context is not a run context
+//
+func executionOf(parent RunContext) *execution {
+	if rc, ok := parent.(*runContext); ok && rc.execution != nil {
+		return rc.execution
+	}
+	return newExecution()
+}
+
+// NewAbortErrorValue creates a fatal error that denotes execution has been
+// stopped by its host, either by cancellation or by exceeding a budget.
+// These errors can not be caught by scripts
+//
+func NewAbortErrorValue(msg string) ErrorValue {
+	return &errorValue{baseValue: baseValue{info: typeInfoError}, msg: msg, fatal: true, abort: true}
+}
+
+func (execution *execution) cancelled() ErrorValue {
+	settings := execution.current()
+	select {
+	case <-settings.done:
+		return NewAbortErrorValue(fmt.Sprintf("execution cancelled: %v", settings.ctx.Err()))
+	default:
+		return nil
+	}
+}
+
+func (execution *execution) step() ErrorValue {
+	// steps are only counted when limited so unlimited executions
+	// do not synchronize all their actors on every call
+	//
+	limits := execution.current().limits
+	if limits.MaxSteps > 0 && atomic.AddInt64(&execution.steps, 1) > limits.MaxSteps {
+		return NewAbortErrorValue(fmt.Sprintf("execution aborted: exceeded maximum of %d steps", limits.MaxSteps))
+	}
+	return execution.cancelled()
+}
+
+func (execution *execution) checkDepth(depth int) ErrorValue {
+	limits := execution.current().limits
+	if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+		return NewAbortErrorValue(fmt.Sprintf("execution aborted: exceeded maximum recursion depth of %d", limits.MaxDepth))
+	}
+	return nil
+}
+
+// CheckCollectionSize returns an abort error when a list or dictionary
+// of given size exceeds the budget of given context
+//
+func CheckCollectionSize(context RunContext, size int) ErrorValue {
+	limits := context.Limits()
+	if limits.MaxCollectionSize > 0 && size > limits.MaxCollectionSize {
+		return NewAbortErrorValue(fmt.Sprintf("execution aborted: exceeded maximum collection size of %d", limits.MaxCollectionSize))
+	}
+	return nil
+}
+
+// limitResult replaces a list, dictionary or set returned by a function by an
+// abort error when it exceeds the budget, so collections derived from other
+// values stay within budget too
+//
+func limitResult(context RunContext, value Value) Value {
+	if value == nil || context.Limits().MaxCollectionSize <= 0 {
+		return value
+	}
+
+	size := 0
+	switch collection := value.(type) {
+	case ListValue:
+		size = len(collection.List())
+	case DictionaryValue:
+		size = len(collection.Keys())
+	case SetValue:
+		size = len(collection.List())
+	}
+	if err := CheckCollectionSize(context, size); err != nil {
+		return err
+	}
+	return value
+}
+
+// Cancelled returns an abort error when the Go context of given
+// run context is cancelled or has passed its deadline
+//
+func Cancelled(context RunContext) ErrorValue {
+	return executionOf(context).cancelled()
+}
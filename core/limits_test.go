@@ -0,0 +1,123 @@
+package elmo
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func contextWithLimits(limits Limits) RunContext {
+	global := NewGlobalContext()
+	global.SetLimits(limits)
+	return global
+}
+
+func TestMaxSteps(t *testing.T) {
+
+	ParseTestAndRunBlockWithinContext(t, contextWithLimits(Limits{MaxSteps: 1000}),
+		`while $true {}`, ExpectAbortErrorValue(t))
+
+	ParseTestAndRunBlockWithinContext(t, contextWithLimits(Limits{MaxSteps: 1000}),
+		`pepper: 0
+		 while (lt $pepper 10) { incr pepper }
+		 pepper`, ExpectValue(t, NewIntegerLiteral(10)))
+
+	// aborted execution can not be recovered from
+	//
+	ParseTestAndRunBlockWithinContext(t, contextWithLimits(Limits{MaxSteps: 1000}),
+		`try { while $true {} } catch! { "chipotle" }`, ExpectAbortErrorValue(t))
+
+	withVM(true, func() {
+		ParseTestAndRunBlockWithinContext(t, contextWithLimits(Limits{MaxSteps: 1000}),
+			`while $true {}`, ExpectAbortErrorValue(t))
+	})
+}
+
+func TestMaxDepth(t *testing.T) {
+
+	ParseTestAndRunBlockWithinContext(t, contextWithLimits(Limits{MaxDepth: 50}),
+		`f: (func { f })
+		 f`, ExpectAbortErrorValue(t))
+
+	ParseTestAndRunBlockWithinContext(t, contextWithLimits(Limits{MaxDepth: 50}),
+		`f: (func n { if (gt $n 0) { return (f (minus $n 1)) } { return "chipotle" } })
+		 f 40`, ExpectValue(t, NewStringLiteral("chipotle")))
+
+	ParseTestAndRunBlockWithinContext(t, contextWithLimits(Limits{MaxDepth: 50}),
+		`f: (func n { if (gt $n 0) { return (f (minus $n 1)) } { return "chipotle" } })
+		 f 60`, ExpectAbortErrorValue(t))
+}
+
+func TestMaxCollectionSize(t *testing.T) {
+
+	ParseTestAndRunBlockWithinContext(t, contextWithLimits(Limits{MaxCollectionSize: 3}),
+		`[1 2 3]`, ExpectValue(t, NewListValue([]Value{NewIntegerLiteral(1), NewIntegerLiteral(2), NewIntegerLiteral(3)})))
+
+	ParseTestAndRunBlockWithinContext(t, contextWithLimits(Limits{MaxCollectionSize: 3}),
+		`[1 2 3 4]`, ExpectAbortErrorValue(t))
+
+	// collections that are not constructed by a list are checked as well
+	//
+	for _, useVM := range []bool{false, true} {
+		withVM(useVM, func() {
+			ParseTestAndRunBlockWithinContext(t, contextWithLimits(Limits{MaxCollectionSize: 3}),
+				`d: {a: 1; b: 2; c: 3; d: 4}`, ExpectAbortErrorValue(t))
+
+			ParseTestAndRunBlockWithinContext(t, contextWithLimits(Limits{MaxCollectionSize: 3}),
+				`f: (func { d: {a: 1; b: 2; c: 3; d: 4}; return "chipotle" })
+				 f`, ExpectAbortErrorValue(t))
+
+			ParseTestAndRunBlockWithinContext(t, contextWithLimits(Limits{MaxCollectionSize: 3}),
+				`d: {a: 1; b: 2; c: 3}
+				 d.c`, ExpectValue(t, NewIntegerLiteral(3)))
+		})
+	}
+}
+
+func TestSetContextAndLimitsConcurrently(t *testing.T) {
+
+	global := NewGlobalContext()
+	done := make(chan bool)
+
+	go func() {
+		for i := 0; i < 100; i++ {
+			global.SetContext(context.Background())
+			global.SetLimits(Limits{MaxSteps: 100000})
+		}
+		done <- true
+	}()
+
+	for i := 0; i < 100; i++ {
+		ParseAndRun(global.CreateSubContext(), `plus 1 2`)
+	}
+	<-done
+}
+
+func TestCancellation(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	global := NewGlobalContext()
+	global.SetContext(ctx)
+
+	ParseTestAndRunBlockWithinContext(t, global,
+		`"chipotle"`, ExpectAbortErrorValue(t))
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	global = NewGlobalContext()
+	global.SetContext(ctx)
+
+	started := time.Now()
+	ParseTestAndRunBlockWithinContext(t, global,
+		`sleep 10000`, ExpectAbortErrorValue(t))
+
+	if time.Since(started) > 5*time.Second {
+		t.Error("expected sleep to be interrupted")
+	}
+
+	ParseTestAndRunBlockWithinContext(t, global,
+		`while $true {}`, ExpectAbortErrorValue(t))
+}
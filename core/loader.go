@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"plugin"
 	"strings"
+	"sync"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/pkg/errors"
@@ -19,6 +20,66 @@ type loader struct {
 	folders []string
 }
 
+// loadedScripts keeps track of the scripts loaded within an execution.
+// Loaded scripts are cached by their absolute path
+//
+type loadedScripts struct {
+	lock   sync.Mutex
+	loaded map[string]DictionaryValue
+
+	// scripts that are being loaded, their channel is closed when done
+	loading map[string]chan struct{}
+}
+
+func newLoadedScripts() *loadedScripts {
+	return &loadedScripts{loaded: map[string]DictionaryValue{}, loading: map[string]chan struct{}{}}
+}
+
+// begin marks a script as being loaded by a loader that got there through
+// given chain of loads. Returns the dictionary of the script when it is
+// already loaded, the chain of scripts that led to loading it again when
+// the loader itself is loading it or, when another loader is loading it,
+// a channel to wait for
+//
+func (scripts *loadedScripts) begin(absolute string, chain []string) (DictionaryValue, []string, <-chan struct{}) {
+	scripts.lock.Lock()
+	defer scripts.lock.Unlock()
+
+	if loaded, found := scripts.loaded[absolute]; found {
+		return loaded, nil, nil
+	}
+
+	done, beingLoaded := scripts.loading[absolute]
+	if !beingLoaded {
+		scripts.loading[absolute] = make(chan struct{})
+		return nil, nil, nil
+	}
+
+	for i, loading := range chain {
+		if loading == absolute {
+			cycle := make([]string, 0, len(chain)-i+1)
+			cycle = append(cycle, chain[i:]...)
+			return nil, append(cycle, absolute), nil
+		}
+	}
+
+	return nil, nil, done
+}
+
+// end marks a script as loaded, only successfully loaded scripts are cached
+//
+func (scripts *loadedScripts) end(absolute string, loaded DictionaryValue) {
+	scripts.lock.Lock()
+	defer scripts.lock.Unlock()
+
+	if loaded != nil {
+		scripts.loaded[absolute] = loaded
+	}
+
+	close(scripts.loading[absolute])
+	delete(scripts.loading, absolute)
+}
+
 // Loader is responsible for loading external elmo sources
 //
 type Loader interface {
@@ -42,6 +103,11 @@ func (loader *loader) buildFromGoCode(goModPath string) error {
 
 }
 
+func (loader *loader) pluginsPermitted() bool {
+	policy := loader.context.Policy()
+	return policy == nil || policy.Plugins
+}
+
 func (loader *loader) loadFromPlugin(folderName string, name string) Value {
 
 	source := strings.Join([]string{folderName, "/", name, ".so"}, "")
@@ -51,6 +117,9 @@ func (loader *loader) loadFromPlugin(folderName string, name string) Value {
 		if !fileExists(goModPath) {
 			return nil
 		}
+		if !loader.pluginsPermitted() {
+			return NewNotPermittedErrorValue(fmt.Sprintf("building plugin %s", name))
+		}
 		// found go source code, try to compile it
 		//
 		if err := loader.buildFromGoCode(goModPath); err != nil {
@@ -62,6 +131,10 @@ func (loader *loader) loadFromPlugin(folderName string, name string) Value {
 		}
 	}
 
+	if !loader.pluginsPermitted() {
+		return NewNotPermittedErrorValue(fmt.Sprintf("loading plugin %s", name))
+	}
+
 	modulePlugin, err := plugin.Open(source)
 	if err != nil {
 		return NewErrorValue(err.Error())
@@ -93,7 +166,7 @@ func (loader *loader) watchFile(watcher *fsnotify.Watcher, source string, loaded
 
 			if event.Op&fsnotify.Write == fsnotify.Write {
 
-				reconstructed, _, _ := loader.constructDictionary(source)
+				reconstructed, _, _ := loader.constructDictionary(source, []string{source})
 
 				if reconstructed != nil {
 					loaded.Replace(reconstructed)
@@ -127,14 +200,18 @@ func (loader *loader) addFileWatcher(source string, loaded DictionaryValue) {
 	}
 }
 
-func (loader *loader) constructDictionary(source string) (DictionaryValue, ErrorValue, error) {
+// constructDictionary runs a script and collects its variables. Given chain
+// contains the scripts being loaded that led to loading this script
+//
+func (loader *loader) constructDictionary(source string, chain []string) (DictionaryValue, ErrorValue, error) {
 
 	b, err := ioutil.ReadFile(source)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	subContext := loader.context.CreateSubContext()
+	subContext := NewRunContext(loader.context).(*runContext)
+	subContext.loads = chain
 
 	result := ParseAndRunWithFile(subContext, string(b), source)
 
@@ -150,25 +227,84 @@ func (loader *loader) constructDictionary(source string) (DictionaryValue, Error
 func (loader *loader) loadFromDir(folderName string, name string) Value {
 	source := strings.Join([]string{folderName, "/", name, ".mo"}, "")
 
-	constructed, loadError, _ := loader.constructDictionary(source)
-	if constructed != nil {
-		if GlobalSettings().HotReload {
-			loader.addFileWatcher(source, constructed)
+	if !fileExists(source) {
+		// could be a golang plugin
+		//
+		return loader.loadFromPlugin(folderName, name)
+	}
+
+	return loader.loadScript(source)
+}
+
+// loadScript runs a script once per execution, loading it again results in
+// the dictionary constructed the first time. Scripts that, directly or
+// indirectly, load themselves result in an error showing the chain of loads.
+// A script that is being loaded by another actor is waited for
+//
+func (loader *loader) loadScript(source string) Value {
+
+	absolute, err := filepath.Abs(source)
+	if err != nil {
+		return NewErrorValue(err.Error())
+	}
+
+	execution := executionOf(loader.context)
+	chain := loader.context.loadChain()
+
+	for {
+		loaded, cycle, wait := execution.scripts.begin(absolute, chain)
+		if loaded != nil {
+			return loaded
+		}
+		if cycle != nil {
+			return NewErrorValue(fmt.Sprintf("load cycle: %s", strings.Join(cycle, " -> "))).Panic()
+		}
+		if wait == nil {
+			break
+		}
+
+		// try again when the other load is done, it's not cached when it failed
+		//
+		select {
+		case <-wait:
+		case <-execution.current().done:
+			return execution.cancelled()
 		}
-		return constructed
+	}
+
+	constructed, loadError, readError := loader.constructDictionary(source, append(chain[:len(chain):len(chain)], absolute))
+
+	execution.scripts.end(absolute, constructed)
+
+	if readError != nil {
+		return NewErrorValue(readError.Error())
 	}
 	if loadError != nil {
 		return loadError
 	}
 
-	// could be a golang plugin
-	//
-	return loader.loadFromPlugin(folderName, name)
+	if GlobalSettings().HotReload {
+		loader.addFileWatcher(source, constructed)
+	}
+	return constructed
+}
 
+// searchPath returns the folders to load scripts from when they can not be
+// found relative from the current script. Folders given to the loader come
+// first, followed by the folders of the global search path
+//
+func (loader *loader) searchPath() []string {
+	folders := make([]string, 0, len(loader.folders)+len(GlobalSettings().Path))
+	folders = append(folders, loader.folders...)
+	return append(folders, GlobalSettings().Path...)
 }
 
 func (loader *loader) Load(name string) Value {
 
+	if policy := loader.context.Policy(); policy != nil {
+		return loader.loadWithinSandbox(policy, name)
+	}
+
 	// try relative from current script
 	//
 	scriptName := loader.context.ScriptName()
@@ -185,7 +321,7 @@ func (loader *loader) Load(name string) Value {
 
 	// try known folders
 	//
-	for _, folderName := range loader.folders {
+	for _, folderName := range loader.searchPath() {
 		result := loader.loadFromDir(folderName, name)
 		if result != nil {
 			return result
@@ -196,6 +332,32 @@ func (loader *loader) Load(name string) Value {
 	return err.Panic()
 }
 
+// loadWithinSandbox loads a script relative from the current script when
+// it is located within the file root of the sandbox, otherwise relative
+// from the file root itself
+//
+func (loader *loader) loadWithinSandbox(policy *Policy, name string) Value {
+
+	folderName := string(filepath.Separator)
+	if scriptName := loader.context.ScriptName(); scriptName != nil {
+		if root, err := filepath.Abs(policy.FileRoot); err == nil && policy.within(root, scriptName.String()) {
+			folderName = policy.virtual(filepath.Dir(scriptName.String()))
+		}
+	}
+
+	jailed, err := policy.jail(filepath.Join(folderName, name))
+	if err != nil {
+		return err.Panic()
+	}
+
+	result := loader.loadFromDir(filepath.Dir(jailed), filepath.Base(jailed))
+	if result != nil {
+		return result
+	}
+
+	return NewErrorValue(fmt.Sprintf("could not find %s", name)).Panic()
+}
+
 // NewLoader constructs a new source code loader
 //
 func NewLoader(context RunContext, folders []string) Loader {
@@ -1,6 +1,11 @@
 package elmo
 
-import "testing"
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
 
 func TestLoaderLoadsFromWorkingDir(t *testing.T) {
 
@@ -61,3 +66,153 @@ func TestLoaderDoesNotLoadFileWhichCanNotLoadOtherFile(t *testing.T) {
 	}
 
 }
+
+func TestLoaderTracesErrorsThroughLoadedScripts(t *testing.T) {
+
+	context := NewGlobalContext()
+	loader := NewLoader(context, []string{"./loader_testdata"})
+
+	context.Set("traced", loader.Load("trace"))
+
+	value := ParseAndRunWithFile(context, "\ntraced.failing", "test")
+
+	if value.Type() != TypeError {
+		t.Fatalf("expected an error, found %v", value)
+	}
+
+	expected := []TraceFrame{
+		{Script: "./loader_testdata/trace.mo", Line: 3, Column: 3, Function: "error"},
+		{Script: "./loader_testdata/trace.mo", Line: 7, Column: 3, Function: "fail"},
+		{Script: "test", Line: 2, Column: 1, Function: "traced.failing"}}
+
+	trace := value.(ErrorValue).Trace()
+	if len(trace) != len(expected) {
+		t.Fatalf("expected %d frames, found %v", len(expected), trace)
+	}
+
+	for i, frame := range expected {
+		if trace[i] != frame {
+			t.Errorf("expected frame %v, found %v", frame, trace[i])
+		}
+	}
+}
+
+func TestLoaderTracesErrorsOfLoadedScripts(t *testing.T) {
+
+	value := ParseAndRunWithFile(NewGlobalContext(), "\nload \"loader_testdata/broken\"", "test")
+
+	if value.Type() != TypeError {
+		t.Fatalf("expected an error, found %v", value)
+	}
+
+	expected := []string{"error", "fail", "load"}
+
+	trace := value.(ErrorValue).Trace()
+	if len(trace) != len(expected) {
+		t.Fatalf("expected %d frames, found %v", len(expected), trace)
+	}
+
+	for i, function := range expected {
+		if trace[i].Function != function {
+			t.Errorf("expected frame of %s, found %v", function, trace[i])
+		}
+	}
+}
+
+func TestLoaderLoadsScriptsOnce(t *testing.T) {
+
+	context := NewGlobalContext()
+	loader := NewLoader(context, []string{"./loader_testdata"})
+
+	ParseAndRun(context, `loads: {count: 0}`)
+
+	first := loader.Load("counted")
+	second := NewLoader(context, []string{"./loader_testdata"}).Load("counted")
+
+	if first.Type() != TypeDictionary || first != second {
+		t.Errorf("expected the same dictionary twice, found %v and %v", first, second)
+	}
+
+	ParseTestAndRunBlockWithinContext(t, context, `loads.count`, ExpectValue(t, NewIntegerLiteral(1)))
+
+	// other executions load their own copy
+	//
+	other := NewGlobalContext()
+	ParseAndRun(other, `loads: {count: 0}`)
+	if NewLoader(other, []string{"./loader_testdata"}).Load("counted") == first {
+		t.Error("did not expect to share loaded scripts between executions")
+	}
+}
+
+func TestLoaderWaitsForScriptsLoadedConcurrently(t *testing.T) {
+
+	context := NewGlobalContext()
+	ParseAndRun(context, `slowLoads: {count: 0}`)
+
+	// like actors, loaders run within their own sub context of the same execution
+	//
+	results := make(chan Value, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			results <- NewLoader(context.CreateSubContext(), []string{"./loader_testdata"}).Load("slow")
+		}()
+	}
+
+	first, second := <-results, <-results
+	if first.Type() != TypeDictionary || first != second {
+		t.Errorf("expected the same dictionary twice, found %v and %v", first, second)
+	}
+
+	ParseTestAndRunBlockWithinContext(t, context, `slowLoads.count`, ExpectValue(t, NewIntegerLiteral(1)))
+}
+
+func TestLoaderDetectsLoadCycles(t *testing.T) {
+
+	loader := NewLoader(NewGlobalContext(), []string{"./loader_testdata"})
+
+	value := loader.Load("cycle_a")
+
+	if value.Type() != TypeError {
+		t.Fatalf("expected a load cycle error, found %v", value)
+	}
+
+	a, _ := filepath.Abs("./loader_testdata/cycle_a.mo")
+	b, _ := filepath.Abs("./loader_testdata/cycle_b.mo")
+	if !strings.Contains(value.String(), fmt.Sprintf("load cycle: %s -> %s -> %s", a, b, a)) {
+		t.Errorf("expected a different error, found %v", value)
+	}
+
+	// a failed load is not cached
+	//
+	if value := loader.Load("cycle_b"); value.Type() != TypeError {
+		t.Errorf("expected a load cycle error, found %v", value)
+	}
+}
+
+func TestLoaderLoadsFromSubdirectories(t *testing.T) {
+
+	context := NewGlobalContext()
+	context.Set("sub", NewLoader(context, []string{"./loader_testdata"}).Load("pkg/sub"))
+
+	ParseTestAndRunBlockWithinContext(t, context, `sub.hotness`, ExpectValue(t, NewIntegerLiteral(100000)))
+}
+
+func TestLoaderUsesSearchPath(t *testing.T) {
+
+	path := GlobalSettings().Path
+	defer func() {
+		GlobalSettings().Path = path
+	}()
+
+	loader := NewLoader(NewGlobalContext(), []string{"./loader_testdata"})
+
+	if value := loader.Load("searched"); value.Type() != TypeError {
+		t.Errorf("did not expect to find searched, found %v", value)
+	}
+
+	GlobalSettings().Path = []string{"./loader_testdata/search"}
+
+	if value := loader.Load("searched"); value.Type() != TypeDictionary {
+		t.Errorf("expected to find searched through the search path, found %v", value)
+	}
+}
@@ -0,0 +1,73 @@
+package elmo
+
+import (
+	"container/list"
+	"sync"
+)
+
+// LRUCache is a cache with a bounded number of entries. When the cache is
+// full, the entry that has not been used for the longest time is dropped
+//
+type LRUCache struct {
+	sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type lruCacheEntry struct {
+	key   string
+	value interface{}
+}
+
+// NewLRUCache creates a cache that holds at most capacity entries
+//
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{capacity: capacity, order: list.New(), entries: map[string]*list.Element{}}
+}
+
+// Get returns the value cached under key and marks it as recently used
+//
+func (cache *LRUCache) Get(key string) (interface{}, bool) {
+	cache.Lock()
+	defer cache.Unlock()
+
+	element, found := cache.entries[key]
+	if !found {
+		return nil, false
+	}
+	cache.order.MoveToFront(element)
+	return element.Value.(*lruCacheEntry).value, true
+}
+
+// Put caches a value under key and returns the value that is cached, when
+// key is already cached its existing value is kept and returned
+//
+func (cache *LRUCache) Put(key string, value interface{}) interface{} {
+	cache.Lock()
+	defer cache.Unlock()
+
+	if element, found := cache.entries[key]; found {
+		cache.order.MoveToFront(element)
+		return element.Value.(*lruCacheEntry).value
+	}
+
+	cache.entries[key] = cache.order.PushFront(&lruCacheEntry{key: key, value: value})
+
+	if cache.order.Len() > cache.capacity {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*lruCacheEntry).key)
+	}
+
+	return value
+}
+
+// Len returns the number of cached entries
+//
+func (cache *LRUCache) Len() int {
+	cache.Lock()
+	defer cache.Unlock()
+
+	return cache.order.Len()
+}
@@ -0,0 +1,26 @@
+package elmo
+
+import "testing"
+
+func TestLRUCacheDropsLeastRecentlyUsed(t *testing.T) {
+
+	cache := NewLRUCache(2)
+	cache.Put("1", 1)
+	cache.Put("2", 2)
+	cache.Get("1")
+	cache.Put("3", 3)
+
+	if _, found := cache.Get("2"); found {
+		t.Error("expected least recently used entry to be dropped")
+	}
+	if _, found := cache.Get("1"); !found {
+		t.Error("expected recently used entry to be cached")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("expected 2 cached entries, found %d", cache.Len())
+	}
+
+	if kept := cache.Put("3", 4); kept != 3 {
+		t.Errorf("expected cached value to be kept, found %v", kept)
+	}
+}
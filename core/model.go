@@ -46,19 +46,55 @@ type IncrementableValue interface {
 type DictionaryValue interface {
 	Value
 	Keys() []string
+	KeyValues() []Value
 	Resolve(string) (Value, bool)
+	ResolveKey(Value) (Value, bool)
 	Merge([]DictionaryValue) Value
 	Replace(DictionaryValue)
 	Set(symbol Value, value Value) (Value, ErrorValue)
 	Remove(symbol Value) (Value, ErrorValue)
 }
 
+// SetValue represents a collection of unique hashable values
+//
+type SetValue interface {
+	Value
+	Contains(Value) bool
+	Add(Value) (Value, ErrorValue)
+	Remove(Value) (Value, ErrorValue)
+	Union(SetValue) SetValue
+	Intersection(SetValue) SetValue
+	Difference(SetValue) SetValue
+	List() []Value
+
+	hashKeys() []string
+	snapshot() ([]string, map[string]Value)
+}
+
 // Listable type can convert a value to a list
 //
 type Listable interface {
 	List() []Value
 }
 
+// Iterator produces the values of an iterable value one at a time
+//
+// Next returns false when there are no more values. An error value ends
+// the iteration. Close releases the resources of an iterator and must be
+// called when an iterator is not used anymore
+//
+type Iterator interface {
+	Next() (Value, bool)
+	Close()
+}
+
+// IterableValue represents a value of which the values can be iterated
+// without materializing them all at once
+//
+type IterableValue interface {
+	Iterate() Iterator
+}
+
 // ListValue represents a value that can be used as a list of values
 //
 type ListValue interface {
@@ -93,6 +129,10 @@ type HelpValue interface {
 //
 type MutableValue interface {
 	Mutate(value interface{}) (Value, ErrorValue)
+
+	// Update mutates a value based on its current content without
+	// other goroutines changing the value in between
+	Update(change func(current interface{}) (interface{}, ErrorValue)) (Value, ErrorValue)
 }
 
 // FreezableValue represents a value that can be frozen
@@ -121,6 +161,7 @@ type SerializableValue interface {
 //
 type BinaryValue interface {
 	ToRegular() Value
+	ToRegularWithinContext(context RunContext) Value
 	AsBytes() []byte
 }
 
@@ -135,8 +176,21 @@ type ErrorValue interface {
 	IsTraced() bool
 	Panic() ErrorValue
 	IsFatal() bool
+	IsAborted() bool
 	Ignore() ErrorValue
 	CanBeIgnored() bool
+	AddTrace(frame TraceFrame) ErrorValue
+	Trace() []TraceFrame
+}
+
+// TraceFrame describes a location an error passed while propagating
+// through the call stack
+//
+type TraceFrame struct {
+	Script   string
+	Line     int
+	Column   int
+	Function string
 }
 
 // RunnableValue represents a value that can evaluated to another value
@@ -1,6 +1,10 @@
 package elmo
 
-import "github.com/google/uuid"
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
 
 type argument struct {
 	astNode
@@ -65,3 +69,130 @@ func NewArgumentWithDots(meta ScriptMetaData, nodeBegin *node32, nodeEnd *node32
 func NewDynamicArgument(value Value) Argument {
 	return &argument{value: value}
 }
+
+// KeywordArgument is a function call parameter that is passed by name
+// (like 'timeout=5')
+//
+type KeywordArgument interface {
+	Argument
+
+	Keyword() string
+}
+
+type keywordArgument struct {
+	argument
+	keyword string
+}
+
+func (keywordArgument *keywordArgument) Keyword() string {
+	return keywordArgument.keyword
+}
+
+func (keywordArgument *keywordArgument) String() string {
+	return fmt.Sprintf("%s=%v", keywordArgument.keyword, keywordArgument.value)
+}
+
+func (keywordArgument *keywordArgument) Enrich(dict DictionaryValue) {
+	keywordArgument.argument.Enrich(dict)
+	dict.Set(NewStringLiteral("keyword"), NewStringLiteral(keywordArgument.keyword))
+}
+
+// NewKeywordArgument constructs a new function argument that is passed by name
+//
+func NewKeywordArgument(meta ScriptMetaData, node *node32, keyword string, value Value) KeywordArgument {
+	return &keywordArgument{argument: argument{astNode: astNode{meta: meta, node: node}, value: value}, keyword: keyword}
+}
+
+// NewDynamicKeywordArgument constructs a new function argument that is passed
+// by name without script info
+//
+func NewDynamicKeywordArgument(keyword string, value Value) KeywordArgument {
+	return &keywordArgument{argument: argument{value: value}, keyword: keyword}
+}
+
+// SplitArguments separates the arguments that are passed by position from
+// the ones passed by name
+//
+func SplitArguments(arguments []Argument) ([]Argument, map[string]Argument) {
+	var keywords map[string]Argument
+
+	positional := arguments
+	for i, argument := range arguments {
+		keywordArgument, isKeyword := argument.(KeywordArgument)
+		if !isKeyword {
+			if keywords != nil {
+				positional = append(positional, argument)
+			}
+			continue
+		}
+		if keywords == nil {
+			keywords = map[string]Argument{}
+			positional = append(make([]Argument, 0, len(arguments)), arguments[:i]...)
+		}
+		keywords[keywordArgument.Keyword()] = keywordArgument
+	}
+
+	return positional, keywords
+}
+
+type spreadArgument struct {
+	argument
+}
+
+func (spreadArgument *spreadArgument) String() string {
+	return fmt.Sprintf("...%v", spreadArgument.value)
+}
+
+// NewSpreadArgument constructs a new function argument that expands a list
+// into positional arguments or a dictionary into keyword arguments
+//
+func NewSpreadArgument(meta ScriptMetaData, node *node32, value Value) Argument {
+	return &spreadArgument{argument: argument{astNode: astNode{meta: meta, node: node}, value: value}}
+}
+
+// spreadArguments expands all spread arguments. Arguments are only copied
+// when they actually contain a spread argument
+//
+func spreadArguments(context RunContext, arguments []Argument) ([]Argument, ErrorValue) {
+
+	var expanded []Argument
+	for i, argument := range arguments {
+		spread, isSpread := argument.(*spreadArgument)
+		if !isSpread {
+			if expanded != nil {
+				expanded = append(expanded, argument)
+			}
+			continue
+		}
+		if expanded == nil {
+			expanded = append(make([]Argument, 0, len(arguments)), arguments[:i]...)
+		}
+
+		value := EvalArgument(context, spread)
+		if value.Type() == TypeBlock {
+			value = NewDictionaryWithBlock(context, value.(Block))
+		}
+
+		switch value.Type() {
+		case TypeList:
+			for _, element := range value.Internal().([]Value) {
+				expanded = append(expanded, NewDynamicArgument(element))
+			}
+		case TypeDictionary:
+			dict := value.(DictionaryValue)
+			for _, key := range dict.KeyValues() {
+				element, _ := dict.ResolveKey(key)
+				expanded = append(expanded, NewDynamicKeywordArgument(key.String(), element))
+			}
+		case TypeError:
+			return nil, value.(ErrorValue)
+		default:
+			return nil, NewErrorValue(fmt.Sprintf("can not spread value of type %v, expected a list or a dictionary", value.Info().Name()))
+		}
+	}
+
+	if expanded == nil {
+		return arguments, nil
+	}
+	return expanded, nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/gob"
 	"fmt"
+	"math/big"
 )
 
 // BinaryData is a struct used to serialize values to binary data
@@ -46,6 +47,10 @@ func (binaryValue *binaryValue) AsBytes() []byte {
 }
 
 func (binaryValue *binaryValue) ToRegular() Value {
+	return binaryValue.ToRegularWithinContext(nil)
+}
+
+func (binaryValue *binaryValue) ToRegularWithinContext(context RunContext) Value {
 
 	buf := bytes.NewBuffer(binaryValue.data)
 	decoder := gob.NewDecoder(buf)
@@ -76,6 +81,22 @@ func (binaryValue *binaryValue) ToRegular() Value {
 			return NewErrorValue(err.Error())
 		}
 		return NewIntegerLiteral(actualData)
+	case typeInfoBigInteger.ID():
+		actualData := new(big.Int)
+		if err := decoder.Decode(actualData); err != nil {
+			return NewErrorValue(err.Error())
+		}
+		return NewIntegerFromBig(actualData)
+	case typeInfoDecimal.ID():
+		actualData := ""
+		if err := decoder.Decode(&actualData); err != nil {
+			return NewErrorValue(err.Error())
+		}
+		decimal, err := NewDecimalFromString(actualData)
+		if err != nil {
+			return err
+		}
+		return decimal
 	case typeInfoFloat.ID():
 		actualData := float64(0)
 		if err := decoder.Decode(&actualData); err != nil {
@@ -93,7 +114,25 @@ func (binaryValue *binaryValue) ToRegular() Value {
 		if err := decoder.Decode(&actualData); err != nil {
 			return NewErrorValue(err.Error())
 		}
-		return actualData.ToValue()
+		return actualData.ToValueWithinContext(context)
+	case typeInfoSet.ID():
+		actualData := SerializationResult{}
+		if err := decoder.Decode(&actualData); err != nil {
+			return NewErrorValue(err.Error())
+		}
+		regular := actualData.ToValueWithinContext(context)
+		if regular.Type() == TypeError {
+			return regular
+		}
+		values := regular.(ListValue).List()
+		for _, value := range values {
+			freezeKey(value)
+		}
+		set, err := NewSetValue(values)
+		if err != nil {
+			return err
+		}
+		return set
 	default:
 		return NewErrorValue("binaryValue.AsRegular: operation unsupported yet")
 	}
@@ -10,6 +10,7 @@ type block struct {
 	baseValue
 	capturedContext RunContext
 	calls           []Call
+	code            *compiledCode
 }
 
 // Block is a list of function calls
@@ -38,6 +39,10 @@ func (block *block) Run(context RunContext, arguments []Argument) Value {
 		joined = joined.Join(block.capturedContext)
 	}
 
+	if GlobalSettings().VM {
+		return block.code.compile(block).exec(joined, context, nil)
+	}
+
 	for _, call := range block.calls {
 		result = call.Run(joined, []Argument{})
 		if joined.isStopped() {
@@ -72,11 +77,11 @@ func (block *block) Enrich(dict DictionaryValue) {
 }
 
 func (b *block) CopyWithinContext(context RunContext) Block {
-	return &block{astNode: b.astNode, baseValue: b.baseValue, calls: b.calls, capturedContext: context}
+	return &block{astNode: b.astNode, baseValue: baseValue{info: b.info, id: b.id}, calls: b.calls, code: b.code, capturedContext: context}
 }
 
 // NewBlock contsruct a new block of function calls
 //
 func NewBlock(meta ScriptMetaData, node *node32, calls []Call) Block {
-	return &block{astNode: astNode{meta: meta, node: node}, baseValue: baseValue{info: typeInfoBlock}, calls: calls}
+	return &block{astNode: astNode{meta: meta, node: node}, baseValue: baseValue{info: typeInfoBlock}, calls: calls, code: &compiledCode{}}
 }
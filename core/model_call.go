@@ -3,6 +3,8 @@ package elmo
 import (
 	"errors"
 	"fmt"
+	"sync"
+	"unicode"
 )
 
 type call struct {
@@ -12,6 +14,9 @@ type call struct {
 	function      GoFunction
 	arguments     []Argument
 	pipe          Runnable
+
+	compileOnce sync.Once
+	program     *program
 }
 
 // Call is a function call
@@ -40,26 +45,64 @@ func (call *call) WillPipe() bool {
 	return call.pipe != nil
 }
 
-func (call *call) addInfoWhenError(value Value) Value {
+// position determines line and column of a call while skipping
+// leading white space and new lines
+//
+func (call *call) position() (int, int) {
+	content := call.meta.Content()
+	at := int(call.BeginsAt())
+	for at < len(content)-1 && unicode.IsSpace(content[at]) {
+		at++
+	}
+	return call.meta.PositionOf(at)
+}
+
+// addInfoWhenError adds the location of a call to an error. An error that's
+// not traced yet gets the call as origin, other errors only get a frame when
+// the call crosses a boundary (see isTraceBoundary). Errors are copied before
+// a frame is added so stored errors keep their trace
+//
+func (call *call) addInfoWhenError(value Value, boundary bool) Value {
 	if value == nil {
 		return nil
 	}
 	if value.Type() == TypeError {
-		if value.(ErrorValue).IsTraced() {
-			// TODO: add trace??
-			//
+		err := value.(ErrorValue)
+
+		traced := err.IsTraced()
+		if traced && !boundary {
 			return value
 		}
 
 		meta := call.meta
+		lineno, column := call.position()
 
-		lineno, _ := meta.PositionOf(int(call.BeginsAt()))
-		value.(ErrorValue).SetAt(call.meta, lineno)
-
+		err = err.AddTrace(TraceFrame{Script: meta.Name(), Line: lineno, Column: column, Function: call.Name()})
+		if !traced {
+			err.SetAt(meta, lineno)
+		}
+		return err
 	}
 	return value
 }
 
+// isTraceBoundary tells if errors returned by a call to given value get a frame
+// in their trace. Only calls to user defined functions, to functions of modules
+// or dictionaries and calls that load scripts are recorded
+//
+func isTraceBoundary(inDict DictionaryValue, value Value) bool {
+	if inDict != nil {
+		return true
+	}
+	switch function := value.(type) {
+	case *inspectableGoFunction:
+		return true
+	case *goFunction:
+		return function.boundary
+	}
+	return false
+}
+
 func (call *call) pipeResult(context RunContext, value Value) Value {
 	if !call.WillPipe() {
 		return value
@@ -93,10 +136,29 @@ func createArgumentsForMissingFunc(context RunContext, call *call, arguments []A
 		NewArgument(call.meta, call.astNode.node, NewListValue(values))}
 }
 
+func (call *call) compiled() *program {
+	call.compileOnce.Do(func() {
+		call.program = compileSingleCall(call)
+	})
+	return call.program
+}
+
 func (call *call) Run(context RunContext, additionalArguments []Argument) Value {
 
+	if GlobalSettings().VM {
+		return call.compiled().exec(context, context, additionalArguments)
+	}
+
+	if err := context.step(); err != nil {
+		return call.addInfoWhenError(err, false)
+	}
+
 	if call.function != nil {
-		return call.pipeResult(context, call.addInfoWhenError(call.function(context, call.Arguments())))
+		arguments, err := spreadArguments(context, call.Arguments())
+		if err != nil {
+			return call.pipeResult(context, call.addInfoWhenError(err, false))
+		}
+		return call.pipeResult(context, call.addInfoWhenError(limitResult(context, call.function(context, arguments)), false))
 	}
 
 	var inDict DictionaryValue
@@ -132,8 +194,14 @@ func (call *call) Run(context RunContext, additionalArguments []Argument) Value
 		useArguments = call.arguments
 	}
 
+	useArguments, err := spreadArguments(context, useArguments)
+	if err != nil {
+		return call.pipeResult(context, call.addInfoWhenError(err, false))
+	}
+
 	// when call can not be resolved, try to find the 'func missing' function
 	//
+	key := methodKey(function)
 	if !found {
 		if inDict == nil {
 			value, found = context.Get("?")
@@ -142,6 +210,7 @@ func (call *call) Run(context RunContext, additionalArguments []Argument) Value
 		}
 
 		if found {
+			key = "?"
 			useArguments = createArgumentsForMissingFunc(context, call, useArguments)
 		}
 	}
@@ -149,33 +218,33 @@ func (call *call) Run(context RunContext, additionalArguments []Argument) Value
 	if found {
 
 		if value == nil {
-			return call.pipeResult(context, call.addInfoWhenError(NewErrorValue(fmt.Sprintf("call to %s results in invalid nil value", call.Name()))))
+			return call.pipeResult(context, call.addInfoWhenError(NewErrorValue(fmt.Sprintf("call to %s results in invalid nil value", call.Name())), false))
+		}
+
+		if err := checkKeywordArguments(call.Name(), value, useArguments); err != nil {
+			return call.pipeResult(context, call.addInfoWhenError(err, false))
 		}
 
 		if inDict != nil {
-			this := context.This()
-			context.SetThis(inDict)
-			defer func() {
-				context.SetThis(this)
-			}()
+			defer bindMethod(context, inDict, key, value)()
 		}
 
 		runnable, isRunnable := value.(Runnable)
 
 		if value.Type() == TypeGoFunction {
-			return call.pipeResult(context, call.addInfoWhenError(runnable.Run(context, useArguments)))
+			return call.pipeResult(context, call.addInfoWhenError(limitResult(context, runnable.Run(context, useArguments)), isTraceBoundary(inDict, value)))
 		}
 
 		// runnable values can be used as functions to access their content
 		//
 		if (isRunnable) && (len(useArguments) > 0) {
-			return call.pipeResult(context, call.addInfoWhenError(runnable.Run(context, useArguments)))
+			return call.pipeResult(context, call.addInfoWhenError(limitResult(context, runnable.Run(context, useArguments)), isTraceBoundary(inDict, value)))
 		}
 
-		return call.pipeResult(context, call.addInfoWhenError(value))
+		return call.pipeResult(context, call.addInfoWhenError(value, false))
 	}
 
-	return call.pipeResult(context, call.addInfoWhenError(NewErrorValue(fmt.Sprintf("call to undefined \"%s\"", call.firstArgument))))
+	return call.pipeResult(context, call.addInfoWhenError(NewErrorValue(fmt.Sprintf("call to undefined \"%s\"", call.firstArgument)), false))
 }
 
 func (call *call) String() string {
@@ -3,14 +3,40 @@ package elmo
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 )
 
 type dictValue struct {
 	baseValue
 	frozen bool
 	parent *dictValue
+
+	// values are stored by the hash key of their key, see HashKey
 	values map[string]Value
+
+	// keys holds the hash keys of values in insertion order
+	keys []string
+
+	// keyValues holds the keys that are not strings or identifiers
+	keyValues map[string]Value
+
+	// receiver is set on super references, methods found through
+	// a super reference are called on the receiver, see superOf
+	receiver *dictValue
+
+	// record is set on dictionaries created by the constructor
+	// of a type declared with deftype, see recordType
+	record *recordType
+
+	// goMap is set on dictionaries that wrap a go map, see newDictionaryFromMap.
+	// Values are then read from and written to the map itself
+	goMap reflect.Value
+
+	// lock guards values and frozen so dictionaries
+	// can be shared between actors
+	lock sync.RWMutex
 }
 
 func (dictValue *dictValue) String() string {
@@ -18,15 +44,15 @@ func (dictValue *dictValue) String() string {
 	builder.WriteString("{")
 	writeSep := false
 
-	for _, key := range dictValue.Keys() {
+	for _, key := range dictValue.KeyValues() {
 		if writeSep {
 			builder.WriteString("; ")
 		} else {
 			writeSep = true
 		}
-		builder.WriteString(key)
+		builder.WriteString(key.String())
 		builder.WriteString(": ")
-		value, _ := dictValue.Resolve(key)
+		value, _ := dictValue.ResolveKey(key)
 		builder.WriteString(value.String())
 
 	}
@@ -39,124 +65,311 @@ func (dictValue *dictValue) Type() Type {
 	return TypeDictionary
 }
 
+// keyName returns the textual representation of a hash key
+//
+func (dictValue *dictValue) keyName(key string) string {
+	if keyValue, found := dictValue.keyValues[key]; found {
+		return keyValue.String()
+	}
+	return nameOfHashKey(key)
+}
+
+// currentKeys returns the hash keys of the dictionary in insertion order,
+// keys of a wrapped go map in alphabetical order. Callers hold the lock
+//
+func (dictValue *dictValue) currentKeys() []string {
+	if !dictValue.goMap.IsValid() {
+		return dictValue.keys
+	}
+
+	names := make([]string, 0, dictValue.goMap.Len())
+	for _, key := range dictValue.goMap.MapKeys() {
+		names = append(names, key.String())
+	}
+	sort.Strings(names)
+
+	keys := make([]string, len(names))
+	for i, name := range names {
+		keys[i] = stringHashKey(name)
+	}
+	return keys
+}
+
+// lookup finds the value of a hash key in this dictionary only. Callers hold the lock
+//
+func (dictValue *dictValue) lookup(key string) (Value, bool) {
+	if !dictValue.goMap.IsValid() {
+		value, found := dictValue.values[key]
+		return value, found
+	}
+
+	if !isStringHashKey(key) {
+		return nil, false
+	}
+	value := dictValue.goMap.MapIndex(reflect.ValueOf(nameOfHashKey(key)).Convert(dictValue.goMap.Type().Key()))
+	if !value.IsValid() {
+		return nil, false
+	}
+	return convertGoToValue(value), true
+}
+
+// storeInMap stores a value in a wrapped go map or, when value is nil,
+// removes it. Callers hold the lock
+//
+func (dictValue *dictValue) storeInMap(key string, value Value) ErrorValue {
+	if !isStringHashKey(key) {
+		return NewErrorValue(fmt.Sprintf("can not use %s as key of %v", nameOfHashKey(key), dictValue.goMap.Type()))
+	}
+	mapKey := reflect.ValueOf(nameOfHashKey(key)).Convert(dictValue.goMap.Type().Key())
+
+	if value == nil {
+		dictValue.goMap.SetMapIndex(mapKey, reflect.Value{})
+		return nil
+	}
+
+	converted, err := convertValueToGo(value, dictValue.goMap.Type().Elem())
+	if err != nil {
+		return err
+	}
+	if dictValue.goMap.IsNil() {
+		dictValue.goMap.Set(reflect.MakeMap(dictValue.goMap.Type()))
+	}
+	dictValue.goMap.SetMapIndex(mapKey, converted)
+	return nil
+}
+
+// Internal returns a copy of all values in the dictionary, keys that are
+// not strings are converted to strings
+//
 func (dictValue *dictValue) Internal() interface{} {
-	return dictValue.values
+	dictValue.lock.RLock()
+	defer dictValue.lock.RUnlock()
+
+	keys := dictValue.currentKeys()
+	values := make(map[string]Value, len(keys))
+	for _, k := range keys {
+		values[dictValue.keyName(k)], _ = dictValue.lookup(k)
+	}
+	return values
 }
 
+// Keys returns the keys of the dictionary in insertion order, keys
+// that are not strings are converted to strings
+//
 func (dictValue *dictValue) Keys() []string {
-	keyNames := make([]string, len(dictValue.values))
+	dictValue.lock.RLock()
+	defer dictValue.lock.RUnlock()
 
-	i := 0
-	for k := range dictValue.values {
-		keyNames[i] = k
-		i++
+	current := dictValue.currentKeys()
+	keys := make([]string, len(current))
+	for i, key := range current {
+		keys[i] = dictValue.keyName(key)
 	}
+	return keys
+}
 
-	return keyNames
+// KeyValues returns the keys of the dictionary in insertion order. String
+// keys are returned as strings, all other keys as the value they were set with
+//
+func (dictValue *dictValue) KeyValues() []Value {
+	dictValue.lock.RLock()
+	defer dictValue.lock.RUnlock()
+
+	current := dictValue.currentKeys()
+	keys := make([]Value, len(current))
+	for i, key := range current {
+		if keyValue, found := dictValue.keyValues[key]; found {
+			keys[i] = keyValue
+		} else {
+			keys[i] = NewStringLiteral(nameOfHashKey(key))
+		}
+	}
+	return keys
 }
 
+// put stores a value by its hash key without checking if the dictionary
+// is frozen, new keys are added after all existing keys
+//
+func (dictValue *dictValue) put(key string, keyValue Value, value Value) {
+	if _, found := dictValue.values[key]; !found {
+		dictValue.keys = append(dictValue.keys, key)
+		if !isStringHashKey(key) {
+			if dictValue.keyValues == nil {
+				dictValue.keyValues = make(map[string]Value)
+			}
+			dictValue.keyValues[key] = keyValue
+		}
+	}
+	dictValue.values[key] = value
+}
+
+// Resolve finds the value of a string key in the dictionary or its parents
+//
 func (dictValue *dictValue) Resolve(key string) (Value, bool) {
-	value, found := dictValue.values[key]
+	return dictValue.resolve(stringHashKey(key))
+}
+
+// ResolveKey finds the value of a key of any hashable type in the dictionary
+// or its parents
+//
+func (dictValue *dictValue) ResolveKey(key Value) (Value, bool) {
+	hash, err := HashKey(key)
+	if err != nil {
+		return Nothing, false
+	}
+	return dictValue.resolve(hash)
+}
+
+func (dictValue *dictValue) resolve(key string) (Value, bool) {
+	dictValue.lock.RLock()
+	value, found := dictValue.lookup(key)
+	dictValue.lock.RUnlock()
 
 	if found {
 		return value, true
 	}
 
 	if dictValue.parent != nil {
-		return dictValue.parent.Resolve(key)
+		return dictValue.parent.resolve(key)
 	}
 
 	return Nothing, false
 }
 
 func (dictValue *dictValue) Replace(with DictionaryValue) {
-	newMap := make(map[string]Value)
-	for _, key := range with.Keys() {
-		newMap[key], _ = with.Resolve(key)
+	keys := with.KeyValues()
+	values := make(map[string]Value, len(keys))
+	hashes := make([]string, len(keys))
+	for i, key := range keys {
+		hashes[i], _ = HashKey(key)
+		values[hashes[i]], _ = with.ResolveKey(key)
 	}
-	dictValue.values = newMap
-}
 
-func (dictValue *dictValue) Merge(withAll []DictionaryValue) Value {
-	newMap := make(map[string]Value)
+	dictValue.lock.Lock()
+	defer dictValue.lock.Unlock()
+
+	if dictValue.goMap.IsValid() {
+		if converted, err := convertValueToGo(with, dictValue.goMap.Type()); err == nil {
+			dictValue.goMap.Set(converted)
+		}
+		return
+	}
 
-	for k, v := range dictValue.values {
-		newMap[k] = v
+	dictValue.values = make(map[string]Value, len(keys))
+	dictValue.keys = nil
+	dictValue.keyValues = nil
+	for i, key := range keys {
+		dictValue.put(hashes[i], key, values[hashes[i]])
 	}
+}
 
-	for _, with := range withAll {
-		for _, k := range with.Keys() {
+// Merge creates a new dictionary with the values of this dictionary and all
+// given dictionaries. Keys keep the position of their first occurrence
+//
+func (dictValue *dictValue) Merge(withAll []DictionaryValue) Value {
+	merged := NewDictionaryValue(dictValue.parent, map[string]Value{})
 
-			value, found := with.Resolve(k)
+	for _, with := range append([]DictionaryValue{dictValue}, withAll...) {
+		for _, k := range with.KeyValues() {
+
+			value, found := with.ResolveKey(k)
 			if !found {
 				return NewErrorValue(fmt.Sprintf("could not merge value %s", k))
 			}
-			newMap[k] = value
+			merged.Set(k, value)
 		}
 	}
 
-	return NewDictionaryValue(dictValue.parent, newMap)
+	return merged
 }
 
 func (dictValue *dictValue) Set(symbol Value, value Value) (Value, ErrorValue) {
-	if dictValue.Frozen() {
+	if dictValue.record != nil {
+		return dictValue.setField(symbol, value)
+	}
+
+	key, err := HashKey(symbol)
+	if err != nil {
+		return dictValue, err
+	}
+
+	dictValue.lock.Lock()
+	defer dictValue.lock.Unlock()
+
+	if dictValue.frozen {
 		return dictValue, NewErrorValue("can not set value in frozen dictionary")
 	}
-	dictValue.values[symbol.String()] = value
+	if dictValue.goMap.IsValid() {
+		return dictValue, dictValue.storeInMap(key, value)
+	}
+	dictValue.put(key, symbol, value)
 
 	return dictValue, nil
 }
 
 func (dictValue *dictValue) Remove(symbol Value) (Value, ErrorValue) {
-	if dictValue.Frozen() {
+	key, err := HashKey(symbol)
+	if err != nil {
+		return dictValue, err
+	}
+
+	dictValue.lock.Lock()
+	defer dictValue.lock.Unlock()
+
+	if dictValue.frozen {
 		return dictValue, NewErrorValue("can not remove value from frozen dictionary")
 	}
-	delete(dictValue.values, symbol.String())
 
-	return dictValue, nil
-}
+	if dictValue.record != nil {
+		return dictValue, NewErrorValue(fmt.Sprintf("can not remove field %v from %s", symbol, dictValue.record.name()))
+	}
 
-func (dictValue *dictValue) runInternalCompareFunc(context RunContext, compareFunction Runnable, value Value) (int, ErrorValue) {
-	if runnableCompare, compareIsRunnable := compareFunction.(Runnable); compareIsRunnable {
+	if dictValue.goMap.IsValid() {
+		return dictValue, dictValue.storeInMap(key, nil)
+	}
+	dictValue.removeKey(key)
 
-		subContext := context.CreateSubContext()
-		subContext.SetThis(dictValue)
+	return dictValue, nil
+}
 
-		value := runnableCompare.Run(subContext, []Argument{NewDynamicArgument(value)})
-		if value.Type() == TypeError {
-			return -1, value.(ErrorValue)
-		}
-		if value.Type() == TypeInteger {
-			return int(value.Internal().(int64)), nil
+// removeKey removes a value by its hash key without checking if the
+// dictionary is frozen
+//
+func (dictValue *dictValue) removeKey(key string) {
+	if _, found := dictValue.values[key]; found {
+		delete(dictValue.values, key)
+		delete(dictValue.keyValues, key)
+		for i, k := range dictValue.keys {
+			if k == key {
+				dictValue.keys = append(dictValue.keys[:i:i], dictValue.keys[i+1:]...)
+				break
+			}
 		}
-		return -1, NewErrorValue("found _compare did not return an integer")
 	}
-	return -1, NewErrorValue("found _compare is not runnable")
 }
 
 func (dictValue *dictValue) Compare(context RunContext, value Value) (int, ErrorValue) {
 
-	if value.Type() != TypeDictionary {
-		return 0, NewErrorValue("can not compare dictionary with non dictionary")
-	}
-
 	// when there is a compare function found in the dictionary, simply use that one
 	//
-	if compareFunction, hasCompareFunction := dictValue.Resolve("_compare"); hasCompareFunction {
-		result, err := dictValue.runInternalCompareFunc(context, compareFunction.(Runnable), value)
+	if result, err, hooked := compareByHook(context, dictValue, value); hooked {
 		return result, err
 	}
 
-	keys1 := dictValue.Keys()
-	keys2 := value.(DictionaryValue).Keys()
+	if value.Type() != TypeDictionary {
+		return 0, NewErrorValue("can not compare dictionary with non dictionary")
+	}
+
+	keys1 := dictValue.KeyValues()
+	keys2 := value.(DictionaryValue).KeyValues()
 
 	if len(keys1) == len(keys2) {
 		for _, key := range keys1 {
-			kval2, found2 := value.(DictionaryValue).Resolve(key)
+			kval2, found2 := value.(DictionaryValue).ResolveKey(key)
 			if !found2 {
 				return -1, NewErrorValue("can not compare asymetric dictionaries")
 			}
-			kval1, _ := dictValue.Resolve(key)
+			kval1, _ := dictValue.ResolveKey(key)
 
 			comparable, isComparable := kval1.(ComparableValue)
 			if !isComparable {
@@ -183,8 +396,20 @@ func (dictValue *dictValue) Compare(context RunContext, value Value) (int, Error
 }
 
 func (dictValue *dictValue) Freeze() Value {
+	dictValue.lock.Lock()
 	dictValue.frozen = true
-	for _, value := range dictValue.values {
+	dictValue.lock.Unlock()
+
+	dictValue.lock.RLock()
+	keys := dictValue.currentKeys()
+	values := make([]Value, 0, len(keys))
+	for _, key := range keys {
+		value, _ := dictValue.lookup(key)
+		values = append(values, value)
+	}
+	dictValue.lock.RUnlock()
+
+	for _, value := range values {
 		if freezable, ok := value.(FreezableValue); ok && !freezable.Frozen() {
 			freezable.Freeze()
 		}
@@ -193,13 +418,23 @@ func (dictValue *dictValue) Freeze() Value {
 }
 
 func (dictValue *dictValue) Frozen() bool {
+	dictValue.lock.RLock()
+	defer dictValue.lock.RUnlock()
+
 	return dictValue.frozen
 }
 
+// Run calls the _call hook of the dictionary or, when there is none,
+// returns the value of the key given as first argument
+//
 func (dictValue *dictValue) Run(context RunContext, arguments []Argument) Value {
 
+	if result, hooked := runHook(context, dictValue, hookCall, arguments); hooked {
+		return result
+	}
+
 	key := EvalArgument(context, arguments[0])
-	result, _ := dictValue.Resolve(key.String())
+	result, _ := dictValue.ResolveKey(key)
 	return result
 }
 
@@ -207,27 +442,90 @@ func (dictValue *dictValue) ToBinary() BinaryValue {
 	return NewBinaryValueFromInternal(typeInfoDictionary.ID(), "", Serialize(dictValue))
 }
 
-// NewDictionaryValue creates a new map of values
+// NewDictionaryValue creates a new map of values. Since go maps have no order,
+// the keys of given values are ordered alphabetically. Keys added later on are
+// added after all existing keys
 // TODO: 31okt2016 introduce interface for map parents
 //
 func NewDictionaryValue(parent interface{}, values map[string]Value) DictionaryValue {
+	return NewOrderedDictionaryValue(parent, nil, values)
+}
+
+// NewOrderedDictionaryValue creates a new map of values with keys ordered like
+// given keys. Keys of values that are not given are added in alphabetical order
+//
+func NewOrderedDictionaryValue(parent interface{}, keys []string, values map[string]Value) DictionaryValue {
+
+	ordered := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, key := range keys {
+		if _, found := values[key]; found && !seen[key] {
+			ordered = append(ordered, key)
+			seen[key] = true
+		}
+	}
+
+	if len(ordered) < len(values) {
+		rest := make([]string, 0, len(values)-len(ordered))
+		for key := range values {
+			if !seen[key] {
+				rest = append(rest, key)
+			}
+		}
+		sort.Strings(rest)
+		ordered = append(ordered, rest...)
+	}
+
+	// strings are their own hash key, except for the rare
+	// strings that look like the hash key of another value
+	//
+	rehash := false
+	for i, key := range ordered {
+		if hash := stringHashKey(key); hash != key {
+			ordered[i] = hash
+			rehash = true
+		}
+	}
+	if rehash {
+		values = rehashStringKeys(values)
+	}
+
 	if parent == nil {
-		return &dictValue{baseValue: baseValue{info: typeInfoDictionary}, parent: nil, values: values}
+		return &dictValue{baseValue: baseValue{info: typeInfoDictionary}, parent: nil, values: values, keys: ordered}
+	}
+	return &dictValue{baseValue: baseValue{info: typeInfoDictionary}, parent: parent.(*dictValue), values: values, keys: ordered}
+}
+
+func rehashStringKeys(values map[string]Value) map[string]Value {
+	hashed := make(map[string]Value, len(values))
+	for key, value := range values {
+		hashed[stringHashKey(key)] = value
 	}
-	return &dictValue{baseValue: baseValue{info: typeInfoDictionary}, parent: parent.(*dictValue), values: values}
+	return hashed
+}
+
+// SortedKeys returns the keys of a dictionary in alphabetical order
+//
+func SortedKeys(dict DictionaryValue) []string {
+	keys := dict.Keys()
+	sort.Strings(keys)
+	return keys
 }
 
-// NewDictionaryWithBlock constructs a new dictionary by evaluating given block
+// NewDictionaryWithBlock constructs a new dictionary by evaluating given block.
+// Keys are ordered like they are set by the block
 //
 func NewDictionaryWithBlock(context RunContext, block Block) DictionaryValue {
 
-	// use NewRunContext so block will be evaluated within same scope
+	// use an ordered run context so block will be evaluated within same scope
+	// while remembering the order in which variables are set
 	//
-	subContext := NewRunContext(context)
+	subContext := newOrderedRunContext(context)
 
 	block.Run(subContext, NoArguments)
 
-	return NewDictionaryValue(nil, subContext.Mapping())
+	keys, mapping := subContext.orderedMapping()
+	return NewOrderedDictionaryValue(nil, keys, mapping)
 }
 
 // NewDictionaryFromList constructs a dictionary from a list of values
@@ -242,84 +540,139 @@ func NewDictionaryFromList(parent interface{}, values []Value) Value {
 		return NewErrorValue(fmt.Sprintf("can not create a dictionary from an odd number of elements using %v", values))
 	}
 
-	mapping := make(map[string]Value)
+	dict := NewDictionaryValue(parent, make(map[string]Value))
 
-	var key Value
-
-	for i, val := range values {
-		if i%2 == 0 {
-			key = val
-		} else {
-			mapping[key.String()] = val
+	for i := 0; i < len(values); i = i + 2 {
+		if _, err := dict.Set(values[i], values[i+1]); err != nil {
+			return err
 		}
 	}
 
-	return NewDictionaryValue(parent, mapping)
+	return dict
 
 }
 
-func getStructFieldValue(reflectValue reflect.Value) Value {
+// structFieldName returns the name of a struct field as seen by scripts. Fields
+// can be renamed using an elmo tag or hidden using the tag `elmo:"-"`
+//
+func structFieldName(field reflect.StructField) (string, bool) {
+	if field.PkgPath != "" {
+		return "", false
+	}
 
-	switch reflectValue.Kind() {
-	case reflect.Bool:
-		return TrueOrFalse(reflectValue.Bool())
-	case reflect.String:
-		return NewStringLiteral(reflectValue.String())
-	case reflect.Int64:
-		return NewIntegerLiteral(reflectValue.Int())
-	case reflect.Float64:
-		return NewFloatLiteral(reflectValue.Float())
+	name := strings.Split(field.Tag.Get("elmo"), ",")[0]
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return field.Name, true
 	default:
-		return Nothing
+		return name, true
 	}
 }
 
-func setStructFieldValue(reflectValue reflect.Value, value Value) Value {
+// fieldAccessor is a function that gets or sets the field of a struct
+//
+type fieldAccessor struct {
+	goFunction
+	field reflect.Value
+}
 
-	switch reflectValue.Kind() {
-	case reflect.Bool:
-		if value.Type() != TypeBoolean {
-			return NewErrorValue("expected a boolean value")
-		}
+func structFieldAccessor(name string, fieldValue reflect.Value) NamedValue {
+	accessor := &fieldAccessor{goFunction: goFunction{baseValue: baseValue{info: typeInfoGoFunction}, name: name,
+		help: NewStringLiteral(fmt.Sprintf("get %s (no arguments) or set %s (1 argument)", name, name))}, field: fieldValue}
 
-		reflectValue.SetBool(value.Internal().(bool))
-	case reflect.String:
-		reflectValue.SetString(value.String())
-	case reflect.Int64:
-		reflectValue.SetInt(value.Internal().(int64))
-	case reflect.Float64:
-		reflectValue.SetFloat(value.Internal().(float64))
-	default:
-		return Nothing
+	accessor.value = func(context RunContext, arguments []Argument) Value {
+		if len(arguments) == 0 {
+			// getter
+			return convertGoToValue(fieldValue)
+		}
+		if len(arguments) == 1 {
+			// setter
+			value := EvalArgument(context, arguments[0])
+			if value.Type() == TypeBlock {
+				value = NewDictionaryWithBlock(context, value.(Block)).(Value)
+			}
+			converted, err := convertValueToGo(value, fieldValue.Type())
+			if err != nil {
+				return err
+			}
+			fieldValue.Set(converted)
+			return value
+		}
+		return NewErrorValue("invalid number of arguments")
 	}
-	return value
+
+	return accessor
 }
 
-// NewDictionaryFromStruct construct a dictionary based on the given pointer to a struct
+// newDictionaryFromMap creates a dictionary that reads and writes the values
+// of a settable go map with string keys, so changes made by scripts reach
+// the go value
 //
+func newDictionaryFromMap(goMap reflect.Value) DictionaryValue {
+	return &dictValue{baseValue: baseValue{info: typeInfoDictionary}, values: map[string]Value{}, goMap: goMap}
+}
+
+// NewDictionaryFromStruct construct a dictionary based on the given pointer to a struct.
+// Fields are exposed as functions that get or set their value, nested structs as
+// dictionaries of their own and exported methods as functions. Slices and maps with
+// string keys are exposed as lists and dictionaries that change the field itself
 //
 func NewDictionaryFromStruct(parent interface{}, data interface{}) DictionaryValue {
-	functions := make(map[string]Value, 0)
+	return newDictionaryFromStruct(parent, reflect.ValueOf(data), make(map[structAddress]DictionaryValue))
+}
+
+// structAddress identifies a struct in memory. The address alone is not enough
+// since a struct and its first field share their address
+//
+type structAddress struct {
+	structType reflect.Type
+	address    uintptr
+}
+
+func newDictionaryFromStruct(parent interface{}, pointer reflect.Value, created map[structAddress]DictionaryValue) DictionaryValue {
+
+	// structs that refer to each other share their dictionaries
+	//
+	at := structAddress{structType: pointer.Type(), address: pointer.Pointer()}
+	if dict, found := created[at]; found {
+		return dict
+	}
 
-	structVal := reflect.ValueOf(data).Elem()
+	dict := NewDictionaryValue(parent, make(map[string]Value, 0)).(*dictValue)
+	created[at] = dict
+
+	structVal := pointer.Elem()
 
 	for i := 0; i < structVal.NumField(); i++ {
 
 		fieldType := structVal.Type().Field(i)
 		fieldValue := structVal.Field(i)
 
-		functions[fieldType.Name] = NewGoFunctionWithHelp(fieldType.Name, fmt.Sprintf("get %s (no arguments) or set %s (1 argument)", fieldType.Name, fieldType.Name), func(context RunContext, arguments []Argument) Value {
-			if len(arguments) == 0 {
-				// getter
-				return getStructFieldValue(fieldValue)
-			}
-			if len(arguments) == 1 {
-				// setter
-				return setStructFieldValue(fieldValue, EvalArgument(context, arguments[0]))
-			}
-			return NewErrorValue("invalid number of arguments")
-		})
+		name, visible := structFieldName(fieldType)
+		if !visible {
+			continue
+		}
+
+		// fields keep the order in which they are declared
+		//
+		switch {
+		case fieldValue.Kind() == reflect.Struct:
+			dict.put(stringHashKey(name), nil, newDictionaryFromStruct(nil, fieldValue.Addr(), created).(Value))
+		case fieldValue.Kind() == reflect.Ptr && fieldValue.Type().Elem().Kind() == reflect.Struct && !fieldValue.IsNil():
+			dict.put(stringHashKey(name), nil, newDictionaryFromStruct(nil, fieldValue, created).(Value))
+		default:
+			dict.put(stringHashKey(name), nil, structFieldAccessor(name, fieldValue))
+		}
+	}
+
+	for i := 0; i < pointer.NumMethod(); i++ {
+		method := pointer.Type().Method(i)
+		if _, found := dict.values[stringHashKey(method.Name)]; !found {
+			dict.put(stringHashKey(method.Name), nil, WrapFunc(method.Name, "", pointer.Method(i).Interface()))
+		}
 	}
 
-	return NewDictionaryValue(parent, functions)
+	return dict
 }
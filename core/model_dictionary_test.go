@@ -1,6 +1,7 @@
 package elmo
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -63,3 +64,239 @@ func TestStruct2Dictionary(t *testing.T) {
 	testField(t, context, dict, "IntField", TypeInteger, NewIntegerLiteral(42), NewIntegerLiteral(24))
 	testField(t, context, dict, "FloatField", TypeFloat, NewFloatLiteral(42.24), NewFloatLiteral(24.42))
 }
+
+type testOrigin struct {
+	Country string
+	Region  string `elmo:"area"`
+}
+
+type testPepper struct {
+	Name    string
+	Hotness int
+	Origin  testOrigin
+	Dishes  []string
+	Ratings map[string]int
+	Secret  string `elmo:"-"`
+	Next    *testPepper
+}
+
+func (pepper *testPepper) Hotter(by int) int {
+	pepper.Hotness = pepper.Hotness + by
+	return pepper.Hotness
+}
+
+func (pepper *testPepper) Describe() string {
+	return fmt.Sprintf("%s from %s", pepper.Name, pepper.Origin.Country)
+}
+
+func structContext(pepper *testPepper) RunContext {
+	context := NewGlobalContext()
+	context.Set("pepper", NewDictionaryFromStruct(nil, pepper))
+	return context
+}
+
+func TestStruct2DictionaryExposesMethods(t *testing.T) {
+
+	pepper := &testPepper{Name: "chipotle", Hotness: 3, Origin: testOrigin{Country: "mexico"}}
+
+	ParseTestAndRunBlockWithinContext(t, structContext(pepper),
+		`pepper.Hotter 2`, ExpectValue(t, NewIntegerLiteral(5)))
+
+	if pepper.Hotness != 5 {
+		t.Errorf("expected hotness to be changed by method, found %d", pepper.Hotness)
+	}
+
+	ParseTestAndRunBlockWithinContext(t, structContext(pepper),
+		`pepper.Describe`, ExpectValue(t, NewStringLiteral("chipotle from mexico")))
+
+	ParseTestAndRunBlockWithinContext(t, structContext(pepper),
+		`pepper.Hotter "2"`, ExpectErrorValueAt(t, 1))
+}
+
+func TestStruct2DictionaryExposesNestedValues(t *testing.T) {
+
+	pepper := &testPepper{Name: "chipotle", Origin: testOrigin{Country: "mexico", Region: "puebla"},
+		Dishes: []string{"salsa"}, Ratings: map[string]int{"me": 5}}
+	pepper.Next = &testPepper{Name: "jalapeno", Next: pepper}
+
+	ParseTestAndRunBlockWithinContext(t, structContext(pepper),
+		`pepper.Origin.Country`, ExpectValue(t, NewStringLiteral("mexico")))
+
+	ParseTestAndRunBlockWithinContext(t, structContext(pepper),
+		`pepper.Origin.area`, ExpectValue(t, NewStringLiteral("puebla")))
+
+	ParseTestAndRunBlockWithinContext(t, structContext(pepper),
+		`pepper.Origin.area "oaxaca"`, ExpectValue(t, NewStringLiteral("oaxaca")))
+
+	if pepper.Origin.Region != "oaxaca" {
+		t.Errorf("expected nested field to be changed, found %s", pepper.Origin.Region)
+	}
+
+	ParseTestAndRunBlockWithinContext(t, structContext(pepper),
+		`pepper.Next.Next.Next.Name`, ExpectValue(t, NewStringLiteral("jalapeno")))
+
+	ParseTestAndRunBlockWithinContext(t, structContext(pepper),
+		`pepper.Dishes ["salsa" "mole"]
+		 pepper.Ratings {me: 5; you: 4}
+		 eq (pepper.Dishes) ["salsa" "mole"]`, ExpectValue(t, True))
+
+	if len(pepper.Dishes) != 2 || pepper.Dishes[1] != "mole" || pepper.Ratings["you"] != 4 {
+		t.Errorf("expected slice and map to be set, found %v and %v", pepper.Dishes, pepper.Ratings)
+	}
+
+	pepper.Dishes = append(pepper.Dishes, "tacos")
+
+	ParseTestAndRunBlockWithinContext(t, structContext(pepper),
+		`len (pepper.Dishes)`, ExpectValue(t, NewIntegerLiteral(3)))
+
+	ParseTestAndRunBlockWithinContext(t, structContext(pepper),
+		`pepper.Secret`, ExpectErrorValueAt(t, 1))
+}
+
+type testSauce struct {
+	Base  testOrigin
+	Name  string
+	Heat  *testOrigin
+	Label string
+}
+
+func TestStruct2DictionaryDoesNotMixUpFirstFields(t *testing.T) {
+
+	sauce := &testSauce{Base: testOrigin{Country: "mexico"}, Name: "mole"}
+
+	context := NewGlobalContext()
+	context.Set("sauce", NewDictionaryFromStruct(nil, sauce))
+
+	ParseTestAndRunBlockWithinContext(t, context,
+		`sauce.Base.Country`, ExpectValue(t, NewStringLiteral("mexico")))
+
+	ParseTestAndRunBlockWithinContext(t, context,
+		`sauce.Base.Name`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlockWithinContext(t, context,
+		`sauce.Name`, ExpectValue(t, NewStringLiteral("mole")))
+}
+
+func TestStruct2DictionaryExposesLiveSlicesAndMaps(t *testing.T) {
+
+	pepper := &testPepper{Name: "chipotle", Dishes: []string{"salsa"}, Ratings: map[string]int{"me": 5}}
+
+	context := structContext(pepper)
+	dishes := ParseAndRun(context, `pepper.Dishes`)
+	ratings := ParseAndRun(context, `pepper.Ratings`)
+
+	// changes made by scripts reach the go values
+	//
+	dishes.(ListValue).Append(NewStringLiteral("mole"))
+	if _, err := dishes.(MutableValue).Mutate([]Value{NewStringLiteral("tacos"), NewStringLiteral("mole")}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(pepper.Dishes) != 2 || pepper.Dishes[0] != "tacos" {
+		t.Errorf("expected slice to be changed, found %v", pepper.Dishes)
+	}
+
+	ParseTestAndRunBlockWithinContext(t, context,
+		`ratings: (pepper.Ratings)
+		 ratings.you: 4`)
+	if pepper.Ratings["you"] != 4 {
+		t.Errorf("expected map to be changed, found %v", pepper.Ratings)
+	}
+
+	if _, err := ratings.(DictionaryValue).Set(NewStringLiteral("them"), NewStringLiteral("hot")); err == nil {
+		t.Error("expected an error when setting a value of the wrong type")
+	}
+
+	ratings.(DictionaryValue).Remove(NewStringLiteral("me"))
+	if _, found := pepper.Ratings["me"]; found {
+		t.Errorf("expected key to be removed, found %v", pepper.Ratings)
+	}
+
+	// and changes made by go are seen by scripts
+	//
+	pepper.Dishes[1] = "enchiladas"
+	pepper.Ratings["they"] = 2
+	context.Set("dishes", dishes)
+	context.Set("ratings", ratings)
+
+	ParseTestAndRunBlockWithinContext(t, context,
+		`[(dishes 1) $ratings.they $ratings.you]`, ExpectValue(t, NewListValue([]Value{NewStringLiteral("enchiladas"), NewIntegerLiteral(2), NewIntegerLiteral(4)})))
+}
+
+func TestDecodeValue(t *testing.T) {
+
+	var pepper testPepper
+	dict := ParseAndRun(NewGlobalContext(), `pepper: {
+		name: "chipotle"
+		hotness: 3
+		origin: {country: "mexico"; area: "puebla"}
+		dishes: ["salsa" "mole"]
+		ratings: {me: 5}
+		secret: "hidden"
+	}
+	$pepper`)
+
+	if err := DecodeValue(dict, &pepper); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if pepper.Name != "chipotle" || pepper.Hotness != 3 || pepper.Origin.Country != "mexico" || pepper.Origin.Region != "puebla" ||
+		len(pepper.Dishes) != 2 || pepper.Ratings["me"] != 5 || pepper.Secret != "" {
+		t.Errorf("unexpected decoded value %v", pepper)
+	}
+
+	var copy testPepper
+	if err := DecodeValue(NewDictionaryFromStruct(nil, &pepper).(Value), &copy); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if copy.Name != "chipotle" || copy.Origin.Region != "puebla" || len(copy.Dishes) != 2 {
+		t.Errorf("expected a copy of struct, found %v", copy)
+	}
+
+	if err := DecodeValue(NewStringLiteral("chipotle"), &pepper); err == nil {
+		t.Error("expected an error when decoding a string into a struct")
+	}
+
+	if err := DecodeValue(dict, pepper); err == nil {
+		t.Error("expected an error when decoding into a non pointer")
+	}
+}
+
+func TestDictionaryKeepsInsertionOrder(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`d: {jalapeno: 1; chipotle: 2; habanero: 3}; "\{$d}"`, ExpectValue(t, NewStringLiteral("{jalapeno: 1; chipotle: 2; habanero: 3}")))
+
+	ParseTestAndRunBlock(t,
+		`d: {habanero: 3; chipotle: 2; last: 1; chipotle: 4}; "\{$d}"`, ExpectValue(t, NewStringLiteral("{habanero: 3; chipotle: 4; last: 1}")))
+
+	if list := NewDictionaryFromList(nil, []Value{NewStringLiteral("zz"), True, NewStringLiteral("aa"), True}); list.String() != "{zz: true; aa: true}" {
+		t.Errorf("expected keys in list order, found %v", list)
+	}
+
+	dict := NewDictionaryValue(nil, map[string]Value{"mm": True, "aa": True, "zz": True})
+	if keys := fmt.Sprint(dict.Keys()); keys != "[aa mm zz]" {
+		t.Errorf("expected keys of go map to be sorted, found %s", keys)
+	}
+
+	dict.Set(NewStringLiteral("bb"), True)
+	dict.Remove(NewStringLiteral("mm"))
+	dict.Set(NewStringLiteral("aa"), False)
+	if keys := fmt.Sprint(dict.Keys()); keys != "[aa zz bb]" {
+		t.Errorf("expected new keys to be added at the end, found %s", keys)
+	}
+
+	merged := dict.Merge([]DictionaryValue{NewOrderedDictionaryValue(nil, []string{"cc", "aa"}, map[string]Value{"aa": True, "cc": True})})
+	if keys := fmt.Sprint(merged.(DictionaryValue).Keys()); keys != "[aa zz bb cc]" {
+		t.Errorf("expected merged keys to keep their first position, found %s", keys)
+	}
+
+	if keys := fmt.Sprint(SortedKeys(merged.(DictionaryValue))); keys != "[aa bb cc zz]" {
+		t.Errorf("expected sorted keys, found %s", keys)
+	}
+
+	fields := NewDictionaryFromStruct(nil, &testStruct2DictionaryData{})
+	if keys := fmt.Sprint(fields.Keys()); keys != "[BoolField StringField IntField FloatField]" {
+		t.Errorf("expected fields in declaration order, found %s", keys)
+	}
+}
@@ -8,7 +8,9 @@ type errorValue struct {
 	lineno int
 	msg    string
 	fatal  bool
+	abort  bool
 	ignore bool
+	trace  []TraceFrame
 
 	token *token32
 }
@@ -67,6 +69,10 @@ func (errorValue *errorValue) IsFatal() bool {
 	return errorValue.fatal
 }
 
+func (errorValue *errorValue) IsAborted() bool {
+	return errorValue.abort
+}
+
 func (errorValue *errorValue) Ignore() ErrorValue {
 	errorValue.ignore = true
 	errorValue.fatal = false
@@ -77,6 +83,62 @@ func (errorValue *errorValue) CanBeIgnored() bool {
 	return errorValue.ignore
 }
 
+// AddTrace returns a copy of the error with given frame added to its trace.
+// The error itself is not changed so it can be stored and passed on again
+// without its trace growing
+//
+func (errorValue *errorValue) AddTrace(frame TraceFrame) ErrorValue {
+	return withFrame(errorValue, frame)
+}
+
+func withFrame(err *errorValue, frame TraceFrame) *errorValue {
+	trace := make([]TraceFrame, len(err.trace), len(err.trace)+1)
+	copy(trace, err.trace)
+
+	return &errorValue{baseValue: baseValue{info: err.info},
+		meta: err.meta, lineno: err.lineno, msg: err.msg,
+		fatal: err.fatal, abort: err.abort, ignore: err.ignore,
+		trace: append(trace, frame), token: err.token}
+}
+
+func (errorValue *errorValue) Trace() []TraceFrame {
+	return errorValue.trace
+}
+
+func (frame TraceFrame) String() string {
+	return fmt.Sprintf("at %s (%s:%d:%d)", frame.Function, frame.Script, frame.Line, frame.Column)
+}
+
+func traceToList(trace []TraceFrame) ListValue {
+	frames := make([]Value, len(trace))
+	for i, frame := range trace {
+		frames[i] = NewDictionaryValue(nil, map[string]Value{
+			"script":   NewStringLiteral(frame.Script),
+			"line":     NewIntegerLiteral(int64(frame.Line)),
+			"column":   NewIntegerLiteral(int64(frame.Column)),
+			"function": NewStringLiteral(frame.Function)})
+	}
+	return NewListValue(frames)
+}
+
+// errorDictionary converts an error into a dictionary so it can be inspected
+// from within elmo code
+//
+func errorDictionary(err ErrorValue) DictionaryValue {
+	mapping := map[string]Value{
+		"message": NewStringLiteral(fmt.Sprintf("%v", err.Internal())),
+		"fatal":   TrueOrFalse(err.IsFatal()),
+		"trace":   traceToList(err.Trace()),
+		"error":   err}
+
+	if meta, lineno := err.At(); meta != nil {
+		mapping["file"] = NewStringLiteral(meta.Name())
+		mapping["line"] = NewIntegerLiteral(int64(lineno))
+	}
+
+	return NewDictionaryValue(nil, mapping)
+}
+
 // NewErrorValue creates a new Error
 //
 func NewErrorValue(msg string) ErrorValue {
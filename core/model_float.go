@@ -35,7 +35,7 @@ func (floatLiteral *floatLiteral) Increment(value Value) Value {
 	if value.Type() == TypeInteger {
 		return NewFloatLiteral(floatLiteral.value + float64(value.Internal().(int64)))
 	}
-	return NewErrorValue("can not add non number to float")
+	return calculate('+', floatLiteral, value, "can not add non number to float")
 }
 
 func (floatLiteral *floatLiteral) Plus(value Value) Value {
@@ -49,7 +49,7 @@ func (floatLiteral *floatLiteral) Minus(value Value) Value {
 	if value.Type() == TypeInteger {
 		return NewFloatLiteral(floatLiteral.value - float64(value.Internal().(int64)))
 	}
-	return NewErrorValue("can not subtract non number from float")
+	return calculate('-', floatLiteral, value, "can not subtract non number from float")
 }
 
 func (floatLiteral *floatLiteral) Multiply(value Value) Value {
@@ -59,7 +59,7 @@ func (floatLiteral *floatLiteral) Multiply(value Value) Value {
 	if value.Type() == TypeInteger {
 		return NewFloatLiteral(floatLiteral.value * float64(value.Internal().(int64)))
 	}
-	return NewErrorValue("can not multiply float by non number")
+	return calculate('*', floatLiteral, value, "can not multiply float by non number")
 }
 
 func (floatLiteral *floatLiteral) Divide(value Value) Value {
@@ -75,7 +75,7 @@ func (floatLiteral *floatLiteral) Divide(value Value) Value {
 		}
 		return NewFloatLiteral(floatLiteral.value / float64(value.Internal().(int64)))
 	}
-	return NewErrorValue("can not multiply float by non number")
+	return calculate('/', floatLiteral, value, "can not divide float by non number")
 }
 
 func (floatLiteral *floatLiteral) Modulo(value Value) Value {
@@ -99,7 +99,7 @@ func (floatLiteral *floatLiteral) Modulo(value Value) Value {
 
 		return NewFloatLiteral(floatLiteral.value - total)
 	}
-	return NewErrorValue("can not divide float by non number to calculate a modulo")
+	return calculate('%', floatLiteral, value, "can not divide float by non number to calculate a modulo")
 }
 
 func (floatLiteral *floatLiteral) Compare(context RunContext, value Value) (int, ErrorValue) {
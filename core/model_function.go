@@ -2,6 +2,7 @@ package elmo
 
 import (
 	"fmt"
+	"strings"
 )
 
 // GoFunction is a native go function that takes an array of input values
@@ -15,6 +16,12 @@ type goFunction struct {
 	help  Value
 	value GoFunction
 	block Block
+
+	// errors returned by a boundary get the location of its call in their trace
+	boundary bool
+
+	// names of the keyword arguments the function accepts
+	keywords []string
 }
 
 func (goFunction *goFunction) String() string {
@@ -37,6 +44,46 @@ func (goFunction *goFunction) Run(context RunContext, arguments []Argument) Valu
 	return goFunction.value(context, arguments)
 }
 
+func (goFunction *goFunction) acceptsKeyword(keyword string) bool {
+	for _, accepted := range goFunction.keywords {
+		if accepted == keyword {
+			return true
+		}
+	}
+	return false
+}
+
+// keywordAcceptor is implemented by functions that declare keyword arguments
+//
+type keywordAcceptor interface {
+	acceptsKeyword(keyword string) bool
+}
+
+// checkKeywordArguments makes sure a function declares all keyword arguments
+// it's called with and gets each of them once. Functions that don't declare
+// keyword arguments would otherwise treat them as positional arguments
+//
+func checkKeywordArguments(name string, value Value, arguments []Argument) ErrorValue {
+	var seen map[string]bool
+	for _, argument := range arguments {
+		keyword, isKeyword := argument.(*keywordArgument)
+		if !isKeyword {
+			continue
+		}
+		if seen[keyword.keyword] {
+			return NewErrorValue(fmt.Sprintf("argument %s is passed more than once to %s", keyword.keyword, name))
+		}
+		if seen == nil {
+			seen = map[string]bool{}
+		}
+		seen[keyword.keyword] = true
+		if acceptor, isAcceptor := value.(keywordAcceptor); !isAcceptor || !acceptor.acceptsKeyword(keyword.keyword) {
+			return NewErrorValue(fmt.Sprintf("unknown argument %s for %s", keyword.keyword, name))
+		}
+	}
+	return nil
+}
+
 func (goFunction *goFunction) Help() Value {
 	if goFunction.help == nil {
 		return Nothing
@@ -46,7 +93,41 @@ func (goFunction *goFunction) Help() Value {
 
 type inspectableGoFunction struct {
 	goFunction
-	argNames []string
+	argNames   []string
+	parameters []*parameter
+}
+
+// Help returns the help of a user defined function. When its parameters are
+// annotated, or no help is given, usage of the function is added
+//
+func (inspectableGoFunction *inspectableGoFunction) Help() Value {
+	if inspectableGoFunction.parameters == nil {
+		return inspectableGoFunction.goFunction.Help()
+	}
+
+	annotated := false
+	for _, parameter := range inspectableGoFunction.parameters {
+		annotated = annotated || parameter.typeName != "" || parameter.optional || parameter.rest
+	}
+
+	help := inspectableGoFunction.goFunction.Help().String()
+	usage := strings.TrimSpace(fmt.Sprintf("usage: %s %s", inspectableGoFunction.name, parametersUsage(inspectableGoFunction.parameters)))
+	if help == "" {
+		return NewStringLiteral(usage)
+	}
+	if annotated {
+		return NewStringLiteral(help + "\n" + usage)
+	}
+	return inspectableGoFunction.goFunction.Help()
+}
+
+// nameWhenAnonymous gives an anonymous function the name of the
+// variable it is first assigned to
+//
+func (inspectableGoFunction *inspectableGoFunction) nameWhenAnonymous(name string) {
+	if inspectableGoFunction.name == "anonymous" {
+		inspectableGoFunction.name = name
+	}
 }
 
 func (inspectableGoFunction *inspectableGoFunction) Block() Block {
@@ -67,6 +148,26 @@ func (inspectableGoFunction *inspectableGoFunction) EndsAt() uint32 {
 
 func (inspectableGoFunction *inspectableGoFunction) Enrich(dict DictionaryValue) {
 	dict.Set(NewStringLiteral("arguments"), NewListValueFromStrings(inspectableGoFunction.argNames))
+
+	if inspectableGoFunction.parameters == nil {
+		return
+	}
+
+	parameters := make([]Value, len(inspectableGoFunction.parameters))
+	for i, parameter := range inspectableGoFunction.parameters {
+		meta := NewDictionaryValue(nil, map[string]Value{
+			"name":     NewStringLiteral(parameter.name),
+			"optional": TrueOrFalse(parameter.optional),
+			"rest":     TrueOrFalse(parameter.rest)})
+		if parameter.typeName != "" {
+			meta.Set(NewStringLiteral("type"), NewStringLiteral(parameter.typeName))
+		}
+		if parameter.defaultValue != nil {
+			meta.Set(NewStringLiteral("default"), parameter.defaultValue)
+		}
+		parameters[i] = meta
+	}
+	dict.Set(NewStringLiteral("parameters"), NewListValue(parameters))
 }
 
 // NewGoFunctionWithBlock creates a new go function and stores the block of code for later inspection
@@ -80,9 +181,24 @@ func NewGoFunctionWithBlock(name string, help string, value GoFunction, argNames
 		block:     block}, argNames: argNames}
 }
 
+// asTraceBoundary marks a go function as boundary for error traces
+//
+func asTraceBoundary(value NamedValue) NamedValue {
+	value.(*goFunction).boundary = true
+	return value
+}
+
 // NewGoFunctionWithHelp creates a new go function
 //
 func NewGoFunctionWithHelp(name string, help string, value GoFunction) NamedValue {
 
 	return &goFunction{baseValue: baseValue{info: typeInfoGoFunction}, name: name, help: NewStringLiteral(help), value: value}
 }
+
+// NewGoFunctionWithKeywords creates a new go function that accepts given
+// keyword arguments. Use SplitArguments to get them
+//
+func NewGoFunctionWithKeywords(name string, help string, keywords []string, value GoFunction) NamedValue {
+
+	return &goFunction{baseValue: baseValue{info: typeInfoGoFunction}, name: name, help: NewStringLiteral(help), value: value, keywords: keywords}
+}
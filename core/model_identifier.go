@@ -41,6 +41,12 @@ func (identifier *identifier) LookUp(context RunContext) (DictionaryValue, Value
 		return nil, result, true
 	}
 
+	// errors can be inspected as if they are dictionaries
+	//
+	if result.Type() == TypeError {
+		result = errorDictionary(result.(ErrorValue))
+	}
+
 	if result.Type() != TypeDictionary {
 		return nil, NewErrorValue(fmt.Sprintf("%s is not a dictionary", identifier.value[0])), false
 	}
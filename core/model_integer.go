@@ -1,6 +1,9 @@
 package elmo
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
 type integerLiteral struct {
 	baseValue
@@ -29,39 +32,48 @@ func (integerLiteral *integerLiteral) Internal() interface{} {
 
 func (integerLiteral *integerLiteral) Increment(value Value) Value {
 	if value.Type() == TypeInteger {
-		return NewIntegerLiteral(integerLiteral.value + value.Internal().(int64))
+		return integerLiteral.Plus(value)
+	}
+	if value.Type() == TypeBigInteger {
+		return calculate('+', integerLiteral, value, "")
 	}
 	return NewErrorValue("can not add non integer to integer")
 }
 
 func (integerLiteral *integerLiteral) Plus(value Value) Value {
 	if value.Type() == TypeInteger {
-		return NewIntegerLiteral(integerLiteral.value + value.Internal().(int64))
+		if result, ok := addInt64(integerLiteral.value, value.Internal().(int64)); ok {
+			return NewIntegerLiteral(result)
+		}
 	}
 	if value.Type() == TypeFloat {
 		return NewFloatLiteral(float64(integerLiteral.value) + value.Internal().(float64))
 	}
-	return NewErrorValue("can not add non number to integer")
+	return calculate('+', integerLiteral, value, "can not add non number to integer")
 }
 
 func (integerLiteral *integerLiteral) Minus(value Value) Value {
 	if value.Type() == TypeInteger {
-		return NewIntegerLiteral(integerLiteral.value - value.Internal().(int64))
+		if result, ok := subtractInt64(integerLiteral.value, value.Internal().(int64)); ok {
+			return NewIntegerLiteral(result)
+		}
 	}
 	if value.Type() == TypeFloat {
 		return NewFloatLiteral(float64(integerLiteral.value) - value.Internal().(float64))
 	}
-	return NewErrorValue("can not subtract non number from integer")
+	return calculate('-', integerLiteral, value, "can not subtract non number from integer")
 }
 
 func (integerLiteral *integerLiteral) Multiply(value Value) Value {
 	if value.Type() == TypeInteger {
-		return NewIntegerLiteral(integerLiteral.value * value.Internal().(int64))
+		if result, ok := multiplyInt64(integerLiteral.value, value.Internal().(int64)); ok {
+			return NewIntegerLiteral(result)
+		}
 	}
 	if value.Type() == TypeFloat {
 		return NewFloatLiteral(float64(integerLiteral.value) * value.Internal().(float64))
 	}
-	return NewErrorValue("can not multiply non number with integer")
+	return calculate('*', integerLiteral, value, "can not multiply non number with integer")
 }
 
 func (integerLiteral *integerLiteral) Divide(value Value) Value {
@@ -69,7 +81,9 @@ func (integerLiteral *integerLiteral) Divide(value Value) Value {
 		if value.Internal().(int64) == 0 {
 			return NewErrorValue("can not divide integer by 0")
 		}
-		return NewIntegerLiteral(integerLiteral.value / value.Internal().(int64))
+		if integerLiteral.value != math.MinInt64 || value.Internal().(int64) != -1 {
+			return NewIntegerLiteral(integerLiteral.value / value.Internal().(int64))
+		}
 	}
 	if value.Type() == TypeFloat {
 		if value.Internal().(float64) == 0.0 {
@@ -77,7 +91,7 @@ func (integerLiteral *integerLiteral) Divide(value Value) Value {
 		}
 		return NewFloatLiteral(float64(integerLiteral.value) / value.Internal().(float64))
 	}
-	return NewErrorValue("can not divide integer by non number")
+	return calculate('/', integerLiteral, value, "can not divide integer by non number")
 }
 
 func (integerLiteral *integerLiteral) Modulo(value Value) Value {
@@ -87,7 +101,10 @@ func (integerLiteral *integerLiteral) Modulo(value Value) Value {
 		}
 		return NewIntegerLiteral(integerLiteral.value % value.Internal().(int64))
 	}
-	return NewErrorValue("can not divide integer by non integer to calculate a modulo")
+	if value.Type() == TypeFloat {
+		return NewErrorValue("can not divide integer by non integer to calculate a modulo")
+	}
+	return calculate('%', integerLiteral, value, "can not divide integer by non integer to calculate a modulo")
 }
 
 func (integerLiteral *integerLiteral) Compare(context RunContext, value Value) (int, ErrorValue) {
@@ -103,7 +120,7 @@ func (integerLiteral *integerLiteral) Compare(context RunContext, value Value) (
 		}
 		return 0, nil
 	}
-	return 0, NewErrorValue("can not compare integer with non integer")
+	return compareNumbers(integerLiteral, value, "can not compare integer with non integer")
 }
 
 func (integerLiteral *integerLiteral) ToBinary() BinaryValue {
@@ -0,0 +1,132 @@
+package elmo
+
+import "fmt"
+
+type iterator struct {
+	next   func() (Value, bool)
+	close  func()
+	ended  bool
+	closed bool
+}
+
+func (iterator *iterator) Next() (Value, bool) {
+	if iterator.ended {
+		return nil, false
+	}
+
+	value, ok := iterator.next()
+	if !ok || value.Type() == TypeError {
+		iterator.ended = true
+	}
+	return value, ok
+}
+
+func (iterator *iterator) Close() {
+	if iterator.closed {
+		return
+	}
+	iterator.closed = true
+	iterator.ended = true
+	if iterator.close != nil {
+		iterator.close()
+	}
+}
+
+// NewIterator constructs an iterator that produces values by calling next.
+// Given close function, which may be nil, is called at most once
+//
+func NewIterator(next func() (Value, bool), close func()) Iterator {
+	return &iterator{next: next, close: close}
+}
+
+// NewErrorIterator constructs an iterator that only produces given error
+//
+func NewErrorIterator(err ErrorValue) Iterator {
+	return NewIterator(func() (Value, bool) {
+		return err, true
+	}, nil)
+}
+
+// NewSliceIterator constructs an iterator over the values of a slice
+//
+func NewSliceIterator(values []Value) Iterator {
+	index := 0
+	return NewIterator(func() (Value, bool) {
+		if index >= len(values) {
+			return nil, false
+		}
+		index = index + 1
+		return values[index-1], true
+	}, nil)
+}
+
+type iterableValue struct {
+	baseValue
+	iterate func() Iterator
+}
+
+func (iterableValue *iterableValue) String() string {
+	return "iterable"
+}
+
+func (iterableValue *iterableValue) Type() Type {
+	return TypeInternal
+}
+
+func (iterableValue *iterableValue) Internal() interface{} {
+	return iterableValue
+}
+
+func (iterableValue *iterableValue) Iterate() Iterator {
+	return iterableValue.iterate()
+}
+
+// NewIterableValue constructs a lazy sequence of values. Given function is
+// called every time the sequence is iterated
+//
+func NewIterableValue(iterate func() Iterator) Value {
+	return &iterableValue{baseValue: baseValue{info: typeInfoIterable}, iterate: iterate}
+}
+
+// AsIterable returns the iterable of a value or false when the value
+// can not be iterated
+//
+func AsIterable(value Value) (IterableValue, bool) {
+	if iterable, ok := value.(IterableValue); ok {
+		return iterable, true
+	}
+	iterable, ok := value.Internal().(IterableValue)
+	return iterable, ok
+}
+
+// Iterate returns an iterator over the values of an iterable value or of
+// a value that can be converted to a list
+//
+func Iterate(value Value) (Iterator, ErrorValue) {
+	if iterable, ok := AsIterable(value); ok {
+		return iterable.Iterate(), nil
+	}
+	if listable, ok := value.Internal().(Listable); ok {
+		return NewSliceIterator(listable.List()), nil
+	}
+	return nil, NewErrorValue(fmt.Sprintf("can not iterate over %v", value))
+}
+
+// CollectValues materializes all values of an iterable value
+//
+func CollectValues(context RunContext, iterable IterableValue) ([]Value, ErrorValue) {
+	iterator := iterable.Iterate()
+	defer iterator.Close()
+
+	values := []Value{}
+	for value, ok := iterator.Next(); ok; value, ok = iterator.Next() {
+		if value.Type() == TypeError {
+			return nil, value.(ErrorValue)
+		}
+		values = append(values, value)
+		if err := CheckCollectionSize(context, len(values)); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
@@ -1,15 +1,65 @@
 package elmo
 
-import "fmt"
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
 
 type listValue struct {
 	baseValue
 	frozen bool
 	values []Value
+
+	// slice is set on lists that wrap a go slice, see newListFromSlice.
+	// Values are then read from and written to the slice itself
+	slice reflect.Value
+
+	// lock guards values and frozen so lists
+	// can be shared between actors
+	lock sync.RWMutex
+}
+
+// current returns the current values of the list, callers hold the lock
+//
+func (listValue *listValue) current() []Value {
+	if listValue.slice.IsValid() {
+		values := make([]Value, listValue.slice.Len())
+		for i := range values {
+			values[i] = convertGoToValue(listValue.slice.Index(i))
+		}
+		return values
+	}
+	return listValue.values[:len(listValue.values):len(listValue.values)]
+}
+
+// store replaces the values of the list, callers hold the lock
+//
+func (listValue *listValue) store(values []Value) ErrorValue {
+	if listValue.slice.IsValid() {
+		converted, err := convertValueToGo(NewListValue(values), listValue.slice.Type())
+		if err != nil {
+			return err
+		}
+		listValue.slice.Set(converted)
+		return nil
+	}
+	listValue.values = values
+	return nil
+}
+
+// snapshot returns the current values of the list. Its capacity is limited
+// so appending to it will never change the values of the list
+//
+func (listValue *listValue) snapshot() []Value {
+	listValue.lock.RLock()
+	defer listValue.lock.RUnlock()
+
+	return listValue.current()
 }
 
 func (listValue *listValue) String() string {
-	return fmt.Sprintf("%v", listValue.values)
+	return fmt.Sprintf("%v", listValue.snapshot())
 }
 
 func (listValue *listValue) Type() Type {
@@ -17,10 +67,16 @@ func (listValue *listValue) Type() Type {
 }
 
 func (listValue *listValue) Internal() interface{} {
-	return listValue.values
+	return listValue.snapshot()
 }
 
-func (listValue *listValue) index(context RunContext, argument Argument) (int, ErrorValue) {
+// Iterate iterates over the values the list has when iteration starts
+//
+func (listValue *listValue) Iterate() Iterator {
+	return NewSliceIterator(listValue.snapshot())
+}
+
+func index(context RunContext, values []Value, argument Argument) (int, ErrorValue) {
 	indexValue := EvalArgument(context, argument)
 
 	if indexValue.Type() != TypeInteger {
@@ -32,10 +88,10 @@ func (listValue *listValue) index(context RunContext, argument Argument) (int, E
 	// negative index will be used to get elemnts from the end of the list
 	//
 	if i < 0 {
-		i = len(listValue.values) + i
+		i = len(values) + i
 	}
 
-	if i < 0 || i >= len(listValue.values) {
+	if i < 0 || i >= len(values) {
 		return 0, NewErrorValue("list accessor out of bounds")
 	}
 
@@ -44,23 +100,24 @@ func (listValue *listValue) index(context RunContext, argument Argument) (int, E
 
 func (listValue *listValue) Run(context RunContext, arguments []Argument) Value {
 	arglen := len(arguments)
+	values := listValue.snapshot()
 
 	if arglen == 1 {
-		i, err := listValue.index(context, arguments[0])
+		i, err := index(context, values, arguments[0])
 
 		if err != nil {
 			return err
 		}
 
-		return listValue.values[i]
+		return values[i]
 	}
 
 	if arglen == 2 {
-		i1, err := listValue.index(context, arguments[0])
+		i1, err := index(context, values, arguments[0])
 		if err != nil {
 			return err
 		}
-		i2, err := listValue.index(context, arguments[1])
+		i2, err := index(context, values, arguments[1])
 		if err != nil {
 			return err
 		}
@@ -68,7 +125,7 @@ func (listValue *listValue) Run(context RunContext, arguments []Argument) Value
 		if i1 > i2 {
 			// return a reversed version of the sub list
 
-			list := listValue.values[i2 : i1+1]
+			list := values[i2 : i1+1]
 			length := len(list)
 			reversed := make([]Value, length)
 			copy(reversed, list)
@@ -78,7 +135,7 @@ func (listValue *listValue) Run(context RunContext, arguments []Argument) Value
 			return NewListValue(reversed)
 		}
 
-		return NewListValue(listValue.values[i1 : i2+1])
+		return NewListValue(values[i1 : i2+1 : i2+1])
 
 	}
 
@@ -90,7 +147,7 @@ func (listValue *listValue) Compare(context RunContext, value Value) (int, Error
 		return 0, NewErrorValue("can not compare list with non list")
 	}
 
-	v1 := listValue.values
+	v1 := listValue.snapshot()
 	v2 := value.Internal().([]Value)
 
 	for i := range v1 {
@@ -119,26 +176,52 @@ func (listValue *listValue) Compare(context RunContext, value Value) (int, Error
 	return 0, nil
 }
 
+// Append adds a value to the list. Values that can not be converted to
+// the elements of a wrapped go slice are not added
+//
 func (listValue *listValue) Append(value Value) {
+	listValue.lock.Lock()
+	defer listValue.lock.Unlock()
+
+	if listValue.slice.IsValid() {
+		listValue.store(append(listValue.current(), value))
+		return
+	}
 	listValue.values = append(listValue.values, value)
 }
 
 func (listValue *listValue) List() []Value {
-	return listValue.values
+	return listValue.snapshot()
 }
 
 func (listValue *listValue) Mutate(value interface{}) (Value, ErrorValue) {
-	if listValue.Frozen() {
+	return listValue.Update(func(interface{}) (interface{}, ErrorValue) {
+		return value, nil
+	})
+}
+
+func (listValue *listValue) Update(change func(current interface{}) (interface{}, ErrorValue)) (Value, ErrorValue) {
+	listValue.lock.Lock()
+	defer listValue.lock.Unlock()
+
+	if listValue.frozen {
 		return listValue, NewErrorValue("can not mutate frozen value")
 	}
 
-	listValue.values = value.([]Value)
-	return listValue, nil
+	changed, err := change(listValue.current())
+	if err != nil {
+		return listValue, err
+	}
+
+	return listValue, listValue.store(changed.([]Value))
 }
 
 func (listValue *listValue) Freeze() Value {
+	listValue.lock.Lock()
 	listValue.frozen = true
-	for _, value := range listValue.values {
+	listValue.lock.Unlock()
+
+	for _, value := range listValue.snapshot() {
 		if freezable, ok := value.(FreezableValue); ok && !freezable.Frozen() {
 			freezable.Freeze()
 		}
@@ -147,6 +230,9 @@ func (listValue *listValue) Freeze() Value {
 }
 
 func (listValue *listValue) Frozen() bool {
+	listValue.lock.RLock()
+	defer listValue.lock.RUnlock()
+
 	return listValue.frozen
 }
 
@@ -155,7 +241,7 @@ func (listValue *listValue) ToBinary() BinaryValue {
 }
 
 func (listValue *listValue) Length() Value {
-	return NewIntegerLiteral(int64(len(listValue.values)))
+	return NewIntegerLiteral(int64(len(listValue.snapshot())))
 }
 
 // NewListValue creates a new list of values
@@ -164,6 +250,13 @@ func NewListValue(values []Value) ListValue {
 	return &listValue{baseValue: baseValue{info: typeInfoList}, values: values}
 }
 
+// newListFromSlice creates a list that reads and writes the elements of
+// a settable go slice, so changes made by scripts reach the go value
+//
+func newListFromSlice(slice reflect.Value) ListValue {
+	return &listValue{baseValue: baseValue{info: typeInfoList}, slice: slice}
+}
+
 // NewListValueFromStrings converts a slice of strings into an elmo list
 //
 func NewListValueFromStrings(strings []string) ListValue {
@@ -0,0 +1,380 @@
+package elmo
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+// maxDecimalScale is the number of fractional digits used for the result
+// of a decimal division that can not be represented exactly
+//
+const maxDecimalScale = 32
+
+type numberKind int
+
+// kinds of numbers ordered by precision, when mixing numbers
+// the result gets the kind of the least precise number
+//
+const (
+	notANumber numberKind = iota
+	exactInteger
+	exactDecimal
+	inexactFloat
+)
+
+func kindOfNumber(value Value) numberKind {
+	switch value.Type() {
+	case TypeInteger, TypeBigInteger:
+		return exactInteger
+	case TypeDecimal:
+		return exactDecimal
+	case TypeFloat:
+		return inexactFloat
+	}
+	return notANumber
+}
+
+type bigIntegerValue struct {
+	baseValue
+	value *big.Int
+}
+
+func (bigIntegerValue *bigIntegerValue) String() string {
+	return bigIntegerValue.value.String()
+}
+
+func (bigIntegerValue *bigIntegerValue) Type() Type {
+	return TypeBigInteger
+}
+
+func (bigIntegerValue *bigIntegerValue) Internal() interface{} {
+	return bigIntegerValue.value
+}
+
+func (bigIntegerValue *bigIntegerValue) Increment(value Value) Value {
+	if kindOfNumber(value) != exactInteger {
+		return NewErrorValue("can not add non integer to integer")
+	}
+	return calculate('+', bigIntegerValue, value, "")
+}
+
+func (bigIntegerValue *bigIntegerValue) Plus(value Value) Value {
+	return calculate('+', bigIntegerValue, value, "can not add non number to integer")
+}
+
+func (bigIntegerValue *bigIntegerValue) Minus(value Value) Value {
+	return calculate('-', bigIntegerValue, value, "can not subtract non number from integer")
+}
+
+func (bigIntegerValue *bigIntegerValue) Multiply(value Value) Value {
+	return calculate('*', bigIntegerValue, value, "can not multiply non number with integer")
+}
+
+func (bigIntegerValue *bigIntegerValue) Divide(value Value) Value {
+	return calculate('/', bigIntegerValue, value, "can not divide integer by non number")
+}
+
+func (bigIntegerValue *bigIntegerValue) Modulo(value Value) Value {
+	if value.Type() == TypeFloat {
+		return NewErrorValue("can not divide integer by non integer to calculate a modulo")
+	}
+	return calculate('%', bigIntegerValue, value, "can not divide integer by non integer to calculate a modulo")
+}
+
+func (bigIntegerValue *bigIntegerValue) Compare(context RunContext, value Value) (int, ErrorValue) {
+	return compareNumbers(bigIntegerValue, value, "can not compare integer with non integer")
+}
+
+func (bigIntegerValue *bigIntegerValue) ToBinary() BinaryValue {
+	return NewBinaryValueFromInternal(typeInfoBigInteger.ID(), "", bigIntegerValue.value)
+}
+
+type decimalValue struct {
+	baseValue
+	value *big.Rat
+	scale int
+}
+
+func (decimalValue *decimalValue) String() string {
+	return decimalValue.value.FloatString(decimalValue.scale)
+}
+
+func (decimalValue *decimalValue) Type() Type {
+	return TypeDecimal
+}
+
+func (decimalValue *decimalValue) Internal() interface{} {
+	return decimalValue.value
+}
+
+func (decimalValue *decimalValue) Increment(value Value) Value {
+	return decimalValue.Plus(value)
+}
+
+func (decimalValue *decimalValue) Plus(value Value) Value {
+	return calculate('+', decimalValue, value, "can not add non number to decimal")
+}
+
+func (decimalValue *decimalValue) Minus(value Value) Value {
+	return calculate('-', decimalValue, value, "can not subtract non number from decimal")
+}
+
+func (decimalValue *decimalValue) Multiply(value Value) Value {
+	return calculate('*', decimalValue, value, "can not multiply decimal by non number")
+}
+
+func (decimalValue *decimalValue) Divide(value Value) Value {
+	return calculate('/', decimalValue, value, "can not divide decimal by non number")
+}
+
+func (decimalValue *decimalValue) Modulo(value Value) Value {
+	return calculate('%', decimalValue, value, "can not divide decimal by non number to calculate a modulo")
+}
+
+func (decimalValue *decimalValue) Compare(context RunContext, value Value) (int, ErrorValue) {
+	return compareNumbers(decimalValue, value, "can not compare decimal with non integer or non decimal")
+}
+
+func (decimalValue *decimalValue) ToBinary() BinaryValue {
+	return NewBinaryValueFromInternal(typeInfoDecimal.ID(), "", decimalValue.String())
+}
+
+// NewIntegerFromBig creates an integer value from a big integer. The result
+// is a regular integer when the value fits in 64 bits
+//
+func NewIntegerFromBig(value *big.Int) Value {
+	if value.IsInt64() {
+		return NewIntegerLiteral(value.Int64())
+	}
+	return &bigIntegerValue{baseValue: baseValue{info: typeInfoBigInteger}, value: value}
+}
+
+// NewDecimalValue creates a decimal value with given number of fractional
+// digits. Values with more digits are rounded, halves away from zero
+//
+func NewDecimalValue(value *big.Rat, scale int) Value {
+	if scale < 0 {
+		scale = 0
+	}
+	rounded, _ := new(big.Rat).SetString(value.FloatString(scale))
+	return &decimalValue{baseValue: baseValue{info: typeInfoDecimal}, value: rounded, scale: scale}
+}
+
+// NewDecimalFromString creates a decimal value from its textual representation,
+// like 3.14 or -0.50. The number of fractional digits determines the scale
+//
+func NewDecimalFromString(text string) (Value, ErrorValue) {
+	text = strings.TrimSpace(text)
+
+	digits := strings.TrimLeft(text, "+-")
+	if len(text)-len(digits) > 1 || strings.Count(digits, ".") > 1 || strings.Trim(digits, ".") == "" ||
+		strings.IndexFunc(digits, func(r rune) bool { return r != '.' && !unicode.IsDigit(r) }) >= 0 {
+		return nil, NewErrorValue(fmt.Sprintf("invalid decimal %s", text))
+	}
+
+	value, _ := new(big.Rat).SetString(text)
+
+	scale := 0
+	if dot := strings.IndexRune(digits, '.'); dot >= 0 {
+		scale = len(digits) - dot - 1
+	}
+	return NewDecimalValue(value, scale), nil
+}
+
+// NewIntegerFromString creates an integer value of any size from its
+// decimal textual representation
+//
+func NewIntegerFromString(text string) (Value, ErrorValue) {
+	value, ok := new(big.Int).SetString(strings.TrimSpace(text), 10)
+	if !ok {
+		return nil, NewErrorValue(fmt.Sprintf("invalid integer %s", text))
+	}
+	return NewIntegerFromBig(value), nil
+}
+
+// ToBigInt converts an integer value of any size into a go big integer
+//
+func ToBigInt(value Value) (*big.Int, ErrorValue) {
+	switch value.Type() {
+	case TypeInteger:
+		return big.NewInt(value.Internal().(int64)), nil
+	case TypeBigInteger:
+		return new(big.Int).Set(value.Internal().(*big.Int)), nil
+	default:
+		return nil, expectedType("an integer", value)
+	}
+}
+
+// ToRat converts an integer or decimal value into a go big rational
+//
+func ToRat(value Value) (*big.Rat, ErrorValue) {
+	switch value.Type() {
+	case TypeInteger, TypeBigInteger:
+		i, _ := ToBigInt(value)
+		return new(big.Rat).SetInt(i), nil
+	case TypeDecimal:
+		return new(big.Rat).Set(value.Internal().(*big.Rat)), nil
+	default:
+		return nil, expectedType("an integer or decimal", value)
+	}
+}
+
+// DecimalScale returns the number of fractional digits of a number, which
+// is 0 for integers
+//
+func DecimalScale(value Value) int {
+	if decimal, isDecimal := value.(*decimalValue); isDecimal {
+		return decimal.scale
+	}
+	return 0
+}
+
+func addInt64(a int64, b int64) (int64, bool) {
+	result := a + b
+	return result, (b >= 0) == (result >= a)
+}
+
+func subtractInt64(a int64, b int64) (int64, bool) {
+	result := a - b
+	return result, (b >= 0) == (result <= a)
+}
+
+func multiplyInt64(a int64, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	if (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+	result := a * b
+	return result, result/b == a
+}
+
+// calculate applies an arithmetic operation on two numbers of any kind. The
+// result gets the kind of the least precise number, integer results are
+// only big when they do not fit in 64 bits
+//
+func calculate(operation byte, v1 Value, v2 Value, invalid string) Value {
+
+	kind := kindOfNumber(v1)
+	other := kindOfNumber(v2)
+	if kind == notANumber || other == notANumber {
+		return NewErrorValue(invalid)
+	}
+	if other > kind {
+		kind = other
+	}
+
+	switch kind {
+	case inexactFloat:
+		f1, _ := ToFloat(v1)
+		f2, _ := ToFloat(v2)
+		float := NewFloatLiteral(f1).(MathValue)
+		switch operation {
+		case '+':
+			return float.Plus(NewFloatLiteral(f2))
+		case '-':
+			return float.Minus(NewFloatLiteral(f2))
+		case '*':
+			return float.Multiply(NewFloatLiteral(f2))
+		case '/':
+			return float.Divide(NewFloatLiteral(f2))
+		default:
+			return float.Modulo(NewFloatLiteral(f2))
+		}
+	case exactDecimal:
+		return calculateDecimal(operation, v1, v2)
+	}
+
+	i1, _ := ToBigInt(v1)
+	i2, _ := ToBigInt(v2)
+	result := new(big.Int)
+	switch operation {
+	case '+':
+		result.Add(i1, i2)
+	case '-':
+		result.Sub(i1, i2)
+	case '*':
+		result.Mul(i1, i2)
+	default:
+		if i2.Sign() == 0 {
+			return NewErrorValue("can not divide integer by 0")
+		}
+		if operation == '/' {
+			result.Quo(i1, i2)
+		} else {
+			result.Rem(i1, i2)
+		}
+	}
+	return NewIntegerFromBig(result)
+}
+
+// calculateDecimal applies an arithmetic operation on integers and decimals.
+// Sums keep the largest scale, products add scales and divisions use the
+// smallest scale that represents the quotient exactly
+//
+func calculateDecimal(operation byte, v1 Value, v2 Value) Value {
+
+	r1, _ := ToRat(v1)
+	r2, _ := ToRat(v2)
+
+	scale := DecimalScale(v1)
+	if DecimalScale(v2) > scale {
+		scale = DecimalScale(v2)
+	}
+
+	result := new(big.Rat)
+	switch operation {
+	case '+':
+		result.Add(r1, r2)
+	case '-':
+		result.Sub(r1, r2)
+	case '*':
+		result.Mul(r1, r2)
+		scale = DecimalScale(v1) + DecimalScale(v2)
+	default:
+		if r2.Sign() == 0 {
+			return NewErrorValue("can not divide decimal by 0")
+		}
+		result.Quo(r1, r2)
+		if operation == '/' {
+			scale = divisionScale(result, scale)
+		} else {
+			truncated := new(big.Int).Quo(result.Num(), result.Denom())
+			result.Sub(r1, new(big.Rat).Mul(new(big.Rat).SetInt(truncated), r2))
+		}
+	}
+	return NewDecimalValue(result, scale)
+}
+
+// divisionScale returns the smallest scale, starting at given minimum, that
+// represents a quotient exactly or maxDecimalScale when there is none
+//
+func divisionScale(quotient *big.Rat, minimum int) int {
+	shifted := new(big.Rat).Mul(quotient, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(minimum)), nil)))
+	ten := big.NewRat(10, 1)
+	for scale := minimum; ; scale++ {
+		if shifted.IsInt() || scale >= maxDecimalScale {
+			return scale
+		}
+		shifted.Mul(shifted, ten)
+	}
+}
+
+// compareNumbers compares integers and decimals of any size. Floats can only
+// be compared with floats since their precision differs
+//
+func compareNumbers(v1 Value, v2 Value, invalid string) (int, ErrorValue) {
+	k1 := kindOfNumber(v1)
+	k2 := kindOfNumber(v2)
+	if k1 == notANumber || k2 == notANumber || k1 == inexactFloat || k2 == inexactFloat {
+		return 0, NewErrorValue(invalid)
+	}
+
+	r1, _ := ToRat(v1)
+	r2, _ := ToRat(v2)
+	return r1.Cmp(r2), nil
+}
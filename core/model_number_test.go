@@ -0,0 +1,151 @@
+package elmo
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestIntegerOverflowPromotesToBigInteger(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`plus 9223372036854775807 1`, ExpectString(t, "9223372036854775808"))
+
+	ParseTestAndRunBlock(t,
+		`type (plus 9223372036854775807 1)`, ExpectValue(t, NewIdentifier("bigint")))
+
+	ParseTestAndRunBlock(t,
+		`minus -9223372036854775808 1`, ExpectString(t, "-9223372036854775809"))
+
+	ParseTestAndRunBlock(t,
+		`multiply 4294967296 4294967296`, ExpectString(t, "18446744073709551616"))
+
+	ParseTestAndRunBlock(t,
+		`divide -9223372036854775808 -1`, ExpectString(t, "9223372036854775808"))
+
+	ParseTestAndRunBlock(t,
+		`i: 9223372036854775807
+		 incr i
+		 $i`, ExpectString(t, "9223372036854775808"))
+
+	// big integers become regular integers again when they fit
+	//
+	ParseTestAndRunBlock(t,
+		`type (minus (plus 9223372036854775807 1) 1)`, ExpectValue(t, NewIdentifier("int")))
+
+	ParseTestAndRunBlock(t,
+		`minus (plus 9223372036854775807 1) 1`, ExpectValue(t, NewIntegerLiteral(9223372036854775807)))
+}
+
+func TestBigIntegers(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`123456789012345678901234567890`, ExpectString(t, "123456789012345678901234567890"))
+
+	ParseTestAndRunBlock(t,
+		`multiply 123456789012345678901234567890 10`, ExpectString(t, "1234567890123456789012345678900"))
+
+	ParseTestAndRunBlock(t,
+		`divide 123456789012345678901234567890 1000000000000000000000`, ExpectString(t, "123456789"))
+
+	ParseTestAndRunBlock(t,
+		`modulo 123456789012345678901234567890 1000`, ExpectValue(t, NewIntegerLiteral(890)))
+
+	ParseTestAndRunBlock(t,
+		`divide 123456789012345678901234567890 0`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`plus 123456789012345678901234567890 0.5`, ExpectValue(t, NewFloatLiteral(123456789012345678901234567890.5)))
+
+	ParseTestAndRunBlock(t,
+		`gt 123456789012345678901234567890 1`, ExpectValue(t, True))
+
+	ParseTestAndRunBlock(t,
+		`lt 1 123456789012345678901234567890`, ExpectValue(t, True))
+
+	ParseTestAndRunBlock(t,
+		`eq 123456789012345678901234567890 123456789012345678901234567890`, ExpectValue(t, True))
+
+	ParseTestAndRunBlock(t,
+		`gt 123456789012345678901234567890 1.0`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlock(t,
+		`expr "123456789012345678901234567890 + 10"`, ExpectString(t, "123456789012345678901234567900"))
+}
+
+func TestDecimals(t *testing.T) {
+
+	context := NewGlobalContext()
+	context.Set("a", mustDecimal(t, "0.1"))
+	context.Set("b", mustDecimal(t, "0.20"))
+	context.Set("c", mustDecimal(t, "3"))
+
+	ParseTestAndRunBlockWithinContext(t, context,
+		`plus $a $b`, ExpectString(t, "0.30"))
+
+	ParseTestAndRunBlockWithinContext(t, context,
+		`eq (plus $a $b) 0.3`, ExpectValue(t, False))
+
+	ParseTestAndRunBlockWithinContext(t, context,
+		`minus $a 1`, ExpectString(t, "-0.9"))
+
+	ParseTestAndRunBlockWithinContext(t, context,
+		`multiply $a $b`, ExpectString(t, "0.020"))
+
+	ParseTestAndRunBlockWithinContext(t, context,
+		`divide 1 (multiply $c 1.0)`, ExpectValue(t, NewFloatLiteral(1.0/3.0)))
+
+	ParseTestAndRunBlockWithinContext(t, context,
+		`divide $a 4`, ExpectString(t, "0.025"))
+
+	ParseTestAndRunBlockWithinContext(t, context,
+		`divide $b $c`, ExpectString(t, "0.06666666666666666666666666666667"))
+
+	ParseTestAndRunBlockWithinContext(t, context,
+		`divide $a 0`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlockWithinContext(t, context,
+		`modulo $c $b`, ExpectString(t, "0.00"))
+
+	ParseTestAndRunBlockWithinContext(t, context,
+		`type (plus $a 1)`, ExpectValue(t, NewIdentifier("decimal")))
+
+	ParseTestAndRunBlockWithinContext(t, context,
+		`type (plus $a 1.0)`, ExpectValue(t, NewIdentifier("float")))
+
+	ParseTestAndRunBlockWithinContext(t, context,
+		`eq $c 3`, ExpectValue(t, True))
+
+	ParseTestAndRunBlockWithinContext(t, context,
+		`lt $a $b`, ExpectValue(t, True))
+
+	ParseTestAndRunBlockWithinContext(t, context,
+		`lt $a 0.2`, ExpectErrorValueAt(t, 1))
+
+	if _, err := NewDecimalFromString("1/3"); err == nil {
+		t.Error("expected 1/3 not to be a valid decimal")
+	}
+
+	if _, err := NewDecimalFromString("."); err == nil {
+		t.Error("expected . not to be a valid decimal")
+	}
+}
+
+func mustDecimal(t *testing.T, text string) Value {
+	value, err := NewDecimalFromString(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
+
+func TestSerializeAndReconstructNumbers(t *testing.T) {
+
+	big, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+	for _, value := range []Value{NewIntegerFromBig(big), mustDecimal(t, "-12.50")} {
+		reconstructed := value.(SerializableValue).ToBinary().ToRegular()
+		if reconstructed.Type() != value.Type() || reconstructed.String() != value.String() {
+			t.Errorf("expected %v to be reconstructed, found %v", value, reconstructed)
+		}
+	}
+}
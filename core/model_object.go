@@ -0,0 +1,119 @@
+package elmo
+
+// boundMethod records which object defines a method that is called on this
+//
+type boundMethod struct {
+	function Value
+	owner    *dictValue
+}
+
+// receiverOf returns the dictionary a method is called on when it is
+// found in given dictionary. This is the dictionary itself, except for
+// super references which call methods on the object that uses super
+//
+func receiverOf(dict DictionaryValue) DictionaryValue {
+	if dictValue, ok := dict.(*dictValue); ok && dictValue.receiver != nil {
+		return dictValue.receiver
+	}
+	return dict
+}
+
+// definingObject returns the dictionary in the chain of prototypes of given
+// dictionary that holds given key
+//
+func definingObject(dict DictionaryValue, key string) *dictValue {
+	object, ok := dict.(*dictValue)
+	if !ok {
+		return nil
+	}
+
+	hash := stringHashKey(key)
+	for owner := object; owner != nil; owner = owner.parent {
+		owner.lock.RLock()
+		_, found := owner.values[hash]
+		owner.lock.RUnlock()
+		if found {
+			return owner
+		}
+	}
+	return nil
+}
+
+// methodKey returns the key under which a method is found when it's
+// called using given (namespaced) identifier
+//
+func methodKey(function interface{}) string {
+	if id, isIdentifier := function.(*identifier); isIdentifier {
+		return id.value[len(id.value)-1]
+	}
+	return ""
+}
+
+// bindMethod makes the receiver of given dictionary this for a call to a
+// method found in the dictionary under given key. The object defining the
+// method is recorded so the method can find its super. Returns a function
+// that restores this and the method bound before
+//
+func bindMethod(context RunContext, inDict DictionaryValue, key string, method Value) func() {
+	this, bound := context.This(), context.boundMethod()
+
+	context.SetThis(receiverOf(inDict))
+	context.setBoundMethod(&boundMethod{function: method, owner: definingObject(inDict, key)})
+
+	return func() {
+		context.SetThis(this)
+		context.setBoundMethod(bound)
+	}
+}
+
+// superOf creates a reference to the prototype of the object that defines
+// given method when the method is called on this. Looking up keys through
+// the reference skips the defining object and methods called through the
+// reference are called on this. Returns nil when the method is not called
+// as a method or its defining object has no prototype
+//
+func superOf(context RunContext, this DictionaryValue, method Value) DictionaryValue {
+	object, ok := this.(*dictValue)
+	bound := context.boundMethod()
+	if !ok || bound == nil || bound.function != method || bound.owner == nil || bound.owner.parent == nil {
+		return nil
+	}
+
+	return &dictValue{baseValue: baseValue{info: typeInfoDictionary},
+		parent: bound.owner.parent, values: map[string]Value{}, frozen: true, receiver: object}
+}
+
+// IsA checks if a value is given prototype or an object that
+// has given prototype somewhere in its chain of prototypes
+//
+func IsA(value Value, prototype Value) bool {
+	object, ok := value.(*dictValue)
+	if !ok {
+		return false
+	}
+
+	for ; object != nil; object = object.parent {
+		if object == prototype {
+			return true
+		}
+	}
+	return false
+}
+
+// NewObject creates a new object with given prototype and the keys
+// and values of given dictionary, which can be nil
+//
+func NewObject(prototype DictionaryValue, values DictionaryValue) (DictionaryValue, ErrorValue) {
+	object := NewDictionaryValue(prototype, make(map[string]Value))
+	if values == nil {
+		return object, nil
+	}
+
+	for _, key := range values.KeyValues() {
+		value, _ := values.ResolveKey(key)
+		if _, err := object.Set(key, value); err != nil {
+			return nil, err
+		}
+	}
+	return object, nil
+}
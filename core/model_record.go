@@ -0,0 +1,212 @@
+package elmo
+
+import (
+	"fmt"
+	"sync"
+)
+
+// recordType is a type declared by a script using deftype. Records are
+// dictionaries with a fixed set of fields, values are checked against
+// the type of their field whenever they are stored in a record
+//
+type recordType struct {
+	info      TypeInfo
+	fields    []*parameter
+	invariant Runnable
+
+	// context in which the type is declared, used to check invariants
+	context RunContext
+}
+
+// recordTypes holds the record types declared within an execution by name,
+// so records can be reconstructed from their binary representation
+//
+type recordTypes struct {
+	lock   sync.RWMutex
+	byName map[string]*recordType
+}
+
+func newRecordTypes() *recordTypes {
+	return &recordTypes{byName: map[string]*recordType{}}
+}
+
+func registerRecordType(context RunContext, recordType *recordType) {
+	types := executionOf(context).records
+
+	types.lock.Lock()
+	defer types.lock.Unlock()
+
+	types.byName[recordType.name()] = recordType
+}
+
+// lookupRecordType finds a record type declared within the execution
+// given context takes part in
+//
+func lookupRecordType(context RunContext, name string) (*recordType, bool) {
+	if context == nil {
+		return nil, false
+	}
+
+	types := executionOf(context).records
+
+	types.lock.RLock()
+	defer types.lock.RUnlock()
+
+	recordType, found := types.byName[name]
+	return recordType, found
+}
+
+func (recordType *recordType) name() string {
+	return recordType.info.Name().String()
+}
+
+func (recordType *recordType) field(name string) (*parameter, bool) {
+	for _, field := range recordType.fields {
+		if field.name == name {
+			return field, true
+		}
+	}
+	return nil, false
+}
+
+// checkField checks if a value can be stored under given key
+//
+func (recordType *recordType) checkField(key Value, value Value) ErrorValue {
+	if key.Type() != TypeString && key.Type() != TypeIdentifier {
+		return NewErrorValue(fmt.Sprintf("%v is not a field of %s", key, recordType.name()))
+	}
+
+	field, found := recordType.field(key.String())
+	if !found {
+		return NewErrorValue(fmt.Sprintf("%v is not a field of %s", key, recordType.name()))
+	}
+
+	if !field.accepts(value) {
+		if value.Type() == TypeError {
+			return value.(ErrorValue)
+		}
+		return NewErrorValue(fmt.Sprintf("invalid value for field %s of %s: expected %s, not %s", field.name, recordType.name(), field.typeName, value.Info().Name()))
+	}
+	return nil
+}
+
+// checkComplete checks if a record has all fields that are not optional
+//
+func (recordType *recordType) checkComplete(record DictionaryValue) ErrorValue {
+	for _, field := range recordType.fields {
+		if field.optional {
+			continue
+		}
+		if _, found := record.Resolve(field.name); !found {
+			return NewErrorValue(fmt.Sprintf("missing field %s of %s", field.name, recordType.name()))
+		}
+	}
+	return nil
+}
+
+// checkInvariant runs the invariant of the type, if any, with given record as this
+//
+func (recordType *recordType) checkInvariant(record DictionaryValue) ErrorValue {
+	if recordType.invariant == nil {
+		return nil
+	}
+
+	subContext := recordType.context.CreateSubContext()
+	subContext.SetThis(record)
+
+	result := recordType.invariant.Run(subContext, NoArguments)
+	switch {
+	case result.Type() == TypeError:
+		return result.(ErrorValue)
+	case result.Type() != TypeBoolean:
+		return NewErrorValue(fmt.Sprintf("invariant of %s did not return a boolean", recordType.name()))
+	case result != True:
+		return NewErrorValue(fmt.Sprintf("invariant of %s does not hold for %v", recordType.name(), record))
+	}
+	return nil
+}
+
+// setField stores a value in a record after checking its field and,
+// when stored, the invariant of the record. A value that breaks the
+// invariant is not stored
+//
+func (dictValue *dictValue) setField(symbol Value, value Value) (Value, ErrorValue) {
+	if err := dictValue.record.checkField(symbol, value); err != nil {
+		return dictValue, err
+	}
+
+	key, _ := HashKey(symbol)
+
+	dictValue.lock.Lock()
+	if dictValue.frozen {
+		dictValue.lock.Unlock()
+		return dictValue, NewErrorValue("can not set value in frozen dictionary")
+	}
+	previous, existed := dictValue.values[key]
+	dictValue.put(key, symbol, value)
+	dictValue.lock.Unlock()
+
+	if err := dictValue.record.checkInvariant(dictValue); err != nil {
+		dictValue.lock.Lock()
+		if existed {
+			dictValue.values[key] = previous
+		} else {
+			dictValue.removeKey(key)
+		}
+		dictValue.lock.Unlock()
+		return dictValue, err
+	}
+
+	return dictValue, nil
+}
+
+// newRecord creates an empty record of given type
+//
+func newRecord(recordType *recordType) *dictValue {
+	return &dictValue{baseValue: baseValue{info: recordType.info},
+		values: map[string]Value{}, record: recordType}
+}
+
+// construct creates a record out of arguments that are bound to the
+// fields of the record type like arguments of a function
+//
+func (recordType *recordType) construct(context RunContext, arguments []Argument) Value {
+	fields := newOrderedRunContext(context)
+
+	if err := bindArguments(recordType.name(), recordType.fields, context, fields, arguments); err != nil {
+		return err
+	}
+
+	record := newRecord(recordType)
+	keys, mapping := fields.orderedMapping()
+	for _, key := range keys {
+		record.put(stringHashKey(key), NewStringLiteral(key), mapping[key])
+	}
+
+	if err := recordType.checkInvariant(record); err != nil {
+		return err
+	}
+	return record
+}
+
+// restore stores a deserialized value. Fields of records are checked, the
+// record itself is checked when all its fields are restored
+//
+func (dictValue *dictValue) restore(symbol Value, value Value) ErrorValue {
+	if dictValue.record != nil {
+		if err := dictValue.record.checkField(symbol, value); err != nil {
+			return err
+		}
+	}
+
+	key, err := HashKey(symbol)
+	if err != nil {
+		return err
+	}
+
+	dictValue.lock.Lock()
+	defer dictValue.lock.Unlock()
+
+	dictValue.put(key, symbol, value)
+	return nil
+}
@@ -0,0 +1,252 @@
+package elmo
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+type setValue struct {
+	baseValue
+	frozen bool
+
+	// values are stored by their hash key, see HashKey
+	values map[string]Value
+
+	// keys holds the hash keys of values in insertion order
+	keys []string
+
+	// lock guards values, keys and frozen so sets
+	// can be shared between actors
+	lock sync.RWMutex
+}
+
+func (setValue *setValue) String() string {
+	var builder strings.Builder
+	builder.WriteString("#{")
+	for i, value := range setValue.List() {
+		if i > 0 {
+			builder.WriteString(" ")
+		}
+		builder.WriteString(value.String())
+	}
+	builder.WriteString("}")
+	return builder.String()
+}
+
+func (setValue *setValue) Type() Type {
+	return TypeSet
+}
+
+// Internal returns the values of the set in insertion order
+//
+func (setValue *setValue) Internal() interface{} {
+	return setValue.List()
+}
+
+// List returns the values of the set in insertion order
+//
+func (setValue *setValue) List() []Value {
+	setValue.lock.RLock()
+	defer setValue.lock.RUnlock()
+
+	values := make([]Value, len(setValue.keys))
+	for i, key := range setValue.keys {
+		values[i] = setValue.values[key]
+	}
+	return values
+}
+
+func (setValue *setValue) hashKeys() []string {
+	setValue.lock.RLock()
+	defer setValue.lock.RUnlock()
+
+	return append(make([]string, 0, len(setValue.keys)), setValue.keys...)
+}
+
+// Iterate iterates over the values the set has when iteration starts
+//
+func (setValue *setValue) Iterate() Iterator {
+	return NewSliceIterator(setValue.List())
+}
+
+func (setValue *setValue) Length() Value {
+	setValue.lock.RLock()
+	defer setValue.lock.RUnlock()
+
+	return NewIntegerLiteral(int64(len(setValue.keys)))
+}
+
+func (setValue *setValue) Contains(value Value) bool {
+	key, err := HashKey(value)
+	if err != nil {
+		return false
+	}
+
+	setValue.lock.RLock()
+	defer setValue.lock.RUnlock()
+
+	_, found := setValue.values[key]
+	return found
+}
+
+func (setValue *setValue) put(key string, value Value) {
+	if _, found := setValue.values[key]; !found {
+		setValue.keys = append(setValue.keys, key)
+		setValue.values[key] = value
+	}
+}
+
+func (setValue *setValue) Add(value Value) (Value, ErrorValue) {
+	key, err := HashKey(value)
+	if err != nil {
+		return setValue, err
+	}
+
+	setValue.lock.Lock()
+	defer setValue.lock.Unlock()
+
+	if setValue.frozen {
+		return setValue, NewErrorValue("can not add value to frozen set")
+	}
+	setValue.put(key, value)
+
+	return setValue, nil
+}
+
+func (setValue *setValue) Remove(value Value) (Value, ErrorValue) {
+	key, err := HashKey(value)
+	if err != nil {
+		return setValue, err
+	}
+
+	setValue.lock.Lock()
+	defer setValue.lock.Unlock()
+
+	if setValue.frozen {
+		return setValue, NewErrorValue("can not remove value from frozen set")
+	}
+
+	if _, found := setValue.values[key]; found {
+		delete(setValue.values, key)
+		for i, k := range setValue.keys {
+			if k == key {
+				setValue.keys = append(setValue.keys[:i:i], setValue.keys[i+1:]...)
+				break
+			}
+		}
+	}
+
+	return setValue, nil
+}
+
+func (setValue *setValue) snapshot() ([]string, map[string]Value) {
+	setValue.lock.RLock()
+	defer setValue.lock.RUnlock()
+
+	values := make(map[string]Value, len(setValue.values))
+	for key, value := range setValue.values {
+		values[key] = value
+	}
+	return append(make([]string, 0, len(setValue.keys)), setValue.keys...), values
+}
+
+// combine creates a new set with the values of this set and given set
+// that are accepted by given filter, values of this set come first
+//
+func (setValue *setValue) combine(with SetValue, accept func(inThis bool, inWith bool) bool) SetValue {
+	keys1, values1 := setValue.snapshot()
+	keys2, values2 := with.snapshot()
+
+	combined := newSetValue()
+	for _, key := range keys1 {
+		if _, inWith := values2[key]; accept(true, inWith) {
+			combined.put(key, values1[key])
+		}
+	}
+	for _, key := range keys2 {
+		if _, inThis := values1[key]; accept(inThis, true) {
+			combined.put(key, values2[key])
+		}
+	}
+	return combined
+}
+
+// Union creates a new set with all values of this set and given set
+//
+func (setValue *setValue) Union(with SetValue) SetValue {
+	return setValue.combine(with, func(inThis bool, inWith bool) bool { return true })
+}
+
+// Intersection creates a new set with the values both in this set and given set
+//
+func (setValue *setValue) Intersection(with SetValue) SetValue {
+	return setValue.combine(with, func(inThis bool, inWith bool) bool { return inThis && inWith })
+}
+
+// Difference creates a new set with the values of this set that are not in given set
+//
+func (setValue *setValue) Difference(with SetValue) SetValue {
+	return setValue.combine(with, func(inThis bool, inWith bool) bool { return inThis && !inWith })
+}
+
+func (setValue *setValue) Freeze() Value {
+	setValue.lock.Lock()
+	setValue.frozen = true
+	setValue.lock.Unlock()
+
+	return setValue
+}
+
+func (setValue *setValue) Frozen() bool {
+	setValue.lock.RLock()
+	defer setValue.lock.RUnlock()
+
+	return setValue.frozen
+}
+
+// Compare returns 0 for sets with the same values. Other sets are ordered by
+// size and then by the hash keys of their values
+//
+func (setValue *setValue) Compare(context RunContext, value Value) (int, ErrorValue) {
+	other, isSet := value.(SetValue)
+	if !isSet {
+		return 0, NewErrorValue("can not compare set with non set")
+	}
+
+	keys1 := setValue.hashKeys()
+	keys2 := other.hashKeys()
+	if len(keys1) != len(keys2) {
+		if len(keys1) < len(keys2) {
+			return -1, nil
+		}
+		return 1, nil
+	}
+
+	sort.Strings(keys1)
+	sort.Strings(keys2)
+	return strings.Compare(strings.Join(keys1, "\x00"), strings.Join(keys2, "\x00")), nil
+}
+
+func (setValue *setValue) ToBinary() BinaryValue {
+	return NewBinaryValueFromInternal(typeInfoSet.ID(), "", Serialize(NewListValue(setValue.List())))
+}
+
+func newSetValue() *setValue {
+	return &setValue{baseValue: baseValue{info: typeInfoSet}, values: make(map[string]Value)}
+}
+
+// NewSetValue creates a new set holding given values, duplicates are
+// ignored. All values must be hashable, see HashKey
+//
+func NewSetValue(values []Value) (SetValue, ErrorValue) {
+	set := newSetValue()
+	for _, value := range values {
+		key, err := HashKey(value)
+		if err != nil {
+			return nil, err
+		}
+		set.put(key, value)
+	}
+	return set, nil
+}
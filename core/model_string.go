@@ -1,6 +1,9 @@
 package elmo
 
 import (
+	"fmt"
+	"math/big"
+	"strconv"
 	"strings"
 )
 
@@ -18,13 +21,16 @@ type stringLiteral struct {
 type blockAtPositionInString struct {
 	at    int
 	block Block
+
+	// printf-style format of the block's result, like %.2f
+	format string
 }
 
 func (stringLiteral *stringLiteral) String() string {
 	if stringLiteral.blocks == nil {
 		return string(stringLiteral.value)
 	}
-	return stringLiteral.resolveBlocksToString(nil, func(Block) string { return "\\{...}" })
+	return stringLiteral.resolveBlocksToString(nil, func(Block, string) string { return "\\{...}" })
 }
 
 func (stringLiteral *stringLiteral) Type() Type {
@@ -107,7 +113,7 @@ func (stringLiteral *stringLiteral) Compare(context RunContext, value Value) (in
 	return strings.Compare(stringLiteral.String(), value.String()), nil
 }
 
-func (stringLiteral *stringLiteral) resolveBlocksToString(context RunContext, withBlock func(Block) string) string {
+func (stringLiteral *stringLiteral) resolveBlocksToString(context RunContext, withBlock func(Block, string) string) string {
 
 	var sb strings.Builder
 	value := stringLiteral.value
@@ -119,9 +125,9 @@ func (stringLiteral *stringLiteral) resolveBlocksToString(context RunContext, wi
 		}
 
 		if stringLiteral.capturedContext != nil {
-			sb.WriteString(withBlock(blockPosition.block.CopyWithinContext(stringLiteral.capturedContext)))
+			sb.WriteString(withBlock(blockPosition.block.CopyWithinContext(stringLiteral.capturedContext), blockPosition.format))
 		} else {
-			sb.WriteString(withBlock(blockPosition.block))
+			sb.WriteString(withBlock(blockPosition.block, blockPosition.format))
 		}
 
 		at = blockPosition.at
@@ -140,9 +146,12 @@ func (stringLiteral *stringLiteral) ResolveBlocks(context RunContext) Value {
 		return stringLiteral
 	}
 
-	return NewStringLiteral(stringLiteral.resolveBlocksToString(context, func(block Block) string {
+	return NewStringLiteral(stringLiteral.resolveBlocksToString(context, func(block Block, format string) string {
 		if insertValue := block.Run(context, []Argument{}); insertValue != nil && insertValue != Nothing {
-			return insertValue.String()
+			if format != "" && insertValue.Type() != TypeError {
+				return FormatValues(format, []Value{insertValue})
+			}
+			return StringOf(context, insertValue)
 		}
 
 		return ""
@@ -179,3 +188,102 @@ func NewStringLiteralFromRunes(value []rune) Value {
 func newStringLiteralWithBlocks(value string, blocks []*blockAtPositionInString) Value {
 	return &stringLiteral{baseValue: baseValue{info: typeInfoString}, value: []rune(value), blocks: blocks}
 }
+
+// formattable lets values be formatted by the fmt package. Verbs for strings
+// use the textual representation of a value, all other verbs use its go value
+//
+type formattable struct {
+	value Value
+}
+
+func (formattable formattable) Format(state fmt.State, verb rune) {
+	format := fmt.FormatString(state, verb)
+
+	switch verb {
+	case 's', 'q', 'v':
+		fmt.Fprintf(state, format, formattable.value.String())
+		return
+	}
+
+	switch formattable.value.Type() {
+	case TypeInteger, TypeFloat, TypeBoolean, TypeBigInteger:
+		fmt.Fprintf(state, format, formattable.value.Internal())
+	case TypeDecimal:
+		fmt.Fprintf(state, format, new(big.Float).SetPrec(256).SetRat(formattable.value.Internal().(*big.Rat)))
+	default:
+		fmt.Fprintf(state, format, formattable.value.String())
+	}
+}
+
+// CheckFormat checks if a printf-style format uses exactly the given number
+// of values, so formatting does not result in %!d(MISSING) or %!(EXTRA ...)
+//
+func CheckFormat(format string, valueCount int) ErrorValue {
+	next, used, reordered := 0, 0, false
+
+	// argumentIndex handles explicit argument indexes like in %[2]d
+	//
+	argumentIndex := func(i int) int {
+		if i >= len(format) || format[i] != '[' {
+			return i
+		}
+		end := strings.IndexByte(format[i:], ']')
+		if end < 0 {
+			return i
+		}
+		index, err := strconv.Atoi(format[i+1 : i+end])
+		if err != nil || index < 1 {
+			return i
+		}
+		next, reordered = index-1, true
+		return i + end + 1
+	}
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		for i < len(format) && strings.IndexByte("+-# 0", format[i]) >= 0 {
+			i++
+		}
+		for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+			i++
+		}
+		if i < len(format) && format[i] == '.' {
+			i++
+			for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+				i++
+			}
+		}
+		i = argumentIndex(i)
+		if i >= len(format) {
+			return NewErrorValue(fmt.Sprintf("invalid format \"%s\", missing verb at the end", format))
+		}
+		if format[i] == '*' || format[i] == '[' {
+			return NewErrorValue(fmt.Sprintf("invalid format \"%s\", widths, precisions and argument indexes must be numbers", format))
+		}
+		if format[i] != '%' {
+			next++
+			if next > used {
+				used = next
+			}
+		}
+	}
+
+	if used > valueCount || (used < valueCount && !reordered) {
+		return NewErrorValue(fmt.Sprintf("format \"%s\" expects %d values, not %d", format, used, valueCount))
+	}
+	return nil
+}
+
+// FormatValues formats values according to a printf-style format, like
+// "%5.2f" or "%-10s|%x"
+//
+func FormatValues(format string, values []Value) string {
+	arguments := make([]interface{}, len(values))
+	for i, value := range values {
+		arguments[i] = formattable{value: value}
+	}
+	return fmt.Sprintf(format, arguments...)
+}
@@ -69,6 +69,41 @@ func TestLongLiteralStringsWithBlock(t *testing.T) {
 		"pepper:`chipotle`; s:``{pepper}`", ExpectValue(t, NewStringLiteral("chipotle")))
 }
 
+func TestLiteralStringsWithFormattedBlock(t *testing.T) {
+	ParseTestAndRunBlock(t,
+		`price: 3.14159; "price: \{$price:%.2f}"`, ExpectValue(t, NewStringLiteral("price: 3.14")))
+	ParseTestAndRunBlock(t,
+		`"|\{"soup":%-6s}|\{42:%05d}|\{255:%x}|"`, ExpectValue(t, NewStringLiteral("|soup  |00042|ff|")))
+	ParseTestAndRunBlock(t,
+		`"\{a: 3; plus $a 4:%3d}"`, ExpectValue(t, NewStringLiteral("  7")))
+	ParseTestAndRunBlock(t,
+		`a:"b"; "\{$a}:\{$a}"`, ExpectValue(t, NewStringLiteral("b:b")))
+	ParseTestAndRunBlock(t,
+		"price: 1.5; `price: `{$price:%.3f}`", ExpectValue(t, NewStringLiteral("price: 1.500")))
+	ParseTestAndRunBlock(t,
+		`a:3; "jalapeño_\{a}_ñ\{a}"`, ExpectValue(t, NewStringLiteral("jalapeño_3_ñ3")))
+}
+
+func TestFormatValues(t *testing.T) {
+	decimal, _ := NewDecimalFromString("2.50")
+	if s := FormatValues("%.3f|%s|%v", []Value{decimal, decimal, NewListValue([]Value{True})}); s != "2.500|2.50|[true]" {
+		t.Error("expected 2.500|2.50|[true], found", s)
+	}
+}
+
+func TestCheckFormat(t *testing.T) {
+	for format, count := range map[string]int{"": 0, "100%%": 0, "%s=%d": 2, "%-8.3f": 1, "%[2]s %[1]s": 2, "%[1]s %[1]s": 1} {
+		if err := CheckFormat(format, count); err != nil {
+			t.Errorf("expected %s to use %d values, found %v", format, count, err)
+		}
+	}
+	for format, count := range map[string]int{"%s=%d": 1, "%s": 2, "%[3]s": 2, "%": 0, "%5": 1, "%*d": 2, "%[x]d": 1} {
+		if err := CheckFormat(format, count); err == nil {
+			t.Errorf("expected %s not to use %d values", format, count)
+		}
+	}
+}
+
 func TestFunctionCallWithBlock(t *testing.T) {
 	ParseTestAndRunBlock(t,
 		`f: (func arg {return (type $arg)})
@@ -362,3 +397,50 @@ func TestBooleanToBinary(t *testing.T) {
 		t.Errorf("expected true, not \"%s\"", value.String())
 	}
 }
+
+func TestErrorTrace(t *testing.T) {
+
+	ParseTestAndRunBlock(t,
+		`f: (func {
+		   error "chipotle"
+		 })
+		 g: (func {
+		   f
+		 })
+		 result: (g)
+		 len $result.trace`, ExpectValue(t, NewIntegerLiteral(3)))
+
+	// calls to builtins and stored errors do not add frames
+	//
+	ParseTestAndRunBlock(t,
+		`f: (func {
+		   error "chipotle"
+		 })
+		 result: (f)
+		 result
+		 result
+		 len $result.trace`, ExpectValue(t, NewIntegerLiteral(2)))
+
+	ParseTestAndRunBlock(t,
+		`f: (func {
+		   error "chipotle"
+		 })
+		 g: (func {
+		   f
+		 })
+		 try { g } catch e {
+		   trace: $e.trace
+		   [((trace 0) function) ((trace 1) function) ((trace 2) function)]
+		 }`,
+		ExpectValue(t, NewListValue([]Value{NewStringLiteral("error"), NewStringLiteral("f"), NewStringLiteral("g")})))
+
+	ParseTestAndRunBlock(t,
+		`f: (func {
+		   error "chipotle"
+		 })
+		 try { f } catch e { ($e.trace 1) line }`, ExpectValue(t, NewIntegerLiteral(4)))
+
+	ParseTestAndRunBlock(t,
+		`result: (error "chipotle")
+		 $result.message`, ExpectValue(t, NewStringLiteral("chipotle")))
+}
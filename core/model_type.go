@@ -1,5 +1,7 @@
 package elmo
 
+import "sync/atomic"
+
 // Type represents an internal value type
 //
 type Type uint8
@@ -35,6 +37,12 @@ const (
 	TypeNil
 	// TypeBinary represents the value of a byte array
 	TypeBinary
+	// TypeBigInteger represents a type for an integer value that does not fit in 64 bits
+	TypeBigInteger
+	// TypeDecimal represents a type for an arbitrary precision decimal value
+	TypeDecimal
+	// TypeSet represents a type for a set of unique values
+	TypeSet
 )
 
 var typeInfoIdentifier = NewTypeInfo("identifier")
@@ -51,6 +59,11 @@ var typeInfoGoFunction = NewTypeInfo("func")
 var typeInfoReturn = NewTypeInfo("return")
 var typeInfoNil = NewTypeInfo("nil")
 var typeInfoBinary = NewTypeInfo("binary")
+var typeInfoIterable = NewTypeInfo("iterable")
+var typeInfoLoopSignal = NewTypeInfo("signal")
+var typeInfoBigInteger = NewTypeInfo("bigint")
+var typeInfoDecimal = NewTypeInfo("decimal")
+var typeInfoSet = NewTypeInfo("set")
 
 // TypeInfo represents kinf of subType for TypeInternal values
 //
@@ -72,13 +85,13 @@ func (typeInfo *typeInfo) ID() int64 {
 	return typeInfo.id
 }
 
+// typeCounter is incremented atomically as actors can declare types concurrently
 var typeCounter int64
 
 // NewTypeInfo constructs a new type object
 //
 func NewTypeInfo(name string) TypeInfo {
-	typeCounter = typeCounter + 1
-	return &typeInfo{id: typeCounter, name: name}
+	return &typeInfo{id: atomic.AddInt64(&typeCounter, 1), name: name}
 }
 
 func TypeMap(types ...Type) map[Type]bool {
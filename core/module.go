@@ -1,8 +1,11 @@
 package elmo
 
+import "sync"
+
 type module struct {
 	name   string
 	loaded Value
+	lock   sync.Mutex
 
 	initializer ModuleInitializer
 }
@@ -23,6 +26,9 @@ func (module *module) Name() string {
 }
 
 func (module *module) Content(context RunContext) Value {
+	module.lock.Lock()
+	defer module.lock.Unlock()
+
 	if module.loaded == nil {
 		module.loaded = module.initializer(context)
 	}
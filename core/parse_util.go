@@ -35,7 +35,8 @@ func nodeChildren(node *node32) []*node32 {
 			child.pegRule != ruleLPAR &&
 			child.pegRule != ruleRPAR &&
 			child.pegRule != ruleDOLLAR &&
-			child.pegRule != ruleCOMMA)
+			child.pegRule != ruleCOMMA &&
+			child.pegRule != ruleFormatSpec)
 	})
 }
 
@@ -132,9 +133,9 @@ func nodeText(node *node32, buf []rune) string {
 
 		for cursor != nil {
 			if cursor.pegRule == ruleSpacing {
+				end = cursor.begin
 				break
 			}
-			end = cursor.end
 			cursor = cursor.next
 		}
 	}
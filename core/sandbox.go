@@ -0,0 +1,209 @@
+package elmo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Policy declares what code running within a sandbox context is permitted to do
+//
+type Policy struct {
+	// Builtins are the names of the global functions that can be used.
+	// When nil, all global functions can be used
+	Builtins []string
+
+	// Modules are the names of the modules that can be loaded.
+	// When nil, all registered modules can be loaded
+	Modules []string
+
+	// FileRoot is the directory all file access is jailed to. Paths used
+	// by scripts are relative to this root. When empty, scripts can not
+	// access files or load other scripts
+	FileRoot string
+
+	// Plugins denotes whether go plugins can be loaded (and build)
+	Plugins bool
+
+	// Commands are the names of executables that can be run by sys
+	Commands []string
+
+	// Hosts are the hosts (optionally including port) http clients can connect to
+	Hosts []string
+
+	// Environment are the names of the environment variables scripts can
+	// read and set
+	Environment []string
+
+	// Settings denotes whether scripts can access the global settings
+	Settings bool
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsBuiltin checks if a global function can be used
+//
+func (policy *Policy) AllowsBuiltin(name string) bool {
+	return policy.Builtins == nil || contains(policy.Builtins, name)
+}
+
+// AllowsModule checks if a module can be loaded
+//
+func (policy *Policy) AllowsModule(name string) bool {
+	return policy.Modules == nil || contains(policy.Modules, name)
+}
+
+// AllowsCommand checks if an executable can be run
+//
+func (policy *Policy) AllowsCommand(name string) bool {
+	return contains(policy.Commands, name)
+}
+
+// AllowsEnvironment checks if an environment variable can be read or set
+//
+func (policy *Policy) AllowsEnvironment(name string) bool {
+	return contains(policy.Environment, name)
+}
+
+// AllowsHost checks if a connection to a host can be made. Host can
+// contain a port, in which case both host with and without port are checked
+//
+func (policy *Policy) AllowsHost(host string) bool {
+	if contains(policy.Hosts, host) {
+		return true
+	}
+	if at := strings.LastIndex(host, ":"); at >= 0 && !strings.HasSuffix(host, "]") {
+		return contains(policy.Hosts, host[:at])
+	}
+	return false
+}
+
+// jail converts a path used by a script into a path within the file root
+//
+func (policy *Policy) jail(path string) (string, ErrorValue) {
+	if policy.FileRoot == "" {
+		return "", NewNotPermittedErrorValue("file access")
+	}
+
+	root, err := filepath.Abs(policy.FileRoot)
+	if err != nil {
+		return "", NewErrorValue(err.Error())
+	}
+
+	// cleaning a path that starts at the root makes it impossible
+	// to escape the root by using ..
+	//
+	jailed := filepath.Join(root, filepath.Clean(string(filepath.Separator)+path))
+
+	// do not follow symbolic links out of the root, files that do not
+	// exist yet are checked by their nearest existing parent
+	//
+	existing := jailed
+	for !fileOrDirExists(existing) && existing != root {
+		existing = filepath.Dir(existing)
+	}
+	if !policy.within(root, existing) {
+		return "", NewNotPermittedErrorValue(fmt.Sprintf("access to %s", path))
+	}
+
+	return jailed, nil
+}
+
+func fileOrDirExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func (policy *Policy) within(root string, path string) bool {
+	if resolvedRoot, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolvedRoot
+	}
+	if resolvedPath, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolvedPath
+	}
+	return path == root || strings.HasPrefix(path, root+string(filepath.Separator))
+}
+
+// virtual converts a path within the file root into the path as seen by scripts
+//
+func (policy *Policy) virtual(path string) string {
+	root, err := filepath.Abs(policy.FileRoot)
+	if err != nil {
+		return path
+	}
+	relative, err := filepath.Rel(root, path)
+	if err != nil {
+		return path
+	}
+	return filepath.Join(string(filepath.Separator), relative)
+}
+
+// NewNotPermittedErrorValue creates an error for an operation that is
+// denied by the policy of a sandbox
+//
+func NewNotPermittedErrorValue(what string) ErrorValue {
+	return NewErrorValue(fmt.Sprintf("%s is not permitted", what))
+}
+
+func notPermitted(name string) NamedValue {
+	return NewGoFunctionWithHelp(name, fmt.Sprintf("%s is not permitted in this sandbox", name),
+		func(context RunContext, arguments []Argument) Value {
+			return NewNotPermittedErrorValue(name)
+		})
+}
+
+// NewSandboxContext constructs a global context that only permits what is
+// declared by given policy. All contexts derived from it, including the ones
+// of loaded scripts and actors, share this policy
+//
+func NewSandboxContext(policy Policy) RunContext {
+	context := NewGlobalContext()
+	context.(*runContext).execution.policy = &policy
+
+	for _, key := range context.Keys() {
+		value, _ := context.Get(key)
+		if value.Type() == TypeGoFunction && !policy.AllowsBuiltin(key) {
+			context.SetNamed(notPermitted(key))
+		}
+	}
+
+	return context
+}
+
+// fileSystemPath converts a path used by a script to the path on the
+// file system and the absolute path as seen by the script
+//
+func fileSystemPath(context RunContext, path string) (string, string, ErrorValue) {
+	policy := context.Policy()
+	if policy == nil {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return "", "", NewErrorValue(err.Error())
+		}
+		return path, absPath, nil
+	}
+
+	jailed, err := policy.jail(path)
+	if err != nil {
+		return "", "", err
+	}
+	return jailed, policy.virtual(jailed), nil
+}
+
+// tempDir returns the directory temporary files are created in
+//
+func tempDir(context RunContext) (string, ErrorValue) {
+	policy := context.Policy()
+	if policy == nil {
+		return os.TempDir(), nil
+	}
+	return policy.jail(string(filepath.Separator))
+}
@@ -0,0 +1,138 @@
+package elmo
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func expectNotPermitted(t *testing.T) func(RunContext, Value) {
+	return func(context RunContext, blockResult Value) {
+		if blockResult.Type() != TypeError || !strings.Contains(blockResult.String(), "is not permitted") {
+			t.Errorf("expected not permitted error but found %v at %s", blockResult, getCallingFunc())
+		}
+	}
+}
+
+func TestSandboxBuiltins(t *testing.T) {
+
+	sandbox := NewSandboxContext(Policy{Builtins: []string{"set", "puts"}})
+
+	ParseTestAndRunBlockWithinContext(t, sandbox,
+		`pepper: "chipotle"`, ExpectValue(t, NewStringLiteral("chipotle")))
+
+	ParseTestAndRunBlockWithinContext(t, sandbox,
+		`file "file_testdata/peppers.txt"`, expectNotPermitted(t))
+
+	ParseTestAndRunBlockWithinContext(t, NewSandboxContext(Policy{}),
+		`len "chipotle"`, ExpectValue(t, NewIntegerLiteral(8)))
+}
+
+func TestSandboxModules(t *testing.T) {
+
+	sandbox := NewSandboxContext(Policy{Modules: []string{"peppers"}})
+	sandbox.RegisterModule(NewModule("peppers", func(context RunContext) Value {
+		return NewDictionaryValue(nil, map[string]Value{"chipotle": True})
+	}))
+	sandbox.RegisterModule(NewModule("sauces", func(context RunContext) Value {
+		return NewDictionaryValue(nil, map[string]Value{"salsa": True})
+	}))
+
+	ParseTestAndRunBlockWithinContext(t, sandbox,
+		`peppers: (load peppers)
+		 peppers.chipotle`, ExpectValue(t, True))
+
+	ParseTestAndRunBlockWithinContext(t, sandbox,
+		`load sauces`, expectNotPermitted(t))
+}
+
+func TestSandboxFiles(t *testing.T) {
+
+	ParseTestAndRunBlockWithinContext(t, NewSandboxContext(Policy{}),
+		`file "file_testdata/peppers.txt"`, expectNotPermitted(t))
+
+	sandbox := NewSandboxContext(Policy{FileRoot: "file_testdata"})
+
+	ParseTestAndRunBlockWithinContext(t, sandbox,
+		`((file "peppers.txt") string)`, ExpectValue(t, NewStringLiteral("chipotle,jalapeno,habanero")))
+
+	ParseTestAndRunBlockWithinContext(t, sandbox,
+		`((file "/peppers.txt") absPath)`, ExpectValue(t, NewStringLiteral("/peppers.txt")))
+
+	// paths can not escape the file root
+	//
+	ParseTestAndRunBlockWithinContext(t, sandbox,
+		`((file "../../file_testdata/peppers.txt") exists)`, ExpectValue(t, False))
+
+	ParseTestAndRunBlockWithinContext(t, sandbox,
+		`((file "../../core/sandbox.go") exists)`, ExpectValue(t, False))
+
+	ParseTestAndRunBlockWithinContext(t, sandbox,
+		`tempFile tmp {
+		   tmp.write "chipotle"
+		   return (tmp.string)
+		 }`, ExpectValue(t, NewStringLiteral("chipotle")))
+}
+
+func TestSandboxSymbolicLinks(t *testing.T) {
+
+	root, err := ioutil.TempDir("", "elmo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	outside, err := filepath.Abs("file_testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "outside")); err != nil {
+		t.Skip("symbolic links not supported")
+	}
+
+	ParseTestAndRunBlockWithinContext(t, NewSandboxContext(Policy{FileRoot: root}),
+		`file "outside/peppers.txt"`, expectNotPermitted(t))
+
+	ParseTestAndRunBlockWithinContext(t, NewSandboxContext(Policy{FileRoot: root}),
+		`file "outside/new.txt"`, expectNotPermitted(t))
+}
+
+func TestSandboxLoader(t *testing.T) {
+
+	ParseTestAndRunBlockWithinContext(t, NewSandboxContext(Policy{}),
+		`load "to_be_loaded"`, expectNotPermitted(t))
+
+	ParseTestAndRunBlockWithinContext(t, NewSandboxContext(Policy{FileRoot: "loader_testdata"}),
+		`loaded: (load "use_load")
+		 loaded.loaded.loaded`, ExpectValue(t, NewStringLiteral("chipotle")))
+
+	ParseTestAndRunBlockWithinContext(t, NewSandboxContext(Policy{FileRoot: "loader_testdata"}),
+		`load "../file_testdata/peppers"`, ExpectErrorValueAt(t, 1))
+}
+
+func TestSandboxPlugins(t *testing.T) {
+
+	root, err := ioutil.TempDir("", "elmo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	if err := ioutil.WriteFile(filepath.Join(root, "go.mod"), []byte("module chipotle\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ParseTestAndRunBlockWithinContext(t, NewSandboxContext(Policy{FileRoot: root}),
+		`load "chipotle"`, expectNotPermitted(t))
+}
+
+func TestSandboxSettings(t *testing.T) {
+
+	ParseTestAndRunBlockWithinContext(t, NewSandboxContext(Policy{}),
+		`globalSettings`, expectNotPermitted(t))
+
+	ParseTestAndRunBlockWithinContext(t, NewSandboxContext(Policy{Settings: true}),
+		`type (globalSettings)`, ExpectValue(t, NewIdentifier("dict")))
+}
@@ -42,6 +42,11 @@ const (
 	// SEListRef denotes a reference to a previously serialized list
 	//
 	SEListRef
+
+	// SEDictValueKey denotes a dictionary key that is not a string. It is
+	// followed by the key and then by the dictionary value
+	//
+	SEDictValueKey
 )
 
 // SerializationEvent is a struct holding event specific data
@@ -51,7 +56,7 @@ type SerializationEvent struct {
 	//
 	T SerializationEventType
 
-	// Key
+	// Key, or the type name of a record when opening a dictionary
 	//
 	K string
 
@@ -136,12 +141,23 @@ func (result *SerializationResult) addDict(value Value) error {
 	//
 	result.M[value.UUID()] = []byte{}
 
-	result.L = append(result.L, SerializationEvent{T: SEOpenDict, V: value.UUID()})
+	// records are opened with the name of their type
+	//
+	typeName := ""
+	if record, isRecord := value.(*dictValue); isRecord && record.record != nil {
+		typeName = record.record.name()
+	}
+	result.L = append(result.L, SerializationEvent{T: SEOpenDict, K: typeName, V: value.UUID()})
 
 	dictValue := value.(DictionaryValue)
-	for _, key := range dictValue.Keys() {
-		result.L = append(result.L, SerializationEvent{T: SEDictKey, K: key})
-		foundValue, _ := dictValue.Resolve(key)
+	for _, key := range dictValue.KeyValues() {
+		if key.Type() == TypeString {
+			result.L = append(result.L, SerializationEvent{T: SEDictKey, K: key.String()})
+		} else {
+			result.L = append(result.L, SerializationEvent{T: SEDictValueKey})
+			result.addValue(key)
+		}
+		foundValue, _ := dictValue.ResolveKey(key)
 		result.addValue(foundValue)
 	}
 	result.L = append(result.L, SerializationEvent{T: SECloseDict})
@@ -174,20 +190,42 @@ type deserializationContext struct {
 	mapping     map[uuid.UUID][]byte
 	constructed map[uuid.UUID]Value
 	stack       []Value
-	keyStack    []string
+
+	// keys of dictionary values that are being deserialized, a nil
+	// key denotes the next value is a key itself
+	keyStack []Value
+
+	// run context used to find record types, can be nil
+	runContext RunContext
+
+	// first error found while checking records
+	err ErrorValue
 }
 
-// ToValue reconstructs the original values used to create this serialization result
+// ToValue reconstructs the original values used to create this serialization result.
+// Records are reconstructed as plain dictionaries, see ToValueWithinContext
 //
 func (result *SerializationResult) ToValue() Value {
+	return result.ToValueWithinContext(nil)
+}
+
+// ToValueWithinContext reconstructs the original values used to create this
+// serialization result. Records of types declared within the execution of given
+// context are reconstructed as records and checked like newly created records
+//
+func (result *SerializationResult) ToValueWithinContext(runContext RunContext) Value {
 
 	context := &deserializationContext{
 		constructed: make(map[uuid.UUID]Value, 0),
 		mapping:     result.M,
 		stack:       make([]Value, 0, 0),
-		keyStack:    make([]string, 0, 0)}
+		keyStack:    make([]Value, 0, 0),
+		runContext:  runContext}
 
 	for _, ev := range result.L {
+		if context.err != nil {
+			return context.err
+		}
 		switch ev.T {
 		case SEValue:
 			context.processValue(ev)
@@ -205,11 +243,16 @@ func (result *SerializationResult) ToValue() Value {
 			context.processRef(ev)
 		case SEDictKey:
 			context.processDictKey(ev)
+		case SEDictValueKey:
+			context.pushKey(nil)
 		default:
 			panic("insupported serialization event")
 		}
 	}
 
+	if context.err != nil {
+		return context.err
+	}
 	return context.top()
 }
 
@@ -219,10 +262,10 @@ func (context *deserializationContext) pop() Value {
 	return v
 }
 
-func (context *deserializationContext) popKey() string {
-	var s string
-	s, context.keyStack = context.keyStack[len(context.keyStack)-1], context.keyStack[:len(context.keyStack)-1]
-	return s
+func (context *deserializationContext) popKey() Value {
+	var key Value
+	key, context.keyStack = context.keyStack[len(context.keyStack)-1], context.keyStack[:len(context.keyStack)-1]
+	return key
 }
 
 func (context *deserializationContext) top() Value {
@@ -233,7 +276,7 @@ func (context *deserializationContext) push(value Value) {
 	context.stack = append(context.stack, value)
 }
 
-func (context *deserializationContext) pushKey(key string) {
+func (context *deserializationContext) pushKey(key Value) {
 	context.keyStack = append(context.keyStack, key)
 }
 
@@ -258,7 +301,14 @@ func (context *deserializationContext) handleValue(value Value) {
 	//
 	if top.Type() == TypeDictionary {
 		key := context.popKey()
-		top.(DictionaryValue).Set(NewIdentifier(key), value)
+		if key == nil {
+			context.pushKey(value)
+			return
+		}
+		freezeKey(key)
+		if err := top.(*dictValue).restore(key, value); err != nil {
+			context.fail(err)
+		}
 		return
 	}
 
@@ -266,8 +316,16 @@ func (context *deserializationContext) handleValue(value Value) {
 
 }
 
+// fail remembers the first error found while deserializing
+//
+func (context *deserializationContext) fail(err ErrorValue) {
+	if context.err == nil {
+		context.err = err
+	}
+}
+
 func (context *deserializationContext) processValue(ev SerializationEvent) {
-	context.handleValue(NewBinaryValue(context.mapping[ev.V]).(BinaryValue).ToRegular())
+	context.handleValue(NewBinaryValue(context.mapping[ev.V]).(BinaryValue).ToRegularWithinContext(context.runContext))
 }
 
 func (context *deserializationContext) processOpenList(ev SerializationEvent) {
@@ -282,6 +340,17 @@ func (context *deserializationContext) processOpenList(ev SerializationEvent) {
 }
 
 func (context *deserializationContext) processClose(ev SerializationEvent) {
+
+	// records are checked when all their fields are restored
+	//
+	if record, isRecord := context.top().(*dictValue); isRecord && record.record != nil {
+		if err := record.record.checkComplete(record); err != nil {
+			context.fail(err)
+		} else if err := record.record.checkInvariant(record); err != nil {
+			context.fail(err)
+		}
+	}
+
 	if len(context.stack) > 1 {
 		context.pop()
 	}
@@ -292,8 +361,11 @@ func (context *deserializationContext) processRef(ev SerializationEvent) {
 }
 
 func (context *deserializationContext) processOpenDict(ev SerializationEvent) {
-	dict := NewDictionaryValue(nil, make(map[string]Value))
-	dict.(*dictValue).baseValue.id = ev.V
+	dict := NewDictionaryValue(nil, make(map[string]Value)).(*dictValue)
+	if recordType, found := lookupRecordType(context.runContext, ev.K); ev.K != "" && found {
+		dict = newRecord(recordType)
+	}
+	dict.baseValue.id = ev.V
 	context.constructed[ev.V] = dict
 	context.handleValue(dict)
 
@@ -303,5 +375,14 @@ func (context *deserializationContext) processOpenDict(ev SerializationEvent) {
 }
 
 func (context *deserializationContext) processDictKey(ev SerializationEvent) {
-	context.pushKey(ev.K)
+	context.pushKey(NewIdentifier(ev.K))
+}
+
+// freezeKey freezes deserialized collections used as keys, only frozen
+// collections could have been used as keys when they were serialized
+//
+func freezeKey(key Value) {
+	if freezable, ok := key.(FreezableValue); ok {
+		freezable.Freeze()
+	}
 }
@@ -0,0 +1,192 @@
+package elmo
+
+import "fmt"
+
+// exec runs a compiled program. Blocks run their statements within the
+// joined context and stop the original context when needed. A single
+// call program uses additionalArguments as leading arguments
+//
+func (program *program) exec(context RunContext, original RunContext, additionalArguments []Argument) Value {
+
+	var slotOf []int
+	frame, isFrame := context.(*runContext)
+	if isFrame && frame.layout != nil {
+		slotOf = program.slots(frame.layout)
+	}
+
+	var result Value = Nothing
+
+	var inDict DictionaryValue
+	var value Value
+	var found bool
+
+	// key of the loaded value in inDict, used to bind methods
+	var key string
+
+	for _, instruction := range program.code {
+		operand := instruction.operand
+
+		switch instruction.op {
+		case opLoad:
+			inDict = nil
+			found = false
+			switch {
+			case slotOf == nil:
+				value, found = context.Get(program.names[operand])
+			case slotOf[operand] < 0:
+				value, found = frame.getUnslotted(program.names[operand])
+			default:
+				value = frame.slot(slotOf[operand])
+				found = value != nil
+				if !found {
+					value, found = frame.getUnslotted(program.names[operand])
+				}
+			}
+		case opLoadPath:
+			inDict, value, found = program.paths[operand].LookUp(context)
+			key = methodKey(program.paths[operand])
+		case opLoadDynamic:
+			inDict = nil
+			value = program.calls[operand].firstArgument.Value().(Runnable).Run(context, NoArguments)
+			if value.Type() == TypeIdentifier {
+				key = methodKey(value)
+				inDict, value, _ = value.(IdentifierValue).LookUp(context)
+			}
+			found = true
+		case opLoadString:
+			inDict = nil
+			value = program.constants[operand].(StringValue).ResolveBlocks(context)
+			found = true
+		case opLoadConst:
+			inDict = nil
+			value = program.constants[operand]
+			found = true
+		case opInvoke:
+			if err := context.step(); err != nil {
+				result = program.calls[operand].addInfoWhenError(err, false)
+			} else {
+				result = program.invoke(context, program.calls[operand], program.arguments[operand], inDict, key, value, found, additionalArguments)
+			}
+		case opAssign:
+			assignment := program.assignments[operand]
+			if err := context.step(); err != nil {
+				result = program.calls[assignment.call].addInfoWhenError(err, false)
+			} else {
+				result = program.assign(context, frame, slotOf, assignment, inDict, key, value, found, additionalArguments)
+			}
+		case opBuiltin:
+			c := program.calls[operand]
+			if err := context.step(); err != nil {
+				result = c.addInfoWhenError(err, false)
+			} else {
+				if arguments, err := spreadArguments(context, program.arguments[operand]); err != nil {
+					result = c.pipeResult(context, c.addInfoWhenError(err, false))
+				} else {
+					result = c.pipeResult(context, c.addInfoWhenError(limitResult(context, c.function(context, arguments)), false))
+				}
+			}
+		case opStatement:
+			if context.isStopped() {
+				original.Stop()
+				return result
+			}
+			if result.Type() == TypeError && !result.(ErrorValue).CanBeIgnored() {
+				original.Stop()
+				return result
+			}
+		}
+	}
+
+	return result
+}
+
+// assign performs an assignment of a single value to a single variable.
+// Local variables are stored directly in their slot. When an assignment
+// is not written as 'name: value' and the loaded function is not set or
+// let, the loaded function is invoked like any other function
+//
+func (program *program) assign(context RunContext, frame *runContext, slotOf []int, assignment assignment, inDict DictionaryValue, key string, value Value, found bool, additionalArguments []Argument) Value {
+	c := program.calls[assignment.call]
+	arguments := program.arguments[assignment.call]
+
+	convertBlockToDictionary := true
+	if !assignment.syntax {
+		assigner, isAssigner := value.(*assignFunction)
+		if !found || !isAssigner || inDict != nil || len(additionalArguments) > 0 {
+			return program.invoke(context, c, arguments, inDict, key, value, found, additionalArguments)
+		}
+		convertBlockToDictionary = assigner.convertBlockToDictionary
+	}
+
+	assigned := EvalArgument(context, arguments[1])
+
+	if slotOf != nil && slotOf[assignment.name] >= 0 {
+		switch assigned.Type() {
+		case TypeReturn, TypeBlock, TypeError:
+		default:
+			if function, isFunction := assigned.(*inspectableGoFunction); isFunction {
+				function.nameWhenAnonymous(program.names[assignment.name])
+			}
+			if err := limitResult(context, assigned); err != assigned {
+				return c.pipeResult(context, c.addInfoWhenError(err, false))
+			}
+			frame.setSlot(slotOf[assignment.name], assigned)
+			return c.pipeResult(context, assigned)
+		}
+	}
+
+	return c.pipeResult(context, c.addInfoWhenError(limitResult(context, assign(context, arguments, assigned, convertBlockToDictionary)), false))
+}
+
+// invoke calls a loaded function, exactly like an interpreted call would do
+//
+func (program *program) invoke(context RunContext, c *call, arguments []Argument, inDict DictionaryValue, key string, value Value, found bool, additionalArguments []Argument) Value {
+
+	useArguments := arguments
+	if len(additionalArguments) > 0 {
+		useArguments = append(append([]Argument{}, additionalArguments...), arguments...)
+	}
+
+	useArguments, err := spreadArguments(context, useArguments)
+	if err != nil {
+		return c.pipeResult(context, c.addInfoWhenError(err, false))
+	}
+
+	// when call can not be resolved, try to find the 'func missing' function
+	//
+	if !found {
+		if inDict == nil {
+			value, found = context.Get("?")
+		} else {
+			value, found = inDict.Resolve("?")
+		}
+
+		if !found {
+			return c.pipeResult(context, c.addInfoWhenError(NewErrorValue(fmt.Sprintf("call to undefined \"%s\"", c.firstArgument)), false))
+		}
+
+		key = "?"
+		useArguments = createArgumentsForMissingFunc(context, c, useArguments)
+	}
+
+	if value == nil {
+		return c.pipeResult(context, c.addInfoWhenError(NewErrorValue(fmt.Sprintf("call to %s results in invalid nil value", c.Name())), false))
+	}
+
+	if err := checkKeywordArguments(c.Name(), value, useArguments); err != nil {
+		return c.pipeResult(context, c.addInfoWhenError(err, false))
+	}
+
+	if inDict != nil {
+		defer bindMethod(context, inDict, key, value)()
+	}
+
+	// go functions are always called, other runnable values
+	// only when they are used to access their content
+	//
+	if runnable, isRunnable := value.(Runnable); isRunnable && (value.Type() == TypeGoFunction || len(useArguments) > 0) {
+		return c.pipeResult(context, c.addInfoWhenError(limitResult(context, runnable.Run(context, useArguments)), isTraceBoundary(inDict, value)))
+	}
+
+	return c.pipeResult(context, c.addInfoWhenError(value, false))
+}
@@ -0,0 +1,213 @@
+package elmo
+
+import "testing"
+
+func withVM(useVM bool, f func()) {
+	previous := GlobalSettings().VM
+	GlobalSettings().VM = useVM
+	defer func() {
+		GlobalSettings().VM = previous
+	}()
+	f()
+}
+
+func onBothEngines(t *testing.T, s string, testfunc ...func(RunContext, Value)) {
+	withVM(false, func() { ParseTestAndRunBlock(t, s, testfunc...) })
+	withVM(true, func() { ParseTestAndRunBlock(t, s, testfunc...) })
+}
+
+func TestVMRunsCalls(t *testing.T) {
+
+	onBothEngines(t,
+		`"chipotle"`, ExpectValue(t, NewStringLiteral("chipotle")))
+
+	onBothEngines(t,
+		`3`, ExpectValue(t, NewIntegerLiteral(3)))
+
+	onBothEngines(t,
+		`sauce: "chipotle"
+		 sauce`, ExpectValue(t, NewStringLiteral("chipotle")))
+
+	onBothEngines(t,
+		`sauce: "chipotle"
+		 "\{$sauce}!"`, ExpectValue(t, NewStringLiteral("chipotle!")))
+
+	onBothEngines(t,
+		`peppers: {chipotle: 3}
+		 peppers.chipotle`, ExpectValue(t, NewIntegerLiteral(3)))
+
+	onBothEngines(t,
+		`(len "chipotle")`, ExpectValue(t, NewIntegerLiteral(8)))
+
+	onBothEngines(t,
+		`"chipotle" | len`, ExpectValue(t, NewIntegerLiteral(8)))
+
+	onBothEngines(t,
+		`no_such_pepper`, ExpectErrorValueAt(t, 1))
+
+	onBothEngines(t,
+		`? : (func name args { return $name })
+		 chipotle`, ExpectValue(t, NewIdentifier("chipotle")))
+}
+
+func TestVMRunsFunctions(t *testing.T) {
+
+	onBothEngines(t,
+		`f: (func a b? 2 {
+		   c: (plus $a $b)
+		   return $c
+		 })
+		 f 1`, ExpectValue(t, NewIntegerLiteral(3)))
+
+	onBothEngines(t,
+		`fib: (func n {
+		   if (lt $n 2) { return $n }
+		   return (plus (fib (minus $n 1)) (fib (minus $n 2)))
+		 })
+		 fib 10`, ExpectValue(t, NewIntegerLiteral(55)))
+
+	// closures see local variables of the function that created them
+	//
+	onBothEngines(t,
+		`sauce: (func pepper {
+		   hot: (func { return $pepper })
+		   return (hot)
+		 })
+		 sauce "chipotle"`, ExpectValue(t, NewStringLiteral("chipotle")))
+
+	onBothEngines(t,
+		`f: (func {
+		   sauce: "chipotle"
+		   while (eq $sauce "chipotle") { sauce: "jalapeno" }
+		   return $sauce
+		 })
+		 f`, ExpectValue(t, NewStringLiteral("jalapeno")))
+
+	onBothEngines(t,
+		`f: (func {
+		   error "chipotle"
+		   return "jalapeno"
+		 })
+		 f`, ExpectErrorValueAt(t, 2))
+
+	onBothEngines(t,
+		`sauce: { pepper: "chipotle"; get: (func { return $this.pepper }) }
+		 sauce.get`, ExpectValue(t, NewStringLiteral("chipotle")))
+}
+
+func TestVMAssignsLocals(t *testing.T) {
+
+	onBothEngines(t,
+		`f: (func {
+		   sauce: {pepper: "chipotle"}
+		   return $sauce.pepper
+		 })
+		 f`, ExpectValue(t, NewStringLiteral("chipotle")))
+
+	onBothEngines(t,
+		`f: (func {
+		   peppers: (func { return "chipotle" "jalapeno" })
+		   pepper: (peppers)
+		   return $pepper
+		 })
+		 f`, ExpectValue(t, NewStringLiteral("chipotle")))
+
+	onBothEngines(t,
+		`f: (func {
+		   if $true { sauce: "chipotle" }
+		   return $sauce
+		 })
+		 f`, ExpectValue(t, NewStringLiteral("chipotle")))
+
+	onBothEngines(t,
+		`f: (func {
+		   hot: (func { return "chipotle" })
+		   return (help hot)
+		 })
+		 f`, ExpectValue(t, NewStringLiteral("usage: hot")))
+
+	// set and let are only used when they are not redefined
+	//
+	onBothEngines(t,
+		`f: (func {
+		   set: (func name value { return "chipotle" })
+		   set sauce "jalapeno"
+		 })
+		 f`, ExpectValue(t, NewStringLiteral("chipotle")))
+}
+
+func TestVMFramesStoreLocalsInSlots(t *testing.T) {
+
+	// variables assigned within blocks are locals too
+	//
+	ParseAndTestBlock(t,
+		`a: 1
+		 while (lt $a 3) {
+		   if $true { b: 2 }
+		   set c 3
+		 }`, func(block Block) {
+
+			layout := newSlotLayout(block, []string{})
+			if len(layout.names) != 3 || layout.index["a"] != 0 || layout.index["b"] != 1 || layout.index["c"] != 2 {
+				t.Errorf("unexpected layout %v", layout.names)
+			}
+		})
+
+	ParseAndTestBlock(t,
+		`a: 1
+		 b: 2
+		 puts $a`, func(block Block) {
+
+			layout := newSlotLayout(block, []string{"x"})
+			if len(layout.names) != 3 || layout.index["x"] != 0 || layout.index["a"] != 1 || layout.index["b"] != 2 {
+				t.Errorf("unexpected layout %v", layout.names)
+			}
+
+			frame := newFrameContext(NewGlobalContext(), layout)
+			frame.Set("a", NewIntegerLiteral(1))
+			frame.Set("c", NewIntegerLiteral(3))
+
+			if value, found := frame.Get("a"); !found || value.String() != "1" {
+				t.Errorf("expected a to be stored in frame, found %v", value)
+			}
+			if _, found := frame.Get("b"); found {
+				t.Error("expected b not to be set")
+			}
+			if len(frame.Mapping()) != 2 {
+				t.Errorf("expected a and c in mapping, found %v", frame.Mapping())
+			}
+		})
+}
+
+const vmBenchmarkScript = `loop: (func n {
+  i: 0
+  total: 0
+  while (lt $i $n) {
+    total: (plus $total $i)
+    i: (incr $i)
+  }
+  return $total
+})
+loop 1000`
+
+func benchmarkEngine(b *testing.B, useVM bool) {
+	withVM(useVM, func() {
+		block, err := Parse2Block(vmBenchmarkScript, "benchmark")
+		if err != nil {
+			b.Fatal(err)
+		}
+		global := NewGlobalContext()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			block.Run(global, NoArguments)
+		}
+	})
+}
+
+func BenchmarkInterpreter(b *testing.B) {
+	benchmarkEngine(b, false)
+}
+
+func BenchmarkVM(b *testing.B) {
+	benchmarkEngine(b, true)
+}
@@ -0,0 +1,528 @@
+package elmo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+var (
+	typeOfValue      = reflect.TypeOf((*Value)(nil)).Elem()
+	typeOfRunContext = reflect.TypeOf((*RunContext)(nil)).Elem()
+	typeOfGoContext  = reflect.TypeOf((*context.Context)(nil)).Elem()
+	typeOfError      = reflect.TypeOf((*error)(nil)).Elem()
+	typeOfBytes      = reflect.TypeOf([]byte(nil))
+
+	// typeInfoGoValue is used for go values that have no elmo counterpart
+	typeInfoGoValue = NewTypeInfo("go")
+)
+
+// WrapFunc creates an elmo function from an arbitrary go function. Arguments
+// are converted to the parameter types of the go function and its results are
+// converted back to elmo values. A go function can accept a RunContext or a
+// context.Context as first parameter and can return an error as last result.
+// WrapFunc panics when function is not a go function
+//
+func WrapFunc(name string, help string, function interface{}) NamedValue {
+
+	funcValue := reflect.ValueOf(function)
+	funcType := funcValue.Type()
+	if funcType.Kind() != reflect.Func {
+		panic(fmt.Sprintf("can not wrap %v as function %s", funcType, name))
+	}
+
+	// first parameter can be used to pass the context of the call
+	//
+	first := 0
+	if funcType.NumIn() > 0 && (funcType.In(0) == typeOfRunContext || funcType.In(0) == typeOfGoContext) {
+		first = 1
+	}
+
+	min := funcType.NumIn() - first
+	max := min
+	if funcType.IsVariadic() {
+		min--
+		max = math.MaxInt16
+	}
+
+	usage := wrappedUsage(funcType, first)
+	if help == "" {
+		help = fmt.Sprintf("usage: %s %s", name, usage)
+	}
+
+	return NewGoFunctionWithHelp(name, help, func(context RunContext, arguments []Argument) Value {
+
+		argLen, err := CheckArguments(arguments, min, max, name, usage)
+		if err != nil {
+			return err
+		}
+
+		in := make([]reflect.Value, 0, first+argLen)
+		if first == 1 {
+			if funcType.In(0) == typeOfRunContext {
+				in = append(in, reflect.ValueOf(context))
+			} else {
+				in = append(in, reflect.ValueOf(context.Context()))
+			}
+		}
+
+		for i, argument := range arguments {
+			value, err := evalArgumentAsData(context, argument, wrappedParameterType(funcType, first+i))
+			if err != nil {
+				return err
+			}
+			converted, err := convertValueToGo(value, wrappedParameterType(funcType, first+i))
+			if err != nil {
+				return NewInvalidArgumentErrorValue(name, i+1, err)
+			}
+			in = append(in, converted)
+		}
+
+		results, err := callWrapped(name, funcValue, in)
+		if err != nil {
+			return err
+		}
+		return convertResultsToValue(results)
+	})
+}
+
+// callWrapped calls a wrapped go function and turns a panic of that function
+// into an error so it does not crash the host
+//
+func callWrapped(name string, funcValue reflect.Value, in []reflect.Value) (results []reflect.Value, err ErrorValue) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			results = nil
+			err = NewErrorValue(fmt.Sprintf("%s failed: %v", name, r))
+		}
+	}()
+
+	return funcValue.Call(in), nil
+}
+
+// wrappedParameterType returns the type of the n-th parameter of a
+// function, parameters beyond the last one are variadic
+//
+func wrappedParameterType(funcType reflect.Type, n int) reflect.Type {
+	if funcType.IsVariadic() && n >= funcType.NumIn()-1 {
+		return funcType.In(funcType.NumIn() - 1).Elem()
+	}
+	return funcType.In(n)
+}
+
+func wrappedUsage(funcType reflect.Type, first int) string {
+	parameters := make([]string, 0, funcType.NumIn())
+	for i := first; i < funcType.NumIn(); i++ {
+		if funcType.IsVariadic() && i == funcType.NumIn()-1 {
+			parameters = append(parameters, fmt.Sprintf("<%v>*", funcType.In(i).Elem()))
+		} else {
+			parameters = append(parameters, fmt.Sprintf("<%v>", funcType.In(i)))
+		}
+	}
+	return strings.Join(parameters, " ")
+}
+
+// convertResultsToValue converts the results of a go function. A non nil error
+// as last result becomes an error value, multiple results are returned as
+// multiple return values
+//
+func convertResultsToValue(results []reflect.Value) Value {
+
+	if len(results) > 0 && results[len(results)-1].Type() == typeOfError {
+		if err := results[len(results)-1]; !err.IsNil() {
+			return NewErrorValue(err.Interface().(error).Error())
+		}
+		results = results[:len(results)-1]
+	}
+
+	switch len(results) {
+	case 0:
+		return Nothing
+	case 1:
+		return convertGoToValue(results[0])
+	default:
+		values := make([]Value, len(results))
+		for i, result := range results {
+			values[i] = convertGoToValue(result)
+		}
+		return NewReturnValue(values)
+	}
+}
+
+// NewInvalidArgumentErrorValue creates an error for an argument
+// that can not be converted to the type a function expects
+//
+func NewInvalidArgumentErrorValue(fname string, position int, err ErrorValue) ErrorValue {
+	return NewErrorValue(fmt.Sprintf("invalid argument %d for %s: %s", position, fname, err.Internal()))
+}
+
+func expectedType(expected string, value Value) ErrorValue {
+	if value.Type() == TypeError {
+		return value.(ErrorValue)
+	}
+	return NewErrorValue(fmt.Sprintf("expected %s instead of %v", expected, value))
+}
+
+// evalArgumentAsData evaluates an argument that is converted into a go value
+// of given type. Blocks are used to write dictionaries as arguments
+//
+func evalArgumentAsData(context RunContext, argument Argument, target reflect.Type) (Value, ErrorValue) {
+	value := EvalArgument(context, argument)
+	if value.Type() == TypeError {
+		return value, value.(ErrorValue)
+	}
+	if value.Type() == TypeBlock && target != typeOfValue && target.Kind() != reflect.Interface {
+		return NewDictionaryWithBlock(context, value.(Block)).(Value), nil
+	}
+	return value, nil
+}
+
+// DecodeArgument evaluates an argument and decodes it into the go value
+// given pointer points to
+//
+func DecodeArgument(context RunContext, argument Argument, pointer interface{}) ErrorValue {
+	target := reflect.TypeOf(pointer)
+	if target.Kind() != reflect.Ptr {
+		return NewErrorValue(fmt.Sprintf("can not decode into %v, expected a pointer", target))
+	}
+	value, err := evalArgumentAsData(context, argument, target.Elem())
+	if err != nil {
+		return err
+	}
+	return DecodeValue(value, pointer)
+}
+
+// ToString converts a string value into a go string
+//
+func ToString(value Value) (string, ErrorValue) {
+	if value.Type() != TypeString {
+		return "", expectedType("a string", value)
+	}
+	return value.String(), nil
+}
+
+// ToBool converts a boolean value into a go bool
+//
+func ToBool(value Value) (bool, ErrorValue) {
+	if value.Type() != TypeBoolean {
+		return false, expectedType("a boolean", value)
+	}
+	return value.Internal().(bool), nil
+}
+
+// ToInt converts an integer value into a go integer of given bit size
+//
+func ToInt(value Value, bitSize int) (int64, ErrorValue) {
+	if value.Type() != TypeInteger {
+		return 0, expectedType("an integer", value)
+	}
+	i := value.Internal().(int64)
+	if bitSize < 64 && (i < -1<<uint(bitSize-1) || i >= 1<<uint(bitSize-1)) {
+		return 0, NewErrorValue(fmt.Sprintf("%d does not fit in %d bits", i, bitSize))
+	}
+	return i, nil
+}
+
+// ToUint converts an integer value into a go unsigned integer of given bit size
+//
+func ToUint(value Value, bitSize int) (uint64, ErrorValue) {
+	if value.Type() != TypeInteger {
+		return 0, expectedType("an integer", value)
+	}
+	i := value.Internal().(int64)
+	if i < 0 || (bitSize < 64 && i >= 1<<uint(bitSize)) {
+		return 0, NewErrorValue(fmt.Sprintf("%d does not fit in %d unsigned bits", i, bitSize))
+	}
+	return uint64(i), nil
+}
+
+// ToFloat converts a float, integer or decimal value into a go float
+//
+func ToFloat(value Value) (float64, ErrorValue) {
+	switch value.Type() {
+	case TypeFloat:
+		return value.Internal().(float64), nil
+	case TypeInteger:
+		return float64(value.Internal().(int64)), nil
+	case TypeBigInteger, TypeDecimal:
+		r, _ := ToRat(value)
+		f, _ := r.Float64()
+		return f, nil
+	default:
+		return 0, expectedType("a float", value)
+	}
+}
+
+// ToBytes converts a binary or string value into a go byte slice
+//
+func ToBytes(value Value) ([]byte, ErrorValue) {
+	switch value.Type() {
+	case TypeBinary:
+		return value.Internal().([]byte), nil
+	case TypeString:
+		return []byte(value.String()), nil
+	default:
+		return nil, expectedType("binary data", value)
+	}
+}
+
+// EncodeValue converts a go value into an elmo value. Pointers to structs
+// become dictionaries as created by NewDictionaryFromStruct
+//
+func EncodeValue(value interface{}) Value {
+	return convertGoToValue(reflect.ValueOf(value))
+}
+
+// convertValueToGo converts an elmo value into a go value of given type
+//
+func convertValueToGo(value Value, target reflect.Type) (reflect.Value, ErrorValue) {
+
+	// fields of dictionaries that are created from structs are decoded by their value
+	//
+	if accessor, isAccessor := value.(*fieldAccessor); isAccessor {
+		value = convertGoToValue(accessor.field)
+	}
+
+	if target == typeOfValue || (target.Kind() == reflect.Interface && reflect.TypeOf(value).Implements(target) && target.NumMethod() > 0) {
+		return reflect.ValueOf(value), nil
+	}
+
+	// go values that have no elmo counterpart are passed as is
+	//
+	if value.IsType(typeInfoGoValue) && reflect.TypeOf(value.Internal()).AssignableTo(target) {
+		return reflect.ValueOf(value.Internal()), nil
+	}
+
+	result := reflect.New(target).Elem()
+
+	switch target.Kind() {
+	case reflect.Interface:
+		if target.NumMethod() > 0 {
+			return result, NewErrorValue(fmt.Sprintf("can not convert %v to %v", value, target))
+		}
+		if converted := ConvertValueToInterface(value); value != Nothing && converted != nil {
+			result.Set(reflect.ValueOf(converted))
+		}
+	case reflect.Bool:
+		b, err := ToBool(value)
+		if err != nil {
+			return result, err
+		}
+		result.SetBool(b)
+	case reflect.String:
+		str, err := ToString(value)
+		if err != nil {
+			return result, err
+		}
+		result.SetString(str)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := ToInt(value, target.Bits())
+		if err != nil {
+			return result, err
+		}
+		result.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := ToUint(value, target.Bits())
+		if err != nil {
+			return result, err
+		}
+		result.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := ToFloat(value)
+		if err != nil {
+			return result, err
+		}
+		result.SetFloat(f)
+	case reflect.Slice:
+		if target == typeOfBytes && (value.Type() == TypeBinary || value.Type() == TypeString) {
+			bytes, _ := ToBytes(value)
+			result.SetBytes(bytes)
+			return result, nil
+		}
+		list, ok := value.(ListValue)
+		if !ok {
+			return result, expectedType("a list", value)
+		}
+		values := list.List()
+		result.Set(reflect.MakeSlice(target, len(values), len(values)))
+		for i, v := range values {
+			converted, err := convertValueToGo(v, target.Elem())
+			if err != nil {
+				return result, err
+			}
+			result.Index(i).Set(converted)
+		}
+	case reflect.Array:
+		list, ok := value.(ListValue)
+		if !ok || len(list.List()) != target.Len() {
+			return result, expectedType(fmt.Sprintf("a list of %d values", target.Len()), value)
+		}
+		for i, v := range list.List() {
+			converted, err := convertValueToGo(v, target.Elem())
+			if err != nil {
+				return result, err
+			}
+			result.Index(i).Set(converted)
+		}
+	case reflect.Map:
+		dict, ok := value.(DictionaryValue)
+		if !ok || target.Key().Kind() != reflect.String {
+			return result, expectedType("a dictionary", value)
+		}
+		result.Set(reflect.MakeMapWithSize(target, len(dict.Keys())))
+		for _, key := range dict.KeyValues() {
+			v, _ := dict.ResolveKey(key)
+			converted, err := convertValueToGo(v, target.Elem())
+			if err != nil {
+				return result, err
+			}
+			result.SetMapIndex(reflect.ValueOf(key.String()).Convert(target.Key()), converted)
+		}
+	case reflect.Struct:
+		dict, ok := value.(DictionaryValue)
+		if !ok {
+			return result, expectedType("a dictionary", value)
+		}
+		for _, key := range dict.Keys() {
+			field, found := structField(target, key)
+			if !found {
+				continue
+			}
+			v, _ := dict.Resolve(key)
+			converted, err := convertValueToGo(v, field.Type)
+			if err != nil {
+				return result, err
+			}
+			result.FieldByIndex(field.Index).Set(converted)
+		}
+	case reflect.Ptr:
+		if value == Nothing {
+			return result, nil
+		}
+		converted, err := convertValueToGo(value, target.Elem())
+		if err != nil {
+			return result, err
+		}
+		result.Set(reflect.New(target.Elem()))
+		result.Elem().Set(converted)
+	default:
+		return result, NewErrorValue(fmt.Sprintf("can not convert %v to %v", value, target))
+	}
+
+	return result, nil
+}
+
+// convertGoToValue converts a go value into an elmo value
+//
+func convertGoToValue(value reflect.Value) Value {
+
+	if !value.IsValid() {
+		return Nothing
+	}
+
+	if value.Type().Implements(typeOfValue) {
+		if value.Kind() == reflect.Interface && value.IsNil() {
+			return Nothing
+		}
+		return value.Interface().(Value)
+	}
+
+	switch value.Kind() {
+	case reflect.Bool:
+		return TrueOrFalse(value.Bool())
+	case reflect.String:
+		return NewStringLiteral(value.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return NewIntegerLiteral(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		// values that do not fit in an int64 become big integers
+		//
+		if value.Uint() > math.MaxInt64 {
+			return NewIntegerFromBig(new(big.Int).SetUint64(value.Uint()))
+		}
+		return NewIntegerLiteral(int64(value.Uint()))
+	case reflect.Float32, reflect.Float64:
+		return NewFloatLiteral(value.Float())
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8 {
+			return NewBinaryValue(value.Bytes())
+		}
+		// slices that can be set, like fields of structs, are wrapped so
+		// scripts change the slice itself
+		//
+		if value.Kind() == reflect.Slice && value.CanSet() {
+			return newListFromSlice(value)
+		}
+		values := make([]Value, value.Len())
+		for i := range values {
+			values[i] = convertGoToValue(value.Index(i))
+		}
+		return NewListValue(values)
+	case reflect.Map:
+		if value.CanSet() && value.Type().Key().Kind() == reflect.String {
+			return newDictionaryFromMap(value)
+		}
+		mapping := make(map[string]Value, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			mapping[fmt.Sprint(iter.Key().Interface())] = convertGoToValue(iter.Value())
+		}
+		return NewDictionaryValue(nil, mapping)
+	case reflect.Struct:
+		if !value.CanAddr() {
+			copy := reflect.New(value.Type())
+			copy.Elem().Set(value)
+			value = copy.Elem()
+		}
+		return NewDictionaryFromStruct(nil, value.Addr().Interface()).(Value)
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return Nothing
+		}
+		if err, isError := value.Interface().(error); isError {
+			return NewErrorValue(err.Error())
+		}
+		if value.Kind() == reflect.Ptr && value.Type().Elem().Kind() == reflect.Struct {
+			return NewDictionaryFromStruct(nil, value.Interface()).(Value)
+		}
+		return convertGoToValue(value.Elem())
+	default:
+		return NewInternalValue(typeInfoGoValue, value.Interface())
+	}
+}
+
+// structField finds the field of a struct that is used for given key
+//
+func structField(structType reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if name, visible := structFieldName(field); visible && (name == key || strings.EqualFold(name, key)) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// DecodeValue fills the go value given pointer points to using an elmo value.
+// Dictionaries can be decoded into structs (using the same field names as
+// NewDictionaryFromStruct) and maps, lists into slices
+//
+func DecodeValue(value Value, pointer interface{}) ErrorValue {
+
+	target := reflect.ValueOf(pointer)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return NewErrorValue(fmt.Sprintf("can not decode into %v, expected a pointer", target.Type()))
+	}
+
+	converted, err := convertValueToGo(value, target.Elem().Type())
+	if err != nil {
+		return err
+	}
+
+	target.Elem().Set(converted)
+	return nil
+}
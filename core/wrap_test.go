@@ -0,0 +1,159 @@
+package elmo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+type pepper struct {
+	Name    string
+	Hotness int
+	Dried   bool
+	origin  string
+}
+
+func wrapContext() RunContext {
+	global := NewGlobalContext()
+
+	global.SetNamed(WrapFunc("repeat", "", strings.Repeat))
+	global.SetNamed(WrapFunc("half", "", func(f float32) float64 { return float64(f) / 2 }))
+	global.SetNamed(WrapFunc("hot", "", func(hotness uint8) bool { return hotness > 100 }))
+	global.SetNamed(WrapFunc("sum", "", func(values ...int) int {
+		total := 0
+		for _, v := range values {
+			total = total + v
+		}
+		return total
+	}))
+	global.SetNamed(WrapFunc("prefixed", "", func(prefix string, names []string) []string {
+		result := make([]string, len(names))
+		for i, name := range names {
+			result[i] = prefix + name
+		}
+		return result
+	}))
+	global.SetNamed(WrapFunc("count", "", func(counts map[string]int) int { return len(counts) }))
+	global.SetNamed(WrapFunc("describe", "", func(p pepper) string { return fmt.Sprintf("%s:%d:%v", p.Name, p.Hotness, p.Dried) }))
+	global.SetNamed(WrapFunc("pepper", "", func(name string) *pepper { return &pepper{Name: name, Hotness: 3} }))
+	global.SetNamed(WrapFunc("bytes", "", func(data []byte) int { return len(data) }))
+	global.SetNamed(WrapFunc("divide", "", func(a, b int) (int, error) {
+		if b == 0 {
+			return 0, errors.New("division by zero")
+		}
+		return a / b, nil
+	}))
+	global.SetNamed(WrapFunc("split", "", func(a int) (int, int) { return a / 2, a - a/2 }))
+	global.SetNamed(WrapFunc("known", "", func(context RunContext, name string) bool {
+		_, found := context.Get(name)
+		return found
+	}))
+	global.SetNamed(WrapFunc("cancelled", "", func(ctx context.Context) bool { return ctx.Err() != nil }))
+	global.SetNamed(WrapFunc("maxUint", "", func() uint64 { return math.MaxUint64 }))
+	global.SetNamed(WrapFunc("typeOf", "", func(value Value) string { return value.Info().Name().String() }))
+
+	return global
+}
+
+func TestWrapFuncConvertsArguments(t *testing.T) {
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`repeat "ab" 3`, ExpectValue(t, NewStringLiteral("ababab")))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`half 3`, ExpectValue(t, NewFloatLiteral(1.5)))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`hot 101`, ExpectValue(t, True))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`sum 1 2 3`, ExpectValue(t, NewIntegerLiteral(6)))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`sum`, ExpectValue(t, NewIntegerLiteral(0)))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`prefixed "hot " ["chipotle" "jalapeno"]`, ExpectValue(t, NewListValueFromStrings([]string{"hot chipotle", "hot jalapeno"})))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`count {chipotle: 1; jalapeno: 2}`, ExpectValue(t, NewIntegerLiteral(2)))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`describe {name: "chipotle"; hotness: 3; dried: $true}`, ExpectValue(t, NewStringLiteral("chipotle:3:true")))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`bytes "chipotle"`, ExpectValue(t, NewIntegerLiteral(8)))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`known "repeat"`, ExpectValue(t, True))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`cancelled`, ExpectValue(t, False))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`typeOf [1 2]`, ExpectValue(t, NewStringLiteral("list")))
+}
+
+func TestWrapFuncConvertsResults(t *testing.T) {
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`divide 6 3`, ExpectValue(t, NewIntegerLiteral(2)))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`divide 6 0`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`set a b (split 5)
+		 [$a $b]`, ExpectValue(t, NewListValue([]Value{NewIntegerLiteral(2), NewIntegerLiteral(3)})))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`p: (pepper "chipotle")
+		 p.Hotness`, ExpectValue(t, NewIntegerLiteral(3)))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`maxUint`, ExpectString(t, "18446744073709551615"))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`gt (maxUint) 0`, ExpectValue(t, True))
+}
+
+func TestWrapFuncRecoversFromPanics(t *testing.T) {
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`repeat "ab" -1`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`
+		 divide 6 3
+		 repeat "ab" -1`, ExpectErrorValueAt(t, 3))
+}
+
+func TestWrapFuncChecksArguments(t *testing.T) {
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`repeat "ab"`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`repeat 3 "ab"`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`hot 1000`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`hot -1`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`sum 1 "2"`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`prefixed "hot " [1 2]`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`repeat "ab" times=3`, ExpectErrorValueAt(t, 1))
+
+	ParseTestAndRunBlockWithinContext(t, wrapContext(),
+		`help repeat`, ExpectValue(t, NewStringLiteral("usage: repeat <string> <int>")))
+}
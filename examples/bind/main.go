@@ -0,0 +1,23 @@
+package main
+
+import (
+	"github.com/okke/elmo/examples/bind/peppersmodule"
+	"github.com/okke/elmo/runner"
+)
+
+//go:generate go run -C ../../tools/bind ./cmd/elmo-bind -package peppersmodule -module peppers -o ../../examples/bind/peppersmodule/module.go github.com/okke/elmo/examples/bind/peppers
+
+func main() {
+	// create a context with all elmo's default modules
+	//
+	context := runner.NewMainContext()
+
+	// add the module that is generated from the peppers package
+	//
+	context.RegisterModule(peppersmodule.Module)
+
+	// and run!
+	//
+	runner := runner.NewRunner(context)
+	runner.Main()
+}
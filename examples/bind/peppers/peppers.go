@@ -0,0 +1,81 @@
+// Package peppers is an ordinary go package that is turned into
+// an elmo module by elmo bind
+//
+package peppers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Scoville measures the hotness of a pepper
+//
+type Scoville uint32
+
+// Pepper describes a pepper
+//
+type Pepper struct {
+	Name     string
+	Hotness  Scoville
+	Dishes   []string
+	Ratings  map[string]int
+	internal bool
+}
+
+var hotness = map[string]Scoville{
+	"chipotle": 5000,
+	"jalapeno": 3500,
+}
+
+// Hotness returns the hotness of a known pepper
+//
+func Hotness(name string) (Scoville, error) {
+	if h, found := hotness[name]; found {
+		return h, nil
+	}
+	return 0, fmt.Errorf("unknown pepper %s", name)
+}
+
+// Blend mixes peppers into one sauce
+//
+func Blend(sauce string, peppers ...string) string {
+	return fmt.Sprintf("%s with %s", sauce, strings.Join(peppers, " and "))
+}
+
+// Dilute makes a sauce less hot
+//
+func Dilute(hotness float64, times int8) float64 {
+	return hotness / float64(times)
+}
+
+// New creates a pepper
+//
+func New(name string, hotness Scoville) *Pepper {
+	return &Pepper{Name: name, Hotness: hotness}
+}
+
+// Describe describes a pepper
+//
+func Describe(pepper Pepper) string {
+	return fmt.Sprintf("%s (%d) goes well with %s", pepper.Name, pepper.Hotness, strings.Join(pepper.Dishes, ", "))
+}
+
+// Split divides peppers over two dishes
+//
+func Split(peppers int) (int, int) {
+	return peppers / 2, peppers - peppers/2
+}
+
+// Known checks if a pepper is known, unless the caller has given up
+//
+func Known(ctx context.Context, name string) bool {
+	_, found := hotness[name]
+	return found && ctx.Err() == nil
+}
+
+// Grind can not be bound since elmo can not pass go functions
+//
+func Grind(pepper string, grinder func(string) string) string {
+	return grinder(pepper)
+}
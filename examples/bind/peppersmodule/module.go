@@ -0,0 +1,180 @@
+// Code generated by elmo bind from github.com/okke/elmo/examples/bind/peppers. DO NOT EDIT.
+
+package peppersmodule
+
+import (
+	"math"
+
+	elmo "github.com/okke/elmo/core"
+	"github.com/okke/elmo/examples/bind/peppers"
+)
+
+// Module contains the functions of go package github.com/okke/elmo/examples/bind/peppers
+var Module = elmo.NewModule("peppers", initModule)
+
+func initModule(context elmo.RunContext) elmo.Value {
+	return elmo.NewMappingForModule(context, []elmo.NamedValue{
+		bindBlend(),
+		bindDescribe(),
+		bindDilute(),
+		bindHotness(),
+		bindKnown(),
+		bindNew(),
+		bindSplit(),
+	})
+}
+
+func bindBlend() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("blend", "Blend mixes peppers into one sauce", func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		argLen, err := elmo.CheckArguments(arguments, 1, math.MaxInt16, "blend", "<sauce> <peppers>*")
+		if err != nil {
+			return err
+		}
+
+		a0, err := elmo.ToString(elmo.EvalArgument(context, arguments[0]))
+		if err != nil {
+			return elmo.NewInvalidArgumentErrorValue("blend", 1, err)
+		}
+
+		a1 := make([]string, 0, argLen-1)
+		for i := 1; i < argLen; i++ {
+			value, err := elmo.ToString(elmo.EvalArgument(context, arguments[i]))
+			if err != nil {
+				return elmo.NewInvalidArgumentErrorValue("blend", i+1, err)
+			}
+			a1 = append(a1, value)
+		}
+
+		r0 := peppers.Blend(a0, a1...)
+		return elmo.NewStringLiteral(string(r0))
+	})
+}
+
+func bindDescribe() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("describe", "Describe describes a pepper", func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		_, err := elmo.CheckArguments(arguments, 1, 1, "describe", "<pepper>")
+		if err != nil {
+			return err
+		}
+
+		var a0 peppers.Pepper
+		if err := elmo.DecodeArgument(context, arguments[0], &a0); err != nil {
+			return elmo.NewInvalidArgumentErrorValue("describe", 1, err)
+		}
+
+		r0 := peppers.Describe(a0)
+		return elmo.NewStringLiteral(string(r0))
+	})
+}
+
+func bindDilute() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("dilute", "Dilute makes a sauce less hot", func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		_, err := elmo.CheckArguments(arguments, 2, 2, "dilute", "<hotness> <times>")
+		if err != nil {
+			return err
+		}
+
+		a0, err := elmo.ToFloat(elmo.EvalArgument(context, arguments[0]))
+		if err != nil {
+			return elmo.NewInvalidArgumentErrorValue("dilute", 1, err)
+		}
+
+		a1Value, err := elmo.ToInt(elmo.EvalArgument(context, arguments[1]), 8)
+		if err != nil {
+			return elmo.NewInvalidArgumentErrorValue("dilute", 2, err)
+		}
+		a1 := int8(a1Value)
+
+		r0 := peppers.Dilute(a0, a1)
+		return elmo.NewFloatLiteral(float64(r0))
+	})
+}
+
+func bindHotness() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("hotness", "Hotness returns the hotness of a known pepper", func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		_, err := elmo.CheckArguments(arguments, 1, 1, "hotness", "<name>")
+		if err != nil {
+			return err
+		}
+
+		a0, err := elmo.ToString(elmo.EvalArgument(context, arguments[0]))
+		if err != nil {
+			return elmo.NewInvalidArgumentErrorValue("hotness", 1, err)
+		}
+
+		r0, resultErr := peppers.Hotness(a0)
+		if resultErr != nil {
+			return elmo.NewErrorValue(resultErr.Error())
+		}
+		return elmo.NewIntegerLiteral(int64(r0))
+	})
+}
+
+func bindKnown() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("known", "Known checks if a pepper is known, unless the caller has given up", func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		_, err := elmo.CheckArguments(arguments, 1, 1, "known", "<name>")
+		if err != nil {
+			return err
+		}
+
+		a0, err := elmo.ToString(elmo.EvalArgument(context, arguments[0]))
+		if err != nil {
+			return elmo.NewInvalidArgumentErrorValue("known", 1, err)
+		}
+
+		r0 := peppers.Known(context.Context(), a0)
+		return elmo.TrueOrFalse(bool(r0))
+	})
+}
+
+func bindNew() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("new", "New creates a pepper", func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		_, err := elmo.CheckArguments(arguments, 2, 2, "new", "<name> <hotness>")
+		if err != nil {
+			return err
+		}
+
+		a0, err := elmo.ToString(elmo.EvalArgument(context, arguments[0]))
+		if err != nil {
+			return elmo.NewInvalidArgumentErrorValue("new", 1, err)
+		}
+
+		a1Value, err := elmo.ToUint(elmo.EvalArgument(context, arguments[1]), 32)
+		if err != nil {
+			return elmo.NewInvalidArgumentErrorValue("new", 2, err)
+		}
+		a1 := peppers.Scoville(a1Value)
+
+		r0 := peppers.New(a0, a1)
+		return elmo.EncodeValue(r0)
+	})
+}
+
+func bindSplit() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("split", "Split divides peppers over two dishes", func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		_, err := elmo.CheckArguments(arguments, 1, 1, "split", "<peppers>")
+		if err != nil {
+			return err
+		}
+
+		a0Value, err := elmo.ToInt(elmo.EvalArgument(context, arguments[0]), 64)
+		if err != nil {
+			return elmo.NewInvalidArgumentErrorValue("split", 1, err)
+		}
+		a0 := int(a0Value)
+
+		r0, r1 := peppers.Split(a0)
+		return elmo.NewReturnValue([]elmo.Value{elmo.NewIntegerLiteral(int64(r0)), elmo.NewIntegerLiteral(int64(r1))})
+	})
+}
+
+// functions that could not be bound:
+//
+//   Grind (unsupported type func(string) string)
@@ -11,6 +11,7 @@ func initModule(context elmo.RunContext) elmo.Value {
 		_new(),
 		send(),
 		receive(),
+		messages(),
 		current()})
 }
 
@@ -43,7 +44,8 @@ func _new() elmo.NamedValue {
 		subContext.Set(currentActorKey, actor)
 
 		// run block in its own context as a go routine
-		// so we get concurrent execution
+		// so we get concurrent execution. The actor shares
+		// cancellation and limits with the code creating it
 		//
 		go block.Value().(elmo.Block).Run(subContext, elmo.NoArguments)
 
@@ -72,13 +74,11 @@ func send() elmo.NamedValue {
 
 		actualActor := resolvedActor.Internal().(Actor)
 		if argLen == 1 {
-			actualActor.Send(elmo.True)
-		} else {
-			message := elmo.EvalArgument(context, arguments[1])
-			actualActor.Send(message)
+			return actualActor.Send(context.Context(), elmo.True)
 		}
 
-		return elmo.Nothing
+		message := elmo.EvalArgument(context, arguments[1])
+		return actualActor.Send(context.Context(), message)
 	})
 }
 
@@ -96,7 +96,37 @@ func receive() elmo.NamedValue {
 			return elmo.NewErrorValue("invalid call to actor.receive, not in an actor context. usage: receive")
 		}
 
-		return actor.Internal().(Actor).Receive()
+		return actor.Internal().(Actor).Receive(context.Context())
+	})
+}
+
+func messages() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("messages", `iterate over messages that are send to actor
+	usage: messages
+	Returns an iterable that receives the next message whenever a value is needed.
+	The iterable never ends by itself, so take a limited number of messages
+	or stop iterating when receiving some final message
+	`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		_, err := elmo.CheckArguments(arguments, 0, 0, "messages", "")
+		if err != nil {
+			return err
+		}
+
+		actor, found := context.Get(currentActorKey)
+
+		if !found {
+			return elmo.NewErrorValue("invalid call to actor.messages, not in an actor context. usage: messages")
+		}
+
+		actualActor := actor.Internal().(Actor)
+		ctx := context.Context()
+
+		return elmo.NewIterableValue(func() elmo.Iterator {
+			return elmo.NewIterator(func() (elmo.Value, bool) {
+				return actualActor.Receive(ctx), true
+			}, nil)
+		})
 	})
 }
 
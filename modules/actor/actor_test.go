@@ -0,0 +1,112 @@
+package actor
+
+import (
+	"testing"
+
+	elmo "github.com/okke/elmo/core"
+	dict "github.com/okke/elmo/modules/dictionary"
+	"github.com/okke/elmo/modules/list"
+)
+
+func actorContext() elmo.RunContext {
+	context := elmo.NewGlobalContext()
+	context.RegisterModule(Module)
+	context.RegisterModule(list.Module)
+	context.RegisterModule(dict.Module)
+	return context
+}
+
+func TestSendAndReceive(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, actorContext(),
+		`actor: (load "actor")
+		 list: (load "list")
+		 received: []
+		 a: (actor.new {
+		   list.append! received (actor.receive)
+		   actor.receive
+		 })
+		 actor.send $a "chipotle"
+		 actor.send $a
+		 $received`, elmo.ExpectValue(t, elmo.NewListValue([]elmo.Value{elmo.NewStringLiteral("chipotle")})))
+}
+
+func TestIterateOverMessages(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, actorContext(),
+		`actor: (load "actor")
+		 list: (load "list")
+		 received: []
+		 a: (actor.new {
+		   list.each (list.take (actor.messages) 2) message {
+		     list.append! received $message
+		   }
+		   actor.receive
+		 })
+		 actor.send $a "chipotle"
+		 actor.send $a "jalapeno"
+		 actor.send $a
+		 $received`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), `["chipotle" "jalapeno"]`)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, actorContext(),
+		`actor: (load "actor")
+		 actor.messages`, elmo.ExpectErrorValueAt(t, 2))
+}
+
+// workers creates a script that runs four actors on some shared value. Actors wait
+// for a first message to start working at the same time and a second one to
+// signal they're done, meanwhile the main script can do some work as well.
+// Run with -race to detect unsafe access to shared values
+//
+func workers(shared string, work string, meanwhile string, result string) string {
+	return `actor: (load "actor")
+	 list: (load "list")
+	 dict: (load "dict")
+	 shared: ` + shared + `
+	 worker: (func name {
+	   return (actor.new {
+	     actor.receive
+	     i: 0
+	     while (lt $i 50) {
+	       ` + work + `
+	       i: (incr $i)
+	     }
+	     actor.receive
+	   })
+	 })
+	 a: (worker "chipotle")
+	 b: (worker "jalapeno")
+	 c: (worker "habanero")
+	 d: (worker "serrano")
+	 actor.send $a
+	 actor.send $b
+	 actor.send $c
+	 actor.send $d
+	 ` + meanwhile + `
+	 actor.send $a
+	 actor.send $b
+	 actor.send $c
+	 actor.send $d
+	 ` + result
+}
+
+func TestActorsShareDictionaries(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, actorContext(),
+		workers("{}", "dict.set! $shared $name $i", "", "[(len (dict.keys $shared)) $shared.serrano]"),
+		elmo.ExpectValue(t, elmo.NewListValue([]elmo.Value{elmo.NewIntegerLiteral(4), elmo.NewIntegerLiteral(49)})))
+}
+
+func TestActorsShareLists(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, actorContext(),
+		workers("[]", "list.append! shared $name", "", "len $shared"),
+		elmo.ExpectValue(t, elmo.NewIntegerLiteral(200)))
+}
+
+func TestActorsShareContexts(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, actorContext(),
+		workers("0", "peppers: $shared", "while (lt $shared 50) { shared: (incr $shared) }", "$shared"),
+		elmo.ExpectValue(t, elmo.NewIntegerLiteral(50)))
+}
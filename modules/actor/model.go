@@ -1,6 +1,7 @@
 package actor
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/okke/elmo/core"
@@ -14,20 +15,37 @@ type actor struct {
 
 // Actor interacts with elmo actors
 //
+// Send and Receive block until the message is delivered or given
+// context is done
+//
 type Actor interface {
-	Send(elmo.Value)
-	Receive() elmo.Value
+	Send(ctx context.Context, value elmo.Value) elmo.Value
+	Receive(ctx context.Context) elmo.Value
+}
+
+func cancelled(ctx context.Context) elmo.Value {
+	return elmo.NewAbortErrorValue(fmt.Sprintf("execution cancelled: %v", ctx.Err()))
 }
 
-func (actor *actor) Send(value elmo.Value) {
+func (actor *actor) Send(ctx context.Context, value elmo.Value) elmo.Value {
 	if freezable, ok := value.(elmo.FreezableValue); ok {
 		freezable.Freeze()
 	}
-	actor.channel <- value
+	select {
+	case actor.channel <- value:
+		return elmo.Nothing
+	case <-ctx.Done():
+		return cancelled(ctx)
+	}
 }
 
-func (actor *actor) Receive() elmo.Value {
-	return <-actor.channel
+func (actor *actor) Receive(ctx context.Context) elmo.Value {
+	select {
+	case value := <-actor.channel:
+		return value
+	case <-ctx.Done():
+		return cancelled(ctx)
+	}
 }
 
 func (actor *actor) String() string {
@@ -62,7 +62,7 @@ func toValue() elmo.NamedValue {
 
 			binary := value.(elmo.BinaryValue)
 
-			return binary.ToRegular()
+			return binary.ToRegularWithinContext(context)
 
 		})
 }
@@ -1,10 +1,15 @@
 package data
 
 import (
+	"bytes"
 	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"strings"
 
+	"github.com/google/uuid"
 	elmo "github.com/okke/elmo/core"
 )
 
@@ -36,6 +41,7 @@ func convertCSVStringToListOfDictionaries(in string) elmo.Value {
 		}
 
 		mapping := make(map[string]elmo.Value, 0)
+		keys := make([]string, 0, len(header))
 		for fieldIndex, fieldValue := range record {
 			if fieldIndex >= len(header) {
 				continue
@@ -43,10 +49,11 @@ func convertCSVStringToListOfDictionaries(in string) elmo.Value {
 
 			fieldName := header[fieldIndex]
 			if fieldName != "" {
-				mapping[header[fieldIndex]] = elmo.ConvertStringToValue(fieldValue)
+				mapping[fieldName] = elmo.ConvertStringToValue(fieldValue)
+				keys = append(keys, fieldName)
 			}
 		}
-		list = append(list, elmo.NewDictionaryValue(nil, mapping))
+		list = append(list, elmo.NewOrderedDictionaryValue(nil, keys, mapping))
 	}
 
 	return elmo.NewListValue(list)
@@ -54,19 +61,165 @@ func convertCSVStringToListOfDictionaries(in string) elmo.Value {
 
 func convertJSONStringToDictionary(in string) elmo.Value {
 
-	jsonMap := make(map[string]interface{})
-	if err := json.Unmarshal([]byte(in), &jsonMap); err != nil {
+	decoder := json.NewDecoder(strings.NewReader(in))
+
+	token, err := decoder.Token()
+	if err == nil && token != json.Delim('{') {
+		err = errors.New("json: cannot unmarshal non object into a dictionary")
+	}
+
+	var value elmo.Value
+	if err == nil {
+		value, err = decodeJSONObject(decoder)
+	}
+	if err == nil {
+		if _, trailing := decoder.Token(); trailing != io.EOF {
+			err = errors.New("invalid character after top-level value")
+		}
+	}
+
+	if err != nil {
 		return elmo.NewErrorValue(err.Error())
 	}
+	return value
+}
+
+// decodeJSONObject decodes the members of a json object into a dictionary
+// that keeps the order of the members
+//
+func decodeJSONObject(decoder *json.Decoder) (elmo.Value, error) {
+	mapping := make(map[string]elmo.Value)
+	keys := []string{}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := token.(string)
+
+		value, err := decodeJSONValue(decoder)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, found := mapping[key]; !found {
+			keys = append(keys, key)
+		}
+		mapping[key] = value
+	}
+
+	// consume closing }
+	//
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	return elmo.NewOrderedDictionaryValue(nil, keys, mapping), nil
+}
+
+func decodeJSONValue(decoder *json.Decoder) (elmo.Value, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
 
-	return elmo.ConvertMapToValue(jsonMap)
+	switch token {
+	case json.Delim('{'):
+		return decodeJSONObject(decoder)
+	case json.Delim('['):
+		values := []elmo.Value{}
+		for decoder.More() {
+			value, err := decodeJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return elmo.NewListValue(values), nil
+	}
+
+	return elmo.ConvertAnyToValue(token), nil
+}
+
+func convertValueToJSONString(in elmo.Value) (string, error) {
+	var buffer bytes.Buffer
+	if err := encodeJSON(&buffer, make(map[uuid.UUID]bool), in); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
 }
 
-func convertValueToJSONString(in elmo.Value) string {
-	converted := elmo.ConvertValueToInterface(in, elmo.TypeGoFunction, elmo.TypeBlock)
-	bytes, err := json.Marshal(converted)
+// encodeJSON writes a value as json. Dictionaries are written with their keys
+// in insertion order, functions and blocks are left out. Keys are written as
+// strings so dictionaries with keys like 1 and "1" can not be written
+//
+func encodeJSON(buffer *bytes.Buffer, visited map[uuid.UUID]bool, in elmo.Value) error {
+
+	switch in.Type() {
+	case elmo.TypeGoFunction, elmo.TypeBlock:
+		buffer.WriteString("null")
+		return nil
+	case elmo.TypeDictionary, elmo.TypeList:
+		if visited[in.UUID()] {
+			buffer.WriteString("{}")
+			return nil
+		}
+		visited[in.UUID()] = true
+		defer delete(visited, in.UUID())
+	}
+
+	switch in.Type() {
+	case elmo.TypeDictionary:
+		dict := in.(elmo.DictionaryValue)
+		buffer.WriteByte('{')
+		written := false
+		keys := map[string]bool{}
+		for _, key := range dict.KeyValues() {
+			value, _ := dict.ResolveKey(key)
+			if value.Type() == elmo.TypeGoFunction || value.Type() == elmo.TypeBlock {
+				continue
+			}
+			if written {
+				buffer.WriteByte(',')
+			}
+			written = true
+
+			if keys[key.String()] {
+				return fmt.Errorf("can not convert dictionary to json, key %s is used more than once", key.String())
+			}
+			keys[key.String()] = true
+
+			encodedKey, _ := json.Marshal(key.String())
+			buffer.Write(encodedKey)
+			buffer.WriteByte(':')
+			if err := encodeJSON(buffer, visited, value); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte('}')
+		return nil
+	case elmo.TypeList, elmo.TypeSet:
+		buffer.WriteByte('[')
+		for i, value := range in.(interface{ List() []elmo.Value }).List() {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			if err := encodeJSON(buffer, visited, value); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte(']')
+		return nil
+	}
+
+	encoded, err := json.Marshal(elmo.ConvertValueToInterface(in))
 	if err != nil {
-		return err.Error()
+		return err
 	}
-	return string(bytes)
+	buffer.Write(encoded)
+	return nil
 }
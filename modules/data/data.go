@@ -134,7 +134,16 @@ func toJSON() elmo.NamedValue {
 				return err
 			}
 
-			return elmo.NewStringLiteral(convertValueToJSONString(elmo.EvalArgument(context, arguments[0])))
+			value := elmo.EvalArgument(context, arguments[0])
+			if value.Type() == elmo.TypeError {
+				return value
+			}
+
+			json, err := convertValueToJSONString(value)
+			if err != nil {
+				return elmo.NewErrorValue(err.Error())
+			}
+			return elmo.NewStringLiteral(json)
 
 		})
 }
@@ -25,3 +25,48 @@ func TestConvertToJSON(t *testing.T) {
 func TestConvertToCSV(t *testing.T) {
 	elmo.TestMoFile(t, "to_csv", initTestContext)
 }
+
+func TestConvertSetToJSON(t *testing.T) {
+	context := elmo.NewGlobalContext()
+	initTestContext(context)
+
+	set, _ := elmo.NewSetValue([]elmo.Value{elmo.NewStringLiteral("chipotle"), elmo.NewIntegerLiteral(3)})
+	context.Set("peppers", set)
+
+	elmo.ParseTestAndRunBlockWithinContext(t, context,
+		`data: (load data)
+		 data.toJSON $peppers`, elmo.ExpectValue(t, elmo.NewStringLiteral(`["chipotle",3]`)))
+}
+
+func TestConvertRecordToJSONAndBack(t *testing.T) {
+	context := elmo.NewGlobalContext()
+	initTestContext(context)
+
+	elmo.ParseTestAndRunBlockWithinContext(t, context,
+		`data: (load data)
+		 deftype Point {x:int y:int}
+		 json: (data.toJSON (Point 1 2))
+		 p: (Point ...(data.fromJSON $json))
+		 [$json (type $p) $p.y]`, elmo.ExpectValue(t, elmo.NewListValue([]elmo.Value{
+			elmo.NewStringLiteral(`{"x":1,"y":2}`), elmo.NewIdentifier("Point"), elmo.NewIntegerLiteral(2)})))
+}
+
+func TestConvertDictionaryWithCollidingKeysToJSON(t *testing.T) {
+	peppersContext := func() elmo.RunContext {
+		context := elmo.NewGlobalContext()
+		initTestContext(context)
+		context.Set("peppers", elmo.NewDictionaryFromList(nil, []elmo.Value{
+			elmo.NewIntegerLiteral(1), elmo.NewStringLiteral("chipotle"),
+			elmo.NewStringLiteral("1"), elmo.NewStringLiteral("jalapeno")}))
+		return context
+	}
+
+	elmo.ParseTestAndRunBlockWithinContext(t, peppersContext(),
+		`data: (load data)
+		 data.toJSON $peppers`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, peppersContext(),
+		`data: (load data)
+		 hot: {peppers: $peppers}
+		 data.toJSON $hot`, elmo.ExpectErrorValueAt(t, 3))
+}
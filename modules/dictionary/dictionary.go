@@ -44,9 +44,9 @@ func new() elmo.NamedValue {
 
 		switch dictValues.Type() {
 		case elmo.TypeBlock:
-			return elmo.NewDictionaryValue(parent, elmo.NewDictionaryWithBlock(context, dictValues.(elmo.Block)).Internal().(map[string]elmo.Value))
+			return copyDictionary(parent, elmo.NewDictionaryWithBlock(context, dictValues.(elmo.Block)))
 		case elmo.TypeDictionary:
-			return elmo.NewDictionaryValue(parent, dictValues.Internal().(map[string]elmo.Value))
+			return copyDictionary(parent, dictValues.(elmo.DictionaryValue))
 		case elmo.TypeList:
 			return elmo.NewDictionaryFromList(parent, dictValues.Internal().([]elmo.Value))
 		}
@@ -56,18 +56,47 @@ func new() elmo.NamedValue {
 	})
 }
 
+// copyDictionary creates a new dictionary with the keys and values of
+// given dictionary
+//
+func copyDictionary(parent elmo.Value, dict elmo.DictionaryValue) elmo.Value {
+	keys := dict.KeyValues()
+	pairs := make([]elmo.Value, 0, 2*len(keys))
+	for _, key := range keys {
+		value, _ := dict.ResolveKey(key)
+		pairs = append(pairs, key, value)
+	}
+	return elmo.NewDictionaryFromList(parent, pairs)
+}
+
 func keys() elmo.NamedValue {
-	return elmo.NewGoFunctionWithHelp("keys", `get all keys in a dictionary
-	usage keys < dictionary>
+	return elmo.NewGoFunctionWithKeywords("keys", `get all keys in a dictionary
+	usage keys <dictionary> sorted=<boolean>?
 
-	keys are returned as sorted list of identifiers
-	`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+	keys are returned in the order in which they were added to the dictionary,
+	or in alphabetical order when sorted=true. string keys are returned as
+	identifiers, all other keys as the value they were added with
+	`, []string{"sorted"}, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
 
-		_, err := elmo.CheckArguments(arguments, 1, 1, "keys", "<dictionary>")
+		arguments, keywords := elmo.SplitArguments(arguments)
+
+		_, err := elmo.CheckArguments(arguments, 1, 1, "keys", "<dictionary> sorted=<boolean>?")
 		if err != nil {
 			return err
 		}
 
+		sorted := false
+		for keyword, argument := range keywords {
+			if keyword != "sorted" {
+				return elmo.NewErrorValue(fmt.Sprintf("invalid call to keys, unknown option %s", keyword))
+			}
+			value := elmo.EvalArgument(context, argument)
+			if value.Type() != elmo.TypeBoolean && value.Type() != elmo.TypeIdentifier {
+				return elmo.NewErrorValue("invalid call to keys, sorted should be true or false")
+			}
+			sorted = value.String() == "true"
+		}
+
 		// first argument of a dictionary function can be an identifier with the name of the dictionary
 		//
 		dict, ok := elmo.EvalArgumentOrSolveIdentifier(context, arguments[0]).(elmo.DictionaryValue)
@@ -76,13 +105,17 @@ func keys() elmo.NamedValue {
 			return elmo.NewErrorValue("invalid call to keys, expect a dictionary as first argument: usage keys <dictionary>")
 		}
 
-		keyNames := dict.Keys()
-		keys := make([]elmo.Value, len(keyNames))
-
-		sort.Strings(keyNames)
+		keys := dict.KeyValues()
+		if sorted {
+			sort.SliceStable(keys, func(i, j int) bool {
+				return keys[i].String() < keys[j].String()
+			})
+		}
 
-		for i, k := range keyNames {
-			keys[i] = elmo.NewIdentifier(k)
+		for i, k := range keys {
+			if k.Type() == elmo.TypeString {
+				keys[i] = elmo.NewIdentifier(k.String())
+			}
 		}
 
 		return elmo.NewListValue(keys)
@@ -105,7 +138,7 @@ func knowsOrGet(name string, context elmo.RunContext, arguments []elmo.Argument)
 
 	key := elmo.EvalArgument(context, arguments[1])
 
-	return dict.Resolve(key.String())
+	return dict.ResolveKey(key)
 
 }
 
@@ -174,7 +207,14 @@ func merge() elmo.NamedValue {
 			}
 		}
 
-		return dictionaries[0].Merge(dictionaries[1:])
+		merged := dictionaries[0].Merge(dictionaries[1:])
+		if dict, ok := merged.(elmo.DictionaryValue); ok {
+			if err := elmo.CheckCollectionSize(context, len(dict.Keys())); err != nil {
+				return err
+			}
+		}
+
+		return merged
 
 	})
 }
@@ -199,6 +239,10 @@ func set() elmo.NamedValue {
 			return err
 		}
 
+		if err := elmo.CheckCollectionSize(context, len(dict.Keys())); err != nil {
+			return err
+		}
+
 		return dict.(elmo.Value)
 	})
 }
@@ -40,7 +40,12 @@ func TestNewWithoutParent(t *testing.T) {
 	elmo.ParseTestAndRunBlockWithinContext(t, dictContext(),
 		`d: (load "dict")
 		 h: (d.new ["1" 2 3 4])
-	   h 1`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(2)))
+	   h "1"`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(2)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, dictContext(),
+		`d: (load "dict")
+		 h: (d.new ["1" 2 3 4])
+	   h 1`, elmo.ExpectNothing(t))
 
 	elmo.ParseTestAndRunBlockWithinContext(t, dictContext(),
 		`d: (load "dict")
@@ -179,7 +184,7 @@ func TestKeys(t *testing.T) {
     }
 		d.keys $peppers`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), "[chipotle galapeno]")))
 
-	// keys function should return keys in sorted order
+	// keys function should return keys in insertion order
 	//
 	elmo.ParseTestAndRunBlockWithinContext(t, dictContext(),
 		`d: (load "dict")
@@ -191,7 +196,33 @@ func TestKeys(t *testing.T) {
         heat: 2
       }
     }
-    d.keys $peppers`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), "[chipotle galapeno]")))
+    d.keys $peppers`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), "[galapeno chipotle]")))
+
+	// or in sorted order when asked for
+	//
+	elmo.ParseTestAndRunBlockWithinContext(t, dictContext(),
+		`d: (load "dict")
+    peppers: {
+      galapeno: {
+        heat: 3
+      }
+      chipotle: {
+        heat: 2
+      }
+    }
+    d.keys $peppers sorted=true`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), "[chipotle galapeno]")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, dictContext(),
+		`d: (load "dict")
+    peppers: {}
+    d.keys $peppers reversed=true`, elmo.ExpectErrorValueAt(t, 3))
+
+	// keys that are not strings are returned as they were added
+	//
+	elmo.ParseTestAndRunBlockWithinContext(t, dictContext(),
+		`d: (load "dict")
+    peppers: (d.new [chipotle 2 3 "three" (freeze! [1 2]) "pair"])
+    d.keys $peppers`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), "[chipotle 3 (freeze! [1 2])]")))
 
 }
 
@@ -315,6 +346,17 @@ func TestSet(t *testing.T) {
 		 d.set! $peppers a "chipotle"
 		 peppers.a`, elmo.ExpectValue(t, elmo.NewStringLiteral("chipotle")))
 
+	elmo.ParseTestAndRunBlockWithinContext(t, dictContext(),
+		`d: (load "dict")
+		 peppers: {}
+		 d.set! $peppers 1 "chipotle"
+		 [(d.knows $peppers 1) (d.knows $peppers "1")]`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), "[$true $false]")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, dictContext(),
+		`d: (load "dict")
+		 peppers: {}
+		 d.set! $peppers [1 2] "chipotle"`, elmo.ExpectErrorValueAt(t, 3))
+
 	elmo.ParseTestAndRunBlockWithinContext(t, dictContext(),
 		`d: (load "dict")
  		 peppers: {
@@ -2,17 +2,23 @@ package elmohttp
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
+	"time"
 
 	elmo "github.com/okke/elmo/core"
 )
 
 var typeInfoHTTPClient = elmo.NewTypeInfo("httpClient")
 
+// maxRedirects is the number of redirects a client follows, like
+// the default of http clients
+const maxRedirects = 10
+
 type httpClient struct {
 	client  *http.Client
 	baseUrl *url.URL
@@ -20,12 +26,19 @@ type httpClient struct {
 }
 
 type HTTPClient interface {
-	DoRequest(method string, url string, body []byte) elmo.Value
+	DoRequest(ctx context.Context, method string, url string, body []byte) elmo.Value
 	Cookies() []*http.Cookie
 	SetHeaders(map[string]string)
 }
 
 func NewHTTPClient(baseUrl string) (HTTPClient, elmo.ErrorValue) {
+	return newHTTPClient(baseUrl, nil)
+}
+
+// newHTTPClient creates a client that only follows redirects to hosts
+// permitted by given policy. Without policy all redirects are followed
+//
+func newHTTPClient(baseUrl string, policy *elmo.Policy) (HTTPClient, elmo.ErrorValue) {
 	jar, err := cookiejar.New(nil)
 	if err != nil {
 		return nil, elmo.NewErrorValue(err.Error())
@@ -35,8 +48,25 @@ func NewHTTPClient(baseUrl string) (HTTPClient, elmo.ErrorValue) {
 	if err != nil {
 		return nil, elmo.NewErrorValue(err.Error())
 	}
-	return &httpClient{baseUrl: url, client: &http.Client{
-		Jar: jar}}, nil
+	client := &http.Client{Jar: jar}
+	if policy != nil {
+		client.CheckRedirect = checkRedirect(policy)
+	}
+	return &httpClient{baseUrl: url, client: client}, nil
+}
+
+// checkRedirect checks the host of every redirect against given policy
+//
+func checkRedirect(policy *elmo.Policy) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if !policy.AllowsHost(req.URL.Host) {
+			return fmt.Errorf("redirecting to %s is not permitted", req.URL.Host)
+		}
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return nil
+	}
 }
 
 func (httpClient *httpClient) String() string {
@@ -53,9 +83,38 @@ func (httpClient *httpClient) addHeaders(req *http.Request) {
 	}
 }
 
-func (httpClient *httpClient) DoRequest(method, url string, body []byte) elmo.Value {
+// requestError converts a failed request into an error, requests
+// failing due to cancellation stop the execution of the script
+//
+func requestError(ctx context.Context, err error) elmo.Value {
+	if ctx.Err() != nil {
+		return elmo.NewAbortErrorValue(fmt.Sprintf("execution cancelled: %v", ctx.Err()))
+	}
+	return elmo.NewErrorValue(err.Error())
+}
+
+// requestWithTimeout executes a request that is cancelled when it takes longer
+// than given timeout. Unlike cancellation of the execution, a timeout
+// results in an error that does not stop the script
+//
+func requestWithTimeout(ctx context.Context, timeout time.Duration, request func(ctx context.Context) elmo.Value) elmo.Value {
+	if timeout <= 0 {
+		return request(ctx)
+	}
+
+	requestCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	req, err := http.NewRequest(method, httpClient.baseUrl.String()+url, bytes.NewBuffer(body))
+	result := request(requestCtx)
+	if ctx.Err() == nil && requestCtx.Err() != nil {
+		return elmo.NewErrorValue(fmt.Sprintf("request timed out after %v", timeout))
+	}
+	return result
+}
+
+func (httpClient *httpClient) DoRequest(ctx context.Context, method, url string, body []byte) elmo.Value {
+
+	req, err := http.NewRequestWithContext(ctx, method, httpClient.baseUrl.String()+url, bytes.NewBuffer(body))
 	if err != nil {
 		return elmo.NewErrorValue(err.Error())
 	}
@@ -64,13 +123,13 @@ func (httpClient *httpClient) DoRequest(method, url string, body []byte) elmo.Va
 
 	resp, err := httpClient.client.Do(req)
 	if err != nil {
-		return elmo.NewErrorValue(err.Error())
+		return requestError(ctx, err)
 	}
 
 	defer resp.Body.Close()
 	responseBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return elmo.NewErrorValue(err.Error())
+		return requestError(ctx, err)
 	}
 
 	httpClient.client.Jar.SetCookies(httpClient.baseUrl, resp.Cookies())
@@ -1,7 +1,11 @@
 package elmohttp
 
 import (
+	gocontext "context"
+	"fmt"
 	"net/http"
+	"net/url"
+	"time"
 
 	elmo "github.com/okke/elmo/core"
 )
@@ -36,7 +40,11 @@ func client() elmo.NamedValue {
 		}
 
 		url := elmo.EvalArgument2String(context, arguments[0])
-		client, err := NewHTTPClient(url)
+		if err := checkHost(context, url); err != nil {
+			return err
+		}
+
+		client, err := newHTTPClient(url, context.Policy())
 		if err != nil {
 			return err
 		}
@@ -45,6 +53,25 @@ func client() elmo.NamedValue {
 	})
 }
 
+// checkHost checks if a sandbox permits connecting to the host of given url
+//
+func checkHost(context elmo.RunContext, rawURL string) elmo.ErrorValue {
+	policy := context.Policy()
+	if policy == nil {
+		return nil
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return elmo.NewErrorValue(err.Error())
+	}
+
+	if !policy.AllowsHost(parsed.Host) {
+		return elmo.NewNotPermittedErrorValue(fmt.Sprintf("connecting to %s", parsed.Host))
+	}
+	return nil
+}
+
 func getPath(context elmo.RunContext, pathArg elmo.Argument, parametersArg elmo.Argument) (string, elmo.ErrorValue) {
 	path := ""
 
@@ -78,11 +105,46 @@ func getPathAndParamatersArg(arguments []elmo.Argument, pathArgNo int, parameter
 	return pathArg, parametersArg
 }
 
+// requestOptions takes the options of a request from the keyword arguments
+// of a call. Currently only a timeout (in milliseconds) is supported
+//
+func requestOptions(context elmo.RunContext, fname string, keywords map[string]elmo.Argument) (time.Duration, elmo.ErrorValue) {
+	var timeout time.Duration
+
+	for keyword, argument := range keywords {
+		if keyword != "timeout" {
+			return 0, elmo.NewErrorValue(fmt.Sprintf("invalid call to http.%s, unknown option %s", fname, keyword))
+		}
+
+		value := elmo.EvalArgument(context, argument)
+		if value.Type() != elmo.TypeInteger {
+			return 0, elmo.NewErrorValue(fmt.Sprintf("invalid call to http.%s, timeout should be a number of milliseconds", fname))
+		}
+		timeout = time.Duration(value.Internal().(int64)) * time.Millisecond
+	}
+
+	return timeout, nil
+}
+
+func doRequest(context elmo.RunContext, client elmo.Value, timeout time.Duration, method string, path string, body []byte) elmo.Value {
+	return requestWithTimeout(context.Context(), timeout, func(ctx gocontext.Context) elmo.Value {
+		return client.Internal().(HTTPClient).DoRequest(ctx, method, path, body)
+	})
+}
+
 func get() elmo.NamedValue {
-	return elmo.NewGoFunctionWithHelp("get", `executes an HTTP GET request on an http client
-	`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+	return elmo.NewGoFunctionWithKeywords("get", `executes an HTTP GET request on an http client
+	usage: get <client> <path>? <parameters>? timeout=<milliseconds>?
+	`, []string{"timeout"}, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		arguments, keywords := elmo.SplitArguments(arguments)
 
-		_, err := elmo.CheckArguments(arguments, 1, 3, "get", "<client> <path>? <parameters>?")
+		_, err := elmo.CheckArguments(arguments, 1, 3, "get", "<client> <path>? <parameters>? timeout=<milliseconds>?")
+		if err != nil {
+			return err
+		}
+
+		timeout, err := requestOptions(context, "get", keywords)
 		if err != nil {
 			return err
 		}
@@ -103,7 +165,11 @@ func get() elmo.NamedValue {
 			return err
 		}
 
-		return client.Internal().(HTTPClient).DoRequest("GET", path, nil)
+		if err := checkHost(context, client.String()+path); err != nil {
+			return err
+		}
+
+		return doRequest(context, client, timeout, "GET", path, nil)
 	})
 }
 
@@ -111,7 +177,14 @@ func postOrPut(method HTTPMethod) func(context elmo.RunContext, arguments []elmo
 
 	return func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
 
-		_, err := elmo.CheckArguments(arguments, 2, 4, elmoHTTPMethods[method], "<client> <body> <path>? <parameters>?")
+		arguments, keywords := elmo.SplitArguments(arguments)
+
+		_, err := elmo.CheckArguments(arguments, 2, 4, elmoHTTPMethods[method], "<client> <body> <path>? <parameters>? timeout=<milliseconds>?")
+		if err != nil {
+			return err
+		}
+
+		timeout, err := requestOptions(context, elmoHTTPMethods[method], keywords)
 		if err != nil {
 			return err
 		}
@@ -136,18 +209,24 @@ func postOrPut(method HTTPMethod) func(context elmo.RunContext, arguments []elmo
 			return err
 		}
 
-		return client.Internal().(HTTPClient).DoRequest(httpMethods[method], path, body)
+		if err := checkHost(context, client.String()+path); err != nil {
+			return err
+		}
+
+		return doRequest(context, client, timeout, httpMethods[method], path, body)
 	}
 }
 
 func post() elmo.NamedValue {
-	return elmo.NewGoFunctionWithHelp("post", `executes an HTTP POST request on an http client
-	`, postOrPut(HTTP_POST))
+	return elmo.NewGoFunctionWithKeywords("post", `executes an HTTP POST request on an http client
+	usage: post <client> <body> <path>? <parameters>? timeout=<milliseconds>?
+	`, []string{"timeout"}, postOrPut(HTTP_POST))
 }
 
 func put() elmo.NamedValue {
-	return elmo.NewGoFunctionWithHelp("put", `executes an HTTP PUT request on an http client
-	`, postOrPut(HTTP_PUT))
+	return elmo.NewGoFunctionWithKeywords("put", `executes an HTTP PUT request on an http client
+	usage: put <client> <body> <path>? <parameters>? timeout=<milliseconds>?
+	`, []string{"timeout"}, postOrPut(HTTP_PUT))
 }
 
 func cookies() elmo.NamedValue {
@@ -198,12 +277,12 @@ func setHeaders() elmo.NamedValue {
 
 		headerDict := headerArg.(elmo.DictionaryValue)
 
-		headerKeys := headerDict.Keys()
+		headerKeys := headerDict.KeyValues()
 
 		headers := make(map[string]string, len(headerKeys))
 		for _, k := range headerKeys {
-			v, _ := headerDict.Resolve(k)
-			headers[k] = v.String()
+			v, _ := headerDict.ResolveKey(k)
+			headers[k.String()] = v.String()
 		}
 
 		client.Internal().(HTTPClient).SetHeaders(headers)
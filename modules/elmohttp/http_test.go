@@ -1,7 +1,12 @@
 package elmohttp
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
 	elmo "github.com/okke/elmo/core"
 	dict "github.com/okke/elmo/modules/dictionary"
@@ -23,3 +28,109 @@ func TestTestServer(t *testing.T) {
 func TestClient(t *testing.T) {
 	elmo.TestMoFile(t, "client", initTestContext)
 }
+
+func TestClientIsCancelled(t *testing.T) {
+
+	client := elmo.NewGlobalContext()
+	initTestContext(client)
+
+	elmo.ParseTestAndRunBlockWithinContext(t, client,
+		`http: (load http)
+		 server: (http.testServer (func request response {
+		   sleep 10000
+		 }))`)
+
+	server, _ := client.Get("server")
+	defer server.Internal().(HTTPTestServer).Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	client.SetContext(ctx)
+
+	elmo.ParseTestAndRunBlockWithinContext(t, client,
+		`http.get (http.client (http.testURL $server))`, elmo.ExpectAbortErrorValue(t))
+}
+
+func TestClientTimesOut(t *testing.T) {
+
+	slow := elmo.NewGlobalContext()
+	initTestContext(slow)
+
+	elmo.ParseTestAndRunBlockWithinContext(t, slow,
+		`http: (load http)
+		 server: (http.testServer (func request response {
+		   sleep 500
+		 }))`)
+
+	server, _ := slow.Get("server")
+	defer server.Internal().(HTTPTestServer).Close()
+
+	clientContext := func() elmo.RunContext {
+		client := elmo.NewGlobalContext()
+		initTestContext(client)
+		client.Set("url", elmo.NewStringLiteral(server.Internal().(HTTPTestServer).URL()))
+		return client
+	}
+
+	elmo.ParseTestAndRunBlockWithinContext(t, clientContext(),
+		`http: (load http)
+		 http.get (http.client $url) timeout=50`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, clientContext(),
+		`http: (load http)
+		 http.get (http.client $url) timeout="soon"`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, clientContext(),
+		`http: (load http)
+		 http.get (http.client $url) retries=3`, elmo.ExpectErrorValueAt(t, 2))
+}
+
+func sandboxContext() elmo.RunContext {
+	sandbox := elmo.NewSandboxContext(elmo.Policy{Hosts: []string{"chipotle.org"}})
+	initTestContext(sandbox)
+	return sandbox
+}
+
+func TestClientWithinSandbox(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, sandboxContext(),
+		`http: (load http)
+		 http.client "http://jalapeno.org"`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, sandboxContext(),
+		`http: (load http)
+		 client: (http.client "http://chipotle.org")
+		 http.get $client "@jalapeno.org/"`, elmo.ExpectErrorValueAt(t, 3))
+}
+
+func TestRedirectWithinSandbox(t *testing.T) {
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("chipotle"))
+	}))
+	defer target.Close()
+
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	defer redirect.Close()
+
+	sandbox := func(hosts ...string) elmo.RunContext {
+		for i, host := range hosts {
+			parsed, _ := url.Parse(host)
+			hosts[i] = parsed.Host
+		}
+		sandbox := elmo.NewSandboxContext(elmo.Policy{Hosts: hosts})
+		initTestContext(sandbox)
+		sandbox.Set("url", elmo.NewStringLiteral(redirect.URL))
+		return sandbox
+	}
+
+	elmo.ParseTestAndRunBlockWithinContext(t, sandbox(redirect.URL),
+		`http: (load http)
+		 http.get (http.client $url)`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, sandbox(redirect.URL, target.URL),
+		`http: (load http)
+		 http.get (http.client $url)`, elmo.ExpectValue(t, elmo.NewStringLiteral("chipotle")))
+}
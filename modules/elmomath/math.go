@@ -0,0 +1,529 @@
+package elmomath
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	elmo "github.com/okke/elmo/core"
+)
+
+// Module contains mathematical functions and constants
+//
+var Module = elmo.NewModule("math", initModule)
+
+func initModule(context elmo.RunContext) elmo.Value {
+	mapping := elmo.NewMappingForModule(context, []elmo.NamedValue{
+		floatFunction("sqrt", "square root", math.Sqrt),
+		floatFunction("exp", "e raised to the power of a number", math.Exp),
+		floatFunction("sin", "sine of an angle in radians", math.Sin),
+		floatFunction("cos", "cosine of an angle in radians", math.Cos),
+		floatFunction("tan", "tangent of an angle in radians", math.Tan),
+		floatFunction("asin", "arcsine in radians", math.Asin),
+		floatFunction("acos", "arccosine in radians", math.Acos),
+		floatFunction("atan", "arctangent in radians", math.Atan),
+		pow(),
+		log(),
+		roundingFunction("floor", "largest integer less than or equal to a number", floorRat),
+		roundingFunction("ceil", "smallest integer greater than or equal to a number", ceilRat),
+		round(),
+		abs(),
+		minOrMax("min", -1),
+		minOrMax("max", 1),
+		clamp(),
+		_int(),
+		decimal()})
+
+	mapping.Set(elmo.NewIdentifier("pi"), elmo.NewFloatLiteral(math.Pi))
+	mapping.Set(elmo.NewIdentifier("e"), elmo.NewFloatLiteral(math.E))
+
+	return mapping
+}
+
+func isNumber(value elmo.Value) bool {
+	switch value.Type() {
+	case elmo.TypeInteger, elmo.TypeBigInteger, elmo.TypeDecimal, elmo.TypeFloat:
+		return true
+	}
+	return false
+}
+
+func evalNumber(context elmo.RunContext, name string, argument elmo.Argument) (elmo.Value, elmo.ErrorValue) {
+	value := elmo.EvalArgument(context, argument)
+	if value.Type() == elmo.TypeError {
+		return nil, value.(elmo.ErrorValue)
+	}
+	if !isNumber(value) {
+		return nil, elmo.NewErrorValue(fmt.Sprintf("invalid call to math.%s, expected a number instead of %v", name, value))
+	}
+	return value, nil
+}
+
+// toRat converts any number into a rational, floats that are infinite
+// or not a number can not be converted
+//
+func toRat(name string, value elmo.Value) (*big.Rat, elmo.ErrorValue) {
+	if value.Type() != elmo.TypeFloat {
+		return elmo.ToRat(value)
+	}
+	r := new(big.Rat)
+	if r.SetFloat64(value.Internal().(float64)) == nil {
+		return nil, elmo.NewErrorValue(fmt.Sprintf("invalid call to math.%s, %v is not a finite number", name, value))
+	}
+	return r, nil
+}
+
+// fromFloat converts the result of a float calculation into a value and
+// reports results that are not a number as errors
+//
+func fromFloat(name string, argument elmo.Value, result float64) elmo.Value {
+	if math.IsNaN(result) || (math.IsInf(result, 0) && !math.IsInf(argument.Internal().(float64), 0)) {
+		return elmo.NewErrorValue(fmt.Sprintf("math.%s is not defined for %v", name, argument))
+	}
+	return elmo.NewFloatLiteral(result)
+}
+
+func floatFunction(name string, description string, f func(float64) float64) elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp(name, fmt.Sprintf(`Calculates the %s
+		Usage: math.%s <number>
+		Returns: a float`, description, name),
+
+		func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+			if _, err := elmo.CheckArguments(arguments, 1, 1, name, "<number>"); err != nil {
+				return err
+			}
+
+			value, err := evalNumber(context, name, arguments[0])
+			if err != nil {
+				return err
+			}
+
+			x, _ := elmo.ToFloat(value)
+			return fromFloat(name, elmo.NewFloatLiteral(x), f(x))
+		})
+}
+
+func log() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("log", `Calculates the logarithm of a number
+		Usage: math.log <number> <base>?
+		Returns: the natural logarithm of a number or its logarithm for given base
+
+		Examples:
+
+		> math.log 100 10
+		will result in 2.0`,
+
+		func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+			argLen, err := elmo.CheckArguments(arguments, 1, 2, "log", "<number> <base>?")
+			if err != nil {
+				return err
+			}
+
+			value, err := evalNumber(context, "log", arguments[0])
+			if err != nil {
+				return err
+			}
+			x, _ := elmo.ToFloat(value)
+
+			if argLen == 1 {
+				return fromFloat("log", elmo.NewFloatLiteral(x), math.Log(x))
+			}
+
+			base, err := evalNumber(context, "log", arguments[1])
+			if err != nil {
+				return err
+			}
+			b, _ := elmo.ToFloat(base)
+			if b <= 0 || b == 1 {
+				return elmo.NewErrorValue(fmt.Sprintf("invalid call to math.log, %v is not a valid base", base))
+			}
+
+			return fromFloat("log", elmo.NewFloatLiteral(x), math.Log(x)/math.Log(b))
+		})
+}
+
+func pow() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("pow", `Raises a number to a power
+		Usage: math.pow <number> <exponent>
+		Returns: an exact integer or decimal when the number is an integer or a decimal
+		and the exponent is a non negative integer, a float otherwise
+
+		Examples:
+
+		> math.pow 2 100
+		will result in 1267650600228229401496703205376
+		> math.pow 2 0.5
+		will result in 1.4142135623730951`,
+
+		func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+			if _, err := elmo.CheckArguments(arguments, 2, 2, "pow", "<number> <exponent>"); err != nil {
+				return err
+			}
+
+			base, err := evalNumber(context, "pow", arguments[0])
+			if err != nil {
+				return err
+			}
+			exponent, err := evalNumber(context, "pow", arguments[1])
+			if err != nil {
+				return err
+			}
+
+			if base.Type() != elmo.TypeFloat && exponent.Type() == elmo.TypeInteger && exponent.Internal().(int64) >= 0 {
+				power := big.NewInt(exponent.Internal().(int64))
+				r, _ := elmo.ToRat(base)
+				numerator := new(big.Int).Exp(r.Num(), power, nil)
+				if base.Type() != elmo.TypeDecimal {
+					return elmo.NewIntegerFromBig(numerator)
+				}
+				denominator := new(big.Int).Exp(r.Denom(), power, nil)
+				return elmo.NewDecimalValue(new(big.Rat).SetFrac(numerator, denominator), elmo.DecimalScale(base)*int(power.Int64()))
+			}
+
+			x, _ := elmo.ToFloat(base)
+			y, _ := elmo.ToFloat(exponent)
+			result := math.Pow(x, y)
+			if math.IsNaN(result) {
+				return elmo.NewErrorValue(fmt.Sprintf("math.pow is not defined for %v and %v", base, exponent))
+			}
+			return elmo.NewFloatLiteral(result)
+		})
+}
+
+func floorRat(r *big.Rat) *big.Int {
+	// division by a positive denominator rounds towards negative infinity
+	//
+	return new(big.Int).Div(r.Num(), r.Denom())
+}
+
+func ceilRat(r *big.Rat) *big.Int {
+	return new(big.Int).Neg(floorRat(new(big.Rat).Neg(r)))
+}
+
+// roundRat rounds to the nearest integer with halves rounded away from zero
+//
+func roundRat(r *big.Rat) *big.Int {
+	half := big.NewRat(1, 2)
+	if r.Sign() < 0 {
+		return ceilRat(new(big.Rat).Sub(r, half))
+	}
+	return floorRat(new(big.Rat).Add(r, half))
+}
+
+func roundingFunction(name string, description string, f func(*big.Rat) *big.Int) elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp(name, fmt.Sprintf(`Calculates the %s
+		Usage: math.%s <number>
+		Returns: an integer`, description, name),
+
+		func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+			if _, err := elmo.CheckArguments(arguments, 1, 1, name, "<number>"); err != nil {
+				return err
+			}
+
+			value, err := evalNumber(context, name, arguments[0])
+			if err != nil {
+				return err
+			}
+
+			r, err := toRat(name, value)
+			if err != nil {
+				return err
+			}
+			return elmo.NewIntegerFromBig(f(r))
+		})
+}
+
+func round() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("round", `Rounds a number, halves are rounded away from zero
+		Usage: math.round <number> <digits>?
+		Returns: an integer or, when the number of fractional digits is given,
+		a number of the same type with at most that number of fractional digits
+
+		Examples:
+
+		> math.round 2.5
+		will result in 3
+		> math.round 3.14159 2
+		will result in 3.14`,
+
+		func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+			argLen, err := elmo.CheckArguments(arguments, 1, 2, "round", "<number> <digits>?")
+			if err != nil {
+				return err
+			}
+
+			value, err := evalNumber(context, "round", arguments[0])
+			if err != nil {
+				return err
+			}
+
+			if argLen == 1 {
+				r, err := toRat("round", value)
+				if err != nil {
+					return err
+				}
+				return elmo.NewIntegerFromBig(roundRat(r))
+			}
+
+			digits := elmo.EvalArgument(context, arguments[1])
+			if digits.Type() != elmo.TypeInteger || digits.Internal().(int64) < 0 {
+				return elmo.NewErrorValue(fmt.Sprintf("invalid call to math.round, expected a non negative number of digits instead of %v", digits))
+			}
+
+			switch value.Type() {
+			case elmo.TypeDecimal:
+				r, _ := elmo.ToRat(value)
+				return elmo.NewDecimalValue(r, int(digits.Internal().(int64)))
+			case elmo.TypeFloat:
+				rounded, _ := strconv.ParseFloat(strconv.FormatFloat(value.Internal().(float64), 'f', int(digits.Internal().(int64)), 64), 64)
+				return elmo.NewFloatLiteral(rounded)
+			}
+			return value
+		})
+}
+
+func abs() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("abs", `Calculates the absolute value of a number
+		Usage: math.abs <number>
+		Returns: a number of the same type`,
+
+		func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+			if _, err := elmo.CheckArguments(arguments, 1, 1, "abs", "<number>"); err != nil {
+				return err
+			}
+
+			value, err := evalNumber(context, "abs", arguments[0])
+			if err != nil {
+				return err
+			}
+
+			switch value.Type() {
+			case elmo.TypeFloat:
+				return elmo.NewFloatLiteral(math.Abs(value.Internal().(float64)))
+			case elmo.TypeDecimal:
+				r, _ := elmo.ToRat(value)
+				return elmo.NewDecimalValue(r.Abs(r), elmo.DecimalScale(value))
+			}
+			i, _ := elmo.ToBigInt(value)
+			return elmo.NewIntegerFromBig(i.Abs(i))
+		})
+}
+
+// compare compares two numbers of any type, floats are compared with other
+// numbers by converting these numbers to floats
+//
+func compare(context elmo.RunContext, v1 elmo.Value, v2 elmo.Value) (int, elmo.ErrorValue) {
+	if v1.Type() != elmo.TypeFloat && v2.Type() != elmo.TypeFloat {
+		return v1.(elmo.ComparableValue).Compare(context, v2)
+	}
+
+	f1, _ := elmo.ToFloat(v1)
+	f2, _ := elmo.ToFloat(v2)
+	switch {
+	case f1 < f2:
+		return -1, nil
+	case f1 > f2:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func minOrMax(name string, direction int) elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp(name, fmt.Sprintf(`Determines the %s of numbers
+		Usage: math.%s <number>+ or math.%s <list>
+		Returns: the %s number
+
+		Examples:
+
+		> math.%s 3 1 2
+		> math.%s [3 1 2]`, map[int]string{-1: "minimum", 1: "maximum"}[direction], name, name,
+		map[int]string{-1: "smallest", 1: "largest"}[direction], name, name),
+
+		func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+			argLen, err := elmo.CheckArguments(arguments, 1, math.MaxInt16, name, "<number>+")
+			if err != nil {
+				return err
+			}
+
+			values := make([]elmo.Value, argLen)
+			for i, argument := range arguments {
+				values[i] = elmo.EvalArgument(context, argument)
+			}
+			if argLen == 1 && values[0].Type() == elmo.TypeList {
+				values = values[0].Internal().([]elmo.Value)
+			}
+			if len(values) == 0 {
+				return elmo.NewErrorValue(fmt.Sprintf("invalid call to math.%s, expected at least one number", name))
+			}
+
+			var result elmo.Value
+			for _, value := range values {
+				if value.Type() == elmo.TypeError {
+					return value
+				}
+				if !isNumber(value) {
+					return elmo.NewErrorValue(fmt.Sprintf("invalid call to math.%s, expected a number instead of %v", name, value))
+				}
+				if result == nil {
+					result = value
+					continue
+				}
+				c, err := compare(context, value, result)
+				if err != nil {
+					return err
+				}
+				if c == direction {
+					result = value
+				}
+			}
+			return result
+		})
+}
+
+func clamp() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("clamp", `Limits a number to a range
+		Usage: math.clamp <number> <min> <max>
+		Returns: min when the number is smaller than min, max when the number
+		is larger than max and the number itself otherwise
+
+		Examples:
+
+		> math.clamp 12 0 10
+		will result in 10`,
+
+		func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+			if _, err := elmo.CheckArguments(arguments, 3, 3, "clamp", "<number> <min> <max>"); err != nil {
+				return err
+			}
+
+			values := make([]elmo.Value, 3)
+			for i, argument := range arguments {
+				value, err := evalNumber(context, "clamp", argument)
+				if err != nil {
+					return err
+				}
+				values[i] = value
+			}
+
+			if c, err := compare(context, values[1], values[2]); err != nil {
+				return err
+			} else if c > 0 {
+				return elmo.NewErrorValue(fmt.Sprintf("invalid call to math.clamp, min %v is larger than max %v", values[1], values[2]))
+			}
+
+			if c, _ := compare(context, values[0], values[1]); c < 0 {
+				return values[1]
+			}
+			if c, _ := compare(context, values[0], values[2]); c > 0 {
+				return values[2]
+			}
+			return values[0]
+		})
+}
+
+func _int() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("int", `Converts a value to an integer of any size
+		Usage: math.int <string|number>
+		Returns: an integer, fractions are truncated
+
+		Integers that do not fit in 64 bits are big integers. Calculations
+		on integers automatically result in big integers when needed.
+
+		Examples:
+
+		> math.int "123456789012345678901234567890"
+		> math.int 3.9
+		will result in 3`,
+
+		func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+			if _, err := elmo.CheckArguments(arguments, 1, 1, "int", "<string|number>"); err != nil {
+				return err
+			}
+
+			value := elmo.EvalArgument(context, arguments[0])
+			switch {
+			case value.Type() == elmo.TypeError:
+				return value
+			case value.Type() == elmo.TypeString:
+				result, err := elmo.NewIntegerFromString(value.String())
+				if err != nil {
+					return err
+				}
+				return result
+			case isNumber(value):
+				r, err := toRat("int", value)
+				if err != nil {
+					return err
+				}
+				return elmo.NewIntegerFromBig(new(big.Int).Quo(r.Num(), r.Denom()))
+			}
+			return elmo.NewErrorValue(fmt.Sprintf("invalid call to math.int, expected a string or a number instead of %v", value))
+		})
+}
+
+func decimal() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("decimal", `Converts a value to an exact decimal number
+		Usage: math.decimal <string|number> <digits>?
+		Returns: a decimal with given or the value's number of fractional digits
+
+		Calculations on decimals are exact. Adding or subtracting decimals results
+		in a decimal with the largest number of fractional digits, multiplying
+		adds up the number of fractional digits. Division uses as many digits as
+		needed to represent the result, up to 32 digits.
+		Calculations mixing decimals and floats result in floats.
+
+		Examples:
+
+		> math.decimal "0.10"
+		> plus (math.decimal "0.1") (math.decimal "0.2")
+		will result in 0.3
+		> math.decimal 2 2
+		will result in 2.00`,
+
+		func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+			argLen, err := elmo.CheckArguments(arguments, 1, 2, "decimal", "<string|number> <digits>?")
+			if err != nil {
+				return err
+			}
+
+			value := elmo.EvalArgument(context, arguments[0])
+			var result elmo.Value
+			switch {
+			case value.Type() == elmo.TypeError:
+				return value
+			case value.Type() == elmo.TypeString:
+				result, err = elmo.NewDecimalFromString(value.String())
+			case value.Type() == elmo.TypeFloat:
+				result, err = elmo.NewDecimalFromString(strconv.FormatFloat(value.Internal().(float64), 'f', -1, 64))
+			case isNumber(value):
+				r, _ := elmo.ToRat(value)
+				result = elmo.NewDecimalValue(r, elmo.DecimalScale(value))
+			default:
+				return elmo.NewErrorValue(fmt.Sprintf("invalid call to math.decimal, expected a string or a number instead of %v", value))
+			}
+			if err != nil {
+				return err
+			}
+
+			if argLen == 2 {
+				digits := elmo.EvalArgument(context, arguments[1])
+				if digits.Type() != elmo.TypeInteger || digits.Internal().(int64) < 0 {
+					return elmo.NewErrorValue(fmt.Sprintf("invalid call to math.decimal, expected a non negative number of digits instead of %v", digits))
+				}
+				r, _ := elmo.ToRat(result)
+				result = elmo.NewDecimalValue(r, int(digits.Internal().(int64)))
+			}
+			return result
+		})
+}
@@ -0,0 +1,212 @@
+package elmomath
+
+import (
+	"testing"
+
+	elmo "github.com/okke/elmo/core"
+)
+
+func mathContext() elmo.RunContext {
+	context := elmo.NewGlobalContext()
+	context.RegisterModule(Module)
+	return context
+}
+
+func TestFloatFunctions(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.sqrt 16`, elmo.ExpectValue(t, elmo.NewFloatLiteral(4.0)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.sqrt -1`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.sqrt "chipotle"`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.sin 0`, elmo.ExpectValue(t, elmo.NewFloatLiteral(0.0)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.cos $math.pi`, elmo.ExpectValue(t, elmo.NewFloatLiteral(-1.0)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.log $math.e`, elmo.ExpectValue(t, elmo.NewFloatLiteral(1.0)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.log 8 2`, elmo.ExpectValue(t, elmo.NewFloatLiteral(3.0)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.log 0`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.log 8 1`, elmo.ExpectErrorValueAt(t, 2))
+}
+
+func TestPow(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.pow 2 10`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(1024)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 eq (math.pow 2 100) 1267650600228229401496703205376`, elmo.ExpectValue(t, elmo.True))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.pow 4 0.5`, elmo.ExpectValue(t, elmo.NewFloatLiteral(2.0)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.pow 2 -1`, elmo.ExpectValue(t, elmo.NewFloatLiteral(0.5)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 eq (math.pow (math.decimal "1.5") 2) (math.decimal "2.25")`, elmo.ExpectValue(t, elmo.True))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.pow -8 0.5`, elmo.ExpectErrorValueAt(t, 2))
+}
+
+func TestRounding(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.floor 2.7`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(2)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.floor -2.5`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(-3)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.ceil 2.1`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(3)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.ceil (math.decimal "-2.5")`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(-2)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.round 2.5`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(3)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.round -2.5`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(-3)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.round 7`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(7)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.round 3.14159 2`, elmo.ExpectValue(t, elmo.NewFloatLiteral(3.14)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.round (math.decimal "2.345") 2`, elmo.ExpectString(t, "2.35"))
+}
+
+func TestAbsMinMaxAndClamp(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.abs -3`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(3)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.abs -9223372036854775808`, elmo.ExpectString(t, "9223372036854775808"))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.abs -2.5`, elmo.ExpectValue(t, elmo.NewFloatLiteral(2.5)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.abs (math.decimal "-2.50")`, elmo.ExpectString(t, "2.50"))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.min 3 1 2`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(1)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.max [3 1.5 2]`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(3)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.max []`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.min 1 "chipotle"`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.clamp 12 0 10`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(10)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.clamp -1 0 10`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(0)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.clamp 0.5 0 10`, elmo.ExpectValue(t, elmo.NewFloatLiteral(0.5)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.clamp 5 10 0`, elmo.ExpectErrorValueAt(t, 2))
+}
+
+func TestIntAndDecimal(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.int "42"`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(42)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 type (math.int "123456789012345678901234567890")`, elmo.ExpectValue(t, elmo.NewIdentifier("bigint")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.int -3.9`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(-3)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.int "chipotle"`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 plus (math.decimal "0.1") (math.decimal "0.2")`, elmo.ExpectString(t, "0.3"))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.decimal 0.1`, elmo.ExpectString(t, "0.1"))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.decimal 2 2`, elmo.ExpectString(t, "2.00"))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.decimal "2.345" 1`, elmo.ExpectString(t, "2.3"))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.decimal "1e5"`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, mathContext(),
+		`math: (load "math")
+		 math.decimal "2" -1`, elmo.ExpectErrorValueAt(t, 2))
+}
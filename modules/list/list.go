@@ -24,18 +24,24 @@ func initModule(context elmo.RunContext) elmo.Value {
 		each(),
 		_map(),
 		where(),
+		take(),
+		collect(),
 		_sort(),
 		mutableSort(),
 		flatten(),
 	})
 }
 
-func convertToList(value elmo.Value) ([]elmo.Value, elmo.ErrorValue) {
+func convertToList(context elmo.RunContext, value elmo.Value) ([]elmo.Value, elmo.ErrorValue) {
 
 	if value.Type() == elmo.TypeList {
 		return value.Internal().([]elmo.Value), nil
 	}
 
+	if iterable, casted := elmo.AsIterable(value); casted {
+		return elmo.CollectValues(context, iterable)
+	}
+
 	convertable, casted := value.Internal().(elmo.Listable)
 
 	if !casted {
@@ -62,7 +68,7 @@ func tuple() elmo.NamedValue {
 
 		list := elmo.EvalArgumentOrSolveIdentifier(context, arguments[0])
 
-		internal, err := convertToList(list)
+		internal, err := convertToList(context, list)
 		if err != nil {
 			return err
 		}
@@ -83,7 +89,7 @@ func at() elmo.NamedValue {
 		//
 		list := elmo.EvalArgumentOrSolveIdentifier(context, arguments[0])
 
-		internal, err := convertToList(list)
+		internal, err := convertToList(context, list)
 		if err != nil {
 			return err
 		}
@@ -104,27 +110,14 @@ func appendAndOptionallyChange(name string, change bool) elmo.NamedValue {
 		//
 		list := elmo.EvalArgumentOrSolveIdentifier(context, arguments[0])
 
-		internal, err := convertToList(list)
-		if err != nil {
-			return err
-		}
-
+		items := make([]elmo.Value, 0, argLen-1)
 		for i := 1; i < argLen; i++ {
-			internal = append(internal, elmo.EvalArgument(context, arguments[i]))
+			items = append(items, elmo.EvalArgument(context, arguments[i]))
 		}
 
-		if change {
-			mutable, ok := list.(elmo.MutableValue)
-			if !ok {
-				return elmo.NewErrorValue(fmt.Sprintf("can not mutate %v", list))
-			}
-			if _, err := mutable.Mutate(internal); err != nil {
-				return err
-			}
-		}
-
-		return elmo.NewListValue(internal)
-
+		return changeList(context, list, change, func(internal []elmo.Value) []elmo.Value {
+			return append(internal, items...)
+		})
 	})
 }
 
@@ -147,27 +140,52 @@ func prependAndOptionallyChange(name string, change bool) elmo.NamedValue {
 		//
 		list := elmo.EvalArgumentOrSolveIdentifier(context, arguments[0])
 
-		internal, err := convertToList(list)
-		if err != nil {
-			return err
+		items := make([]elmo.Value, 0, argLen-1)
+		for i := argLen - 1; i > 0; i-- {
+			items = append(items, elmo.EvalArgument(context, arguments[i]))
 		}
 
-		for i := 1; i < argLen; i++ {
-			internal = append([]elmo.Value{elmo.EvalArgument(context, arguments[i])}, internal...)
+		return changeList(context, list, change, func(internal []elmo.Value) []elmo.Value {
+			return append(items[:len(items):len(items)], internal...)
+		})
+	})
+}
+
+// changeList applies a change to the content of a list. When the list itself
+// must be changed, this is done atomically so lists can be shared by actors
+//
+func changeList(context elmo.RunContext, list elmo.Value, change bool, with func([]elmo.Value) []elmo.Value) elmo.Value {
+
+	internal, err := convertToList(context, list)
+	if err != nil {
+		return err
+	}
+
+	if !change {
+		changed := with(internal)
+		if err := elmo.CheckCollectionSize(context, len(changed)); err != nil {
+			return err
 		}
+		return elmo.NewListValue(changed)
+	}
 
-		if change {
-			mutable, ok := list.(elmo.MutableValue)
-			if !ok {
-				return elmo.NewErrorValue(fmt.Sprintf("can not mutate %v", list))
-			}
-			if _, err := mutable.Mutate(internal); err != nil {
-				return err
-			}
+	mutable, ok := list.(elmo.MutableValue)
+	if !ok {
+		return elmo.NewErrorValue(fmt.Sprintf("can not mutate %v", list))
+	}
+
+	var changed []elmo.Value
+	if _, err := mutable.Update(func(current interface{}) (interface{}, elmo.ErrorValue) {
+		changed = with(current.([]elmo.Value))
+		if err := elmo.CheckCollectionSize(context, len(changed)); err != nil {
+			return nil, err
 		}
+		return changed, nil
+	}); err != nil {
+		return err
+	}
 
-		return elmo.NewListValue(internal)
-	})
+	return elmo.NewListValue(changed)
 }
 
 func prepend() elmo.NamedValue {
@@ -221,8 +239,22 @@ func runInBlock(context elmo.RunContext, valueName string, value elmo.Value, ind
 	return elmo.NewErrorValue(fmt.Sprintf("invalid block %v", block))
 }
 
+// lazy returns the iterable of a value that should not be materialized, like
+// the output of a command or the values produced by a generator
+//
+func lazy(value elmo.Value) (elmo.IterableValue, bool) {
+	if value.Type() == elmo.TypeList {
+		return nil, false
+	}
+	return elmo.AsIterable(value)
+}
+
 func each() elmo.NamedValue {
-	return elmo.NewGoFunctionWithHelp("each", `iterate over items in list and executes block of code`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+	return elmo.NewGoFunctionWithHelp("each", `iterate over items in list and executes block of code
+	usage: each <list> <value identifier> <index identifier>? <block>
+	Items of lazy sequences (like ranges, generators, command output or file lines)
+	are produced one at a time while iterating. Use break to stop iterating and
+	continue to skip to the next item`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
 
 		list, valueName, indexName, block, valid := getValueIndexAndBlock(context, arguments)
 
@@ -230,17 +262,31 @@ func each() elmo.NamedValue {
 			return elmo.NewErrorValue("invalid call to each: usage each <list> <value identifier> <index identifier>? <block>")
 		}
 
-		var result elmo.Value
+		var result elmo.Value = elmo.Nothing
 
 		subContext := context.CreateSubContext()
 
-		internal, err := convertToList(list)
+		iterator, err := elmo.Iterate(list)
 		if err != nil {
 			return err
 		}
+		defer iterator.Close()
 
-		for index, value := range internal {
-			result = runInBlock(subContext, valueName, value, indexName, index, block)
+		index := 0
+		for value, ok := iterator.Next(); ok; value, ok = iterator.Next() {
+			if value.Type() == elmo.TypeError {
+				return value
+			}
+			iteration, done := elmo.RunLoopIteration(subContext, func(iterationContext elmo.RunContext) elmo.Value {
+				return runInBlock(iterationContext, valueName, value, indexName, index, block)
+			})
+			if iteration != nil {
+				result = iteration
+			}
+			if done {
+				return result
+			}
+			index = index + 1
 		}
 
 		return result
@@ -248,8 +294,41 @@ func each() elmo.NamedValue {
 	})
 }
 
+// lazilyRunInBlock creates an iterable that passes all values of given iterable
+// to a block of code while being iterated. The result of the block and the value
+// itself are handed to apply which determines the value to produce, if any
+//
+func lazilyRunInBlock(context elmo.RunContext, iterable elmo.IterableValue, valueName string, indexName string, block elmo.Value,
+	apply func(value elmo.Value, result elmo.Value) (elmo.Value, bool)) elmo.Value {
+
+	return elmo.NewIterableValue(func() elmo.Iterator {
+		iterator := iterable.Iterate()
+		subContext := context.CreateSubContext()
+		index := 0
+
+		return elmo.NewIterator(func() (elmo.Value, bool) {
+			for value, ok := iterator.Next(); ok; value, ok = iterator.Next() {
+				if value.Type() == elmo.TypeError {
+					return value, true
+				}
+				result := runInBlock(subContext, valueName, value, indexName, index, block)
+				index = index + 1
+				if result.Type() == elmo.TypeError {
+					return result, true
+				}
+				if produced, produce := apply(value, result); produce {
+					return produced, true
+				}
+			}
+			return nil, false
+		}, iterator.Close)
+	})
+}
+
 func _map() elmo.NamedValue {
-	return elmo.NewGoFunctionWithHelp("map", `map all items to values returned by block of code`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+	return elmo.NewGoFunctionWithHelp("map", `map all items to values returned by block of code
+	usage: map <list> <value identifier> <index identifier>? <block>
+	Mapping a lazy sequence results in a lazy sequence which maps items while being iterated`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
 
 		list, valueName, indexName, block, valid := getValueIndexAndBlock(context, arguments)
 
@@ -257,7 +336,13 @@ func _map() elmo.NamedValue {
 			return elmo.NewErrorValue("invalid call to map: usage map <list> <value identifier> <index identifier>? <block>")
 		}
 
-		oldValues, err := convertToList(list)
+		if iterable, isLazy := lazy(list); isLazy {
+			return lazilyRunInBlock(context, iterable, valueName, indexName, block, func(value elmo.Value, result elmo.Value) (elmo.Value, bool) {
+				return result, true
+			})
+		}
+
+		oldValues, err := convertToList(context, list)
 		if err != nil {
 			return err
 		}
@@ -275,7 +360,9 @@ func _map() elmo.NamedValue {
 }
 
 func where() elmo.NamedValue {
-	return elmo.NewGoFunctionWithHelp("where", `filter items`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+	return elmo.NewGoFunctionWithHelp("where", `filter items
+	usage: where <list> <value identifier> <index identifier>? <block>
+	Filtering a lazy sequence results in a lazy sequence which filters items while being iterated`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
 
 		list, valueName, indexName, block, valid := getValueIndexAndBlock(context, arguments)
 
@@ -283,7 +370,13 @@ func where() elmo.NamedValue {
 			return elmo.NewErrorValue("invalid call to where: usage where <list> <value identifier> <index identifier>? <block>")
 		}
 
-		oldValues, err := convertToList(list)
+		if iterable, isLazy := lazy(list); isLazy {
+			return lazilyRunInBlock(context, iterable, valueName, indexName, block, func(value elmo.Value, result elmo.Value) (elmo.Value, bool) {
+				return value, result == elmo.True
+			})
+		}
+
+		oldValues, err := convertToList(context, list)
 		if err != nil {
 			return err
 		}
@@ -301,6 +394,82 @@ func where() elmo.NamedValue {
 	})
 }
 
+func take() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("take", `take the first items of a list
+	usage: take <list> <count>
+	Taking from a lazy sequence results in a lazy sequence that stops iterating the
+	original sequence after count items, so it can be used on endless sequences`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		_, err := elmo.CheckArguments(arguments, 2, 2, "take", "<list> <count>")
+		if err != nil {
+			return err
+		}
+
+		// first argument of a list function can be an identifier with the name of the list
+		//
+		list := elmo.EvalArgumentOrSolveIdentifier(context, arguments[0])
+
+		count := elmo.EvalArgument(context, arguments[1])
+		if count.Type() != elmo.TypeInteger || count.Internal().(int64) < 0 {
+			return elmo.NewErrorValue("take expects a positive integer count")
+		}
+		n := count.Internal().(int64)
+
+		if iterable, isLazy := lazy(list); isLazy {
+			return elmo.NewIterableValue(func() elmo.Iterator {
+				var iterator elmo.Iterator
+				var taken int64
+				return elmo.NewIterator(func() (elmo.Value, bool) {
+					if taken >= n {
+						return nil, false
+					}
+					if iterator == nil {
+						iterator = iterable.Iterate()
+					}
+					taken = taken + 1
+					return iterator.Next()
+				}, func() {
+					if iterator != nil {
+						iterator.Close()
+					}
+				})
+			})
+		}
+
+		internal, err := convertToList(context, list)
+		if err != nil {
+			return err
+		}
+
+		if n > int64(len(internal)) {
+			n = int64(len(internal))
+		}
+		return elmo.NewListValue(internal[:n:n])
+	})
+}
+
+func collect() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("collect", `collect all items of a lazy sequence into a list
+	usage: collect <list>`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		_, err := elmo.CheckArguments(arguments, 1, 1, "collect", "<list>")
+		if err != nil {
+			return err
+		}
+
+		// first argument of a list function can be an identifier with the name of the list
+		//
+		list := elmo.EvalArgumentOrSolveIdentifier(context, arguments[0])
+
+		internal, err := convertToList(context, list)
+		if err != nil {
+			return err
+		}
+
+		return elmo.NewListValue(internal)
+	})
+}
+
 func mutableSort() elmo.NamedValue {
 	return elmo.NewGoFunctionWithHelp("sort!", `sort items in list`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
 
@@ -313,7 +482,7 @@ func mutableSort() elmo.NamedValue {
 		//
 		list := elmo.EvalArgumentOrSolveIdentifier(context, arguments[0])
 
-		internal, err := convertToList(list)
+		internal, err := convertToList(context, list)
 		if err != nil {
 			return err
 		}
@@ -339,7 +508,7 @@ func _sort() elmo.NamedValue {
 		//
 		list := elmo.EvalArgumentOrSolveIdentifier(context, arguments[0])
 
-		internal, err := convertToList(list)
+		internal, err := convertToList(context, list)
 		if err != nil {
 			return err
 		}
@@ -384,7 +553,7 @@ func flatten() elmo.NamedValue {
 		//
 		list := elmo.EvalArgumentOrSolveIdentifier(context, arguments[0])
 
-		internal, err := convertToList(list)
+		internal, err := convertToList(context, list)
 		if err != nil {
 			return err
 		}
@@ -334,3 +334,112 @@ func TestFlatten(t *testing.T) {
 		`list: (load "list")
 		list.flatten [1 2 [1 [a b] 3] 4] |eq [1 2 1 a b 3 4] |assert`, elmo.ExpectValue(t, elmo.True))
 }
+
+func TestLazyMapAndWhere(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, listContext(),
+		`list: (load "list")
+		 list.map (range 3) v { multiply $v 2 }`, elmo.ExpectIterated(t, elmo.ParseAndRun(elmo.NewGlobalContext(), "[0 2 4]")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, listContext(),
+		`list: (load "list")
+		 list.where (range 10) v i { eq (modulo $i 3) 0 }`, elmo.ExpectIterated(t, elmo.ParseAndRun(elmo.NewGlobalContext(), "[0 3 6 9]")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, listContext(),
+		`list: (load "list")
+		 list.map (range 3) v { error "chipotle" } |list.collect`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, listContext(),
+		`list: (load "list")
+		 naturals: (generator { n: 0; while (true) { yield $n; incr n } })
+		 evens: (list.where (naturals) n { eq (modulo $n 2) 0 })
+		 list.take (list.map $evens n { multiply $n $n }) 3`, elmo.ExpectIterated(t, elmo.ParseAndRun(elmo.NewGlobalContext(), "[0 4 16]")))
+}
+
+func TestEachOnLazySequences(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, listContext(),
+		`list: (load "list")
+		 squares: []
+		 list.each (range 1 4) v { list.append! squares (multiply $v $v) }
+		 $squares`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), "[1 4 9]")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, listContext(),
+		`list: (load "list")
+		 tempFile tmp {
+		   tmp.write "chipotle\njalapeno\nhabanero"
+		   lines: []
+		   list.each (tmp.lines) line { list.append! lines $line }
+		   return $lines
+		 }`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), `["chipotle" "jalapeno" "habanero"]`)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, listContext(),
+		`list: (load "list")
+		 list.each (generator { yield 1; error "chipotle" }|eval) v { $v }`, elmo.ExpectErrorValueAt(t, 2))
+}
+
+func TestEachWithBreakAndContinue(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, listContext(),
+		`list: (load "list")
+		 seen: []
+		 list.each [1 2 3 4 5] v {
+		   if (eq $v 2) { continue }
+		   if (eq $v 4) { break }
+		   list.append! seen $v
+		 }
+		 $seen`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), "[1 3]")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, listContext(),
+		`list: (load "list")
+		 list.each (range 1000000000) v { if (eq $v 3) { break }; $v }`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(2)))
+}
+
+func TestTake(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, listContext(),
+		`list: (load "list")
+		list.take [1 2 3]`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, listContext(),
+		`list: (load "list")
+		list.take [1 2 3] -1`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, listContext(),
+		`list: (load "list")
+		list.take [1 2 3] 2`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), "[1 2]")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, listContext(),
+		`list: (load "list")
+		list.take [1 2 3] 5`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), "[1 2 3]")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, listContext(),
+		`list: (load "list")
+		list.take (range 1000000000) 3`, elmo.ExpectIterated(t, elmo.ParseAndRun(elmo.NewGlobalContext(), "[0 1 2]")))
+}
+
+func TestCollect(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, listContext(),
+		`list: (load "list")
+		list.collect (range 3)`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), "[0 1 2]")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, listContext(),
+		`list: (load "list")
+		list.sort (list.map (range 3 0 -1) v { $v })`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), "[1 2 3]")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, listContext(),
+		`list: (load "list")
+		list.collect 3`, elmo.ExpectErrorValueAt(t, 2))
+}
+
+func TestDerivedListsStayWithinCollectionSize(t *testing.T) {
+
+	context := listContext()
+	context.SetLimits(elmo.Limits{MaxCollectionSize: 3})
+
+	elmo.ParseTestAndRunBlockWithinContext(t, context,
+		`list: (load "list")
+		 l: (list.map [1 2 3] v { return [$v $v] })
+		 list.flatten $l`, elmo.ExpectAbortErrorValue(t))
+}
@@ -0,0 +1,276 @@
+package set
+
+import (
+	"fmt"
+	"math"
+
+	elmo "github.com/okke/elmo/core"
+)
+
+// Module contains functions that operate on sets
+//
+var Module = elmo.NewModule("set", initModule)
+
+func initModule(context elmo.RunContext) elmo.Value {
+	return elmo.NewMappingForModule(context, []elmo.NamedValue{
+		_new(), from(), contains(), add(), remove(), union(), intersection(), difference(), list()})
+}
+
+func newSet(context elmo.RunContext, values []elmo.Value) elmo.Value {
+	if err := elmo.CheckCollectionSize(context, len(values)); err != nil {
+		return err
+	}
+
+	set, err := elmo.NewSetValue(values)
+	if err != nil {
+		return err
+	}
+	return set
+}
+
+// evalSet evaluates the first argument of set functions which can be an
+// identifier with the name of the set
+//
+func evalSet(context elmo.RunContext, name string, argument elmo.Argument) (elmo.SetValue, elmo.ErrorValue) {
+	value := elmo.EvalArgumentOrSolveIdentifier(context, argument)
+	if value.Type() == elmo.TypeError {
+		return nil, value.(elmo.ErrorValue)
+	}
+
+	set, ok := value.(elmo.SetValue)
+	if !ok {
+		return nil, elmo.NewErrorValue(fmt.Sprintf("invalid call to %s, expect a set instead of %v", name, value))
+	}
+	return set, nil
+}
+
+func _new() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("new", `creates a new set
+	usage new <value>*
+
+	values must be strings, numbers, booleans or frozen lists, dictionaries
+	or sets. duplicate values are added once
+
+	example:
+
+	sets: (load set)
+	sets.new 1 2 2 |len |eq 2 |assert
+	`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		values := make([]elmo.Value, len(arguments))
+		for i, argument := range arguments {
+			values[i] = elmo.EvalArgument(context, argument)
+		}
+
+		return newSet(context, values)
+	})
+}
+
+func from() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("from", `creates a new set from the values of a list or any other iterable value
+	usage from <list>
+
+	example:
+
+	sets: (load set)
+	sets.from [1 2 2] |len |eq 2 |assert
+	`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		_, err := elmo.CheckArguments(arguments, 1, 1, "from", "<list>")
+		if err != nil {
+			return err
+		}
+
+		value := elmo.EvalArgumentOrSolveIdentifier(context, arguments[0])
+		if value.Type() == elmo.TypeError {
+			return value
+		}
+
+		if value.Type() == elmo.TypeList {
+			return newSet(context, value.Internal().([]elmo.Value))
+		}
+
+		iterable, ok := elmo.AsIterable(value)
+		if !ok {
+			return elmo.NewErrorValue(fmt.Sprintf("invalid call to from, can not create a set from %v", value))
+		}
+
+		values, err := elmo.CollectValues(context, iterable)
+		if err != nil {
+			return err
+		}
+		return newSet(context, values)
+	})
+}
+
+func contains() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("contains", `checks if a set contains a value
+	usage contains <set> <value>
+
+	example:
+
+	sets: (load set)
+	sets.contains (sets.new 1 2) 2 |assert
+	`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		_, err := elmo.CheckArguments(arguments, 2, 2, "contains", "<set> <value>")
+		if err != nil {
+			return err
+		}
+
+		set, err := evalSet(context, "contains", arguments[0])
+		if err != nil {
+			return err
+		}
+
+		if set.Contains(elmo.EvalArgument(context, arguments[1])) {
+			return elmo.True
+		}
+		return elmo.False
+	})
+}
+
+func add() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("add!", `adds values to a set
+	usage add! <set> <value>+
+	`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		argLen, err := elmo.CheckArguments(arguments, 2, math.MaxInt16, "add!", "<set> <value>+")
+		if err != nil {
+			return err
+		}
+
+		set, err := evalSet(context, "add!", arguments[0])
+		if err != nil {
+			return err
+		}
+
+		for i := 1; i < argLen; i++ {
+			if _, err := set.Add(elmo.EvalArgument(context, arguments[i])); err != nil {
+				return err
+			}
+		}
+
+		if err := elmo.CheckCollectionSize(context, len(set.List())); err != nil {
+			return err
+		}
+
+		return set
+	})
+}
+
+func remove() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("remove!", `removes values from a set
+	usage remove! <set> <value>+
+	`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		argLen, err := elmo.CheckArguments(arguments, 2, math.MaxInt16, "remove!", "<set> <value>+")
+		if err != nil {
+			return err
+		}
+
+		set, err := evalSet(context, "remove!", arguments[0])
+		if err != nil {
+			return err
+		}
+
+		for i := 1; i < argLen; i++ {
+			if _, err := set.Remove(elmo.EvalArgument(context, arguments[i])); err != nil {
+				return err
+			}
+		}
+
+		return set
+	})
+}
+
+// combine creates a new set by combining all given sets, from left to right
+//
+func combine(name string, how func(elmo.SetValue, elmo.SetValue) elmo.SetValue) func(elmo.RunContext, []elmo.Argument) elmo.Value {
+	return func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		argLen, err := elmo.CheckArguments(arguments, 2, math.MaxInt16, name, "<set> <set>+")
+		if err != nil {
+			return err
+		}
+
+		result, err := evalSet(context, name, arguments[0])
+		if err != nil {
+			return err
+		}
+
+		for i := 1; i < argLen; i++ {
+			with, err := evalSet(context, name, arguments[i])
+			if err != nil {
+				return err
+			}
+			result = how(result, with)
+		}
+
+		if err := elmo.CheckCollectionSize(context, len(result.List())); err != nil {
+			return err
+		}
+
+		return result
+	}
+}
+
+func union() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("union", `creates a new set with the values of all given sets
+	usage union <set> <set>+
+
+	example:
+
+	sets: (load set)
+	sets.union (sets.new 1 2) (sets.new 2 3) |eq (sets.new 1 2 3) |assert
+	`, combine("union", func(s1 elmo.SetValue, s2 elmo.SetValue) elmo.SetValue {
+		return s1.Union(s2)
+	}))
+}
+
+func intersection() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("intersection", `creates a new set with the values that are in all given sets
+	usage intersection <set> <set>+
+
+	example:
+
+	sets: (load set)
+	sets.intersection (sets.new 1 2) (sets.new 2 3) |eq (sets.new 2) |assert
+	`, combine("intersection", func(s1 elmo.SetValue, s2 elmo.SetValue) elmo.SetValue {
+		return s1.Intersection(s2)
+	}))
+}
+
+func difference() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("difference", `creates a new set with the values of the first set that are not in the other sets
+	usage difference <set> <set>+
+
+	example:
+
+	sets: (load set)
+	sets.difference (sets.new 1 2) (sets.new 2 3) |eq (sets.new 1) |assert
+	`, combine("difference", func(s1 elmo.SetValue, s2 elmo.SetValue) elmo.SetValue {
+		return s1.Difference(s2)
+	}))
+}
+
+func list() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("list", `converts a set into a list
+	usage list <set>
+
+	values are listed in the order in which they were added to the set
+	`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		_, err := elmo.CheckArguments(arguments, 1, 1, "list", "<set>")
+		if err != nil {
+			return err
+		}
+
+		set, err := evalSet(context, "list", arguments[0])
+		if err != nil {
+			return err
+		}
+
+		return elmo.NewListValue(set.List())
+	})
+}
@@ -0,0 +1,124 @@
+package set
+
+import (
+	"testing"
+
+	elmo "github.com/okke/elmo/core"
+)
+
+func setContext() elmo.RunContext {
+	context := elmo.NewGlobalContext()
+	context.RegisterModule(Module)
+	return context
+}
+
+func TestNew(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, setContext(),
+		`s: (load "set")
+		 s.new 1 2 "2" 2 |s.list`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), `[1 2 "2"]`)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, setContext(),
+		`s: (load "set")
+		 s.new 1 2 2 |len`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(2)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, setContext(),
+		`s: (load "set")
+		 s.new |type`, elmo.ExpectValue(t, elmo.NewIdentifier("set")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, setContext(),
+		`s: (load "set")
+		 s.new [1 2]`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, setContext(),
+		`s: (load "set")
+		 s.new (freeze! [1 2]) (freeze! [1 2]) |len`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(1)))
+}
+
+func TestFrom(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, setContext(),
+		`s: (load "set")
+		 s.from`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, setContext(),
+		`s: (load "set")
+		 s.from 3`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, setContext(),
+		`s: (load "set")
+		 s.from [3 1 3] |s.list`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), `[3 1]`)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, setContext(),
+		`s: (load "set")
+		 s.from (range 3) |s.list`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), `[0 1 2]`)))
+}
+
+func TestContains(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, setContext(),
+		`s: (load "set")
+		 s.contains [1] 1`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, setContext(),
+		`s: (load "set")
+		 peppers: (s.new chipotle jalapeno)
+		 [(s.contains $peppers chipotle) (s.contains $peppers "jalapeno") (s.contains $peppers habanero)]`,
+		elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), `[$true $true $false]`)))
+}
+
+func TestAddAndRemove(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, setContext(),
+		`s: (load "set")
+		 peppers: (s.new chipotle)
+		 s.add! $peppers jalapeno chipotle habanero
+		 s.remove! peppers chipotle
+		 s.list $peppers`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), `[jalapeno habanero]`)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, setContext(),
+		`s: (load "set")
+		 peppers: (s.new chipotle)
+		 freeze! $peppers
+		 s.add! $peppers jalapeno`, elmo.ExpectErrorValueAt(t, 4))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, setContext(),
+		`s: (load "set")
+		 s.add! (s.new) [1]`, elmo.ExpectErrorValueAt(t, 2))
+}
+
+func TestCombine(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, setContext(),
+		`s: (load "set")
+		 s.union (s.new 1 2)`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, setContext(),
+		`s: (load "set")
+		 s.union (s.new 1 2) [3]`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, setContext(),
+		`s: (load "set")
+		 s.union (s.new 1 2) (s.new 2 3) (s.new 4) |s.list`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), `[1 2 3 4]`)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, setContext(),
+		`s: (load "set")
+		 s.intersection (s.new 1 2 3) (s.new 3 2) |s.list`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), `[2 3]`)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, setContext(),
+		`s: (load "set")
+		 s.difference (s.new 1 2 3) (s.new 2) (s.new 3) |s.list`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), `[1]`)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, setContext(),
+		`s: (load "set")
+		 s.union (s.new 1 2) (s.new 3) |eq (s.new 3 2 1)`, elmo.ExpectValue(t, elmo.True))
+}
+
+func TestIterate(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, setContext(),
+		`s: (load "set")
+		 total: 0
+		 for v in (s.new 1 2 2 3) { total: (plus $total $v) }
+		 total`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(6)))
+}
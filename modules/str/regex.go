@@ -0,0 +1,307 @@
+package str
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+
+	elmo "github.com/okke/elmo/core"
+)
+
+var typeInfoPattern = elmo.NewTypeInfo("pattern")
+
+// patternCacheSize is the maximum number of compiled patterns that are cached
+//
+const patternCacheSize = 256
+
+// compiled patterns are cached by their source so patterns used
+// within loops or functions are only compiled once
+//
+var patternCache = elmo.NewLRUCache(patternCacheSize)
+
+func compilePattern(source string) (elmo.Value, elmo.ErrorValue) {
+	if cached, found := patternCache.Get(source); found {
+		return cached.(elmo.Value), nil
+	}
+
+	compiled, err := regexp.Compile(source)
+	if err != nil {
+		return nil, elmo.NewErrorValue(fmt.Sprintf("invalid regular expression %s: %v", source, err))
+	}
+
+	pattern := patternCache.Put(source, elmo.NewInternalValue(typeInfoPattern, compiled))
+	return pattern.(elmo.Value), nil
+}
+
+// evalPattern evaluates an argument into a compiled regular expression. The
+// argument can be a pattern created by str.regex or a string
+//
+func evalPattern(context elmo.RunContext, name string, argument elmo.Argument) (*regexp.Regexp, elmo.ErrorValue) {
+	value := elmo.EvalArgument(context, argument)
+	if value.Type() == elmo.TypeError {
+		return nil, value.(elmo.ErrorValue)
+	}
+
+	if value.IsType(typeInfoPattern) {
+		return value.Internal().(*regexp.Regexp), nil
+	}
+
+	if value.Type() != elmo.TypeString {
+		return nil, elmo.NewErrorValue(fmt.Sprintf("invalid call to %s, expected a pattern or string instead of %v", name, value))
+	}
+
+	pattern, err := compilePattern(value.String())
+	if err != nil {
+		return nil, err
+	}
+	return pattern.Internal().(*regexp.Regexp), nil
+}
+
+// submatches converts a match and its capture groups into values, groups
+// that did not participate in the match are nil
+//
+func submatches(s string, indexes []int) []elmo.Value {
+	values := make([]elmo.Value, len(indexes)/2)
+	for i := range values {
+		if indexes[2*i] < 0 {
+			values[i] = elmo.Nothing
+		} else {
+			values[i] = elmo.NewStringLiteral(s[indexes[2*i]:indexes[2*i+1]])
+		}
+	}
+	return values
+}
+
+func regex() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("regex", `compiles a regular expression
+	usage str.regex <string>
+
+	all regular expression functions accept both compiled patterns and strings,
+	strings are compiled once and cached
+
+	example:
+
+	string: (load string)
+	digits: (string.regex "[0-9]+")
+	string.match "chipotle 42" $digits |eq ["42"] |assert
+	`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		_, err := elmo.CheckArguments(arguments, 1, 1, "regex", "<string>")
+		if err != nil {
+			return err
+		}
+
+		source := elmo.EvalArgument(context, arguments[0])
+		if source.Type() == elmo.TypeError {
+			return source
+		}
+
+		pattern, err := compilePattern(source.String())
+		if err != nil {
+			return err
+		}
+		return pattern
+	})
+}
+
+func match() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("match", `finds the first match of a regular expression
+	usage str.match <string> <pattern>
+	returns a list with the match followed by its capture groups or nil when nothing matches
+
+	example:
+
+	string: (load string)
+	string.match "chipotle: 5" "(\\w+): (\\d+)" |eq ["chipotle: 5" "chipotle" "5"] |assert
+	`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		_, err := elmo.CheckArguments(arguments, 2, 2, "match", "<string> <pattern>")
+		if err != nil {
+			return err
+		}
+
+		s := elmo.EvalArgument2String(context, arguments[0])
+		pattern, err := evalPattern(context, "match", arguments[1])
+		if err != nil {
+			return err
+		}
+
+		indexes := pattern.FindStringSubmatchIndex(s)
+		if indexes == nil {
+			return elmo.Nothing
+		}
+		return elmo.NewListValue(submatches(s, indexes))
+	})
+}
+
+func matchAll() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("matchAll", `finds all matches of a regular expression
+	usage str.matchAll <string> <pattern>
+	returns a list with, for every match, a list with the match followed by its capture groups
+
+	example:
+
+	string: (load string)
+	string.matchAll "a1 b2" "([a-z])(\\d)" |eq [["a1" "a" "1"] ["b2" "b" "2"]] |assert
+	`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		_, err := elmo.CheckArguments(arguments, 2, 2, "matchAll", "<string> <pattern>")
+		if err != nil {
+			return err
+		}
+
+		s := elmo.EvalArgument2String(context, arguments[0])
+		pattern, err := evalPattern(context, "matchAll", arguments[1])
+		if err != nil {
+			return err
+		}
+
+		result := []elmo.Value{}
+		for _, indexes := range pattern.FindAllStringSubmatchIndex(s, -1) {
+			result = append(result, elmo.NewListValue(submatches(s, indexes)))
+		}
+		return elmo.NewListValue(result)
+	})
+}
+
+func groups() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("groups", `extracts the named groups of the first match of a regular expression
+	usage str.groups <string> <pattern>
+	returns a dictionary with the values of all named groups or nil when nothing matches
+
+	example:
+
+	string: (load string)
+	pepper: (string.groups "chipotle: 5" "(?P<name>\\w+): (?P<hotness>\\d+)")
+	eq $pepper.hotness "5" |assert
+	`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		_, err := elmo.CheckArguments(arguments, 2, 2, "groups", "<string> <pattern>")
+		if err != nil {
+			return err
+		}
+
+		s := elmo.EvalArgument2String(context, arguments[0])
+		pattern, err := evalPattern(context, "groups", arguments[1])
+		if err != nil {
+			return err
+		}
+
+		indexes := pattern.FindStringSubmatchIndex(s)
+		if indexes == nil {
+			return elmo.Nothing
+		}
+
+		values := submatches(s, indexes)
+		mapping := make(map[string]elmo.Value)
+		names := []string{}
+		for i, name := range pattern.SubexpNames() {
+			if name != "" {
+				mapping[name] = values[i]
+				names = append(names, name)
+			}
+		}
+		return elmo.NewOrderedDictionaryValue(nil, names, mapping)
+	})
+}
+
+func replaceRegex() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("replaceRegex", `replaces all matches of a regular expression
+	usage str.replaceRegex <string> <pattern> <replacement>
+
+	the replacement can be a string in which $1 or ${name} refer to capture groups,
+	or a function that is called with the match and its capture groups as
+	arguments and which result replaces the match
+
+	example:
+
+	string: (load string)
+	string.replaceRegex "chipotle 5" "(\\d+)" "[$1]" |eq "chipotle [5]" |assert
+	string.replaceRegex "chipotle" "[aeiou]" (func m { string.upper $m }) |eq "chIpOtlE" |assert
+	`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		_, err := elmo.CheckArguments(arguments, 3, 3, "replaceRegex", "<string> <pattern> <replacement>")
+		if err != nil {
+			return err
+		}
+
+		s := elmo.EvalArgument2String(context, arguments[0])
+		pattern, err := evalPattern(context, "replaceRegex", arguments[1])
+		if err != nil {
+			return err
+		}
+
+		replacement := elmo.EvalArgument(context, arguments[2])
+		if replacement.Type() == elmo.TypeError {
+			return replacement
+		}
+
+		runnable, isRunnable := replacement.(elmo.Runnable)
+		if replacement.Type() != elmo.TypeGoFunction || !isRunnable {
+			return elmo.NewStringLiteral(pattern.ReplaceAllString(s, replacement.String()))
+		}
+
+		var buffer []byte
+		last := 0
+		for _, indexes := range pattern.FindAllStringSubmatchIndex(s, -1) {
+			values := submatches(s, indexes)
+			callArguments := make([]elmo.Argument, len(values))
+			for i, value := range values {
+				callArguments[i] = elmo.NewDynamicArgument(value)
+			}
+
+			replaced := runnable.Run(context, callArguments)
+			if replaced.Type() == elmo.TypeError {
+				return replaced
+			}
+
+			buffer = append(buffer, s[last:indexes[0]]...)
+			buffer = append(buffer, replaced.String()...)
+			last = indexes[1]
+		}
+		buffer = append(buffer, s[last:]...)
+
+		return elmo.NewStringLiteral(string(buffer))
+	})
+}
+
+func splitRegex() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("splitRegex", `splits a string around the matches of a regular expression
+	usage str.splitRegex <string> <pattern> <max>?
+
+	when max is given, the string is split in at most max parts
+
+	example:
+
+	string: (load string)
+	string.splitRegex "chipotle, jalapeno ,habanero" "\\s*,\\s*" |eq ["chipotle" "jalapeno" "habanero"] |assert
+	`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		argLen, err := elmo.CheckArguments(arguments, 2, 3, "splitRegex", "<string> <pattern> <max>?")
+		if err != nil {
+			return err
+		}
+
+		s := elmo.EvalArgument2String(context, arguments[0])
+		pattern, err := evalPattern(context, "splitRegex", arguments[1])
+		if err != nil {
+			return err
+		}
+
+		max := -1
+		if argLen == 3 {
+			value := elmo.EvalArgument(context, arguments[2])
+			if value.Type() != elmo.TypeInteger || value.Internal().(int64) < 1 || value.Internal().(int64) > math.MaxInt32 {
+				return elmo.NewErrorValue(fmt.Sprintf("invalid call to splitRegex, expected a positive maximum instead of %v", value))
+			}
+			max = int(value.Internal().(int64))
+		}
+
+		splitted := pattern.Split(s, max)
+		values := make([]elmo.Value, len(splitted))
+		for i, v := range splitted {
+			values[i] = elmo.NewStringLiteral(v)
+		}
+		return elmo.NewListValue(values)
+	})
+}
@@ -36,13 +36,29 @@ func initModule(context elmo.RunContext) elmo.Value {
 		padLeft(),
 		padRight(),
 		padBoth(),
+		regex(),
+		match(),
+		matchAll(),
+		groups(),
+		replaceRegex(),
+		splitRegex(),
+		length(),
+		graphemes(),
+		format(),
 	})
 }
 
 func at() elmo.NamedValue {
-	return elmo.NewGoFunctionWithHelp("at", `get character at position`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+	return elmo.NewGoFunctionWithKeywords("at", `get character at position`, []string{"graphemes"}, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
 
-		_, err := elmo.CheckArguments(arguments, 2, 3, "at", "<string> <from> <to>?")
+		arguments, keywords := elmo.SplitArguments(arguments)
+
+		_, err := elmo.CheckArguments(arguments, 2, 3, "at", "<string> <from> <to>? graphemes=<boolean>?")
+		if err != nil {
+			return err
+		}
+
+		graphemes, err := unitOptions(context, "at", keywords)
 		if err != nil {
 			return err
 		}
@@ -51,6 +67,9 @@ func at() elmo.NamedValue {
 		if str.Type() == elmo.TypeError {
 			return str
 		}
+		if graphemes {
+			return unitAt(context, splitGraphemes(str.String()), arguments[1:])
+		}
 		if str.Type() == elmo.TypeString {
 			return str.(elmo.Runnable).Run(context, arguments[1:])
 		}
@@ -194,54 +213,83 @@ func getFindArgs(context elmo.RunContext, arguments []elmo.Argument) (string, st
 }
 
 func findFirst() elmo.NamedValue {
-	return elmo.NewGoFunctionWithHelp("findFirst", `find the index of the first occurences of a given text within a string`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+	return elmo.NewGoFunctionWithKeywords("findFirst", `find the index of the first occurences of a given text within a string`, []string{"graphemes"}, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		arguments, keywords := elmo.SplitArguments(arguments)
 
-		_, err := elmo.CheckArguments(arguments, 2, 2, "findFirst", "<string> <value>")
+		_, err := elmo.CheckArguments(arguments, 2, 2, "findFirst", "<string> <value> graphemes=<boolean>?")
+		if err != nil {
+			return err
+		}
+
+		graphemes, err := unitOptions(context, "findFirst", keywords)
 		if err != nil {
 			return err
 		}
 
 		value, what := getFindArgs(context, arguments)
 
-		return elmo.NewIntegerLiteral(int64(strings.Index(value, what)))
+		foundAt := strings.Index(value, what)
+		if foundAt < 0 {
+			return elmo.NewIntegerLiteral(-1)
+		}
+		return elmo.NewIntegerLiteral(int64(unitIndexer(value, graphemes)(foundAt)))
 
 	})
 }
 
 func findLast() elmo.NamedValue {
-	return elmo.NewGoFunctionWithHelp("findLast", `find the index of the last occurences of a given text within a string`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+	return elmo.NewGoFunctionWithKeywords("findLast", `find the index of the last occurences of a given text within a string`, []string{"graphemes"}, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
 
-		_, err := elmo.CheckArguments(arguments, 2, 2, "findLast", "<string> <value>")
+		arguments, keywords := elmo.SplitArguments(arguments)
+
+		_, err := elmo.CheckArguments(arguments, 2, 2, "findLast", "<string> <value> graphemes=<boolean>?")
+		if err != nil {
+			return err
+		}
+
+		graphemes, err := unitOptions(context, "findLast", keywords)
 		if err != nil {
 			return err
 		}
 
 		value, what := getFindArgs(context, arguments)
 
-		return elmo.NewIntegerLiteral(int64(strings.LastIndex(value, what)))
+		foundAt := strings.LastIndex(value, what)
+		if foundAt < 0 {
+			return elmo.NewIntegerLiteral(-1)
+		}
+		return elmo.NewIntegerLiteral(int64(unitIndexer(value, graphemes)(foundAt)))
 
 	})
 }
 
 func findAll() elmo.NamedValue {
-	return elmo.NewGoFunctionWithHelp("findAll", `find all indexes of the last occurences of a given text within a string`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+	return elmo.NewGoFunctionWithKeywords("findAll", `find all indexes of the last occurences of a given text within a string`, []string{"graphemes"}, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		arguments, keywords := elmo.SplitArguments(arguments)
+
+		_, err := elmo.CheckArguments(arguments, 2, 2, "findAll", "<string> <value> graphemes=<boolean>?")
+		if err != nil {
+			return err
+		}
 
-		_, err := elmo.CheckArguments(arguments, 2, 2, "findAll", "<string> <value>")
+		graphemes, err := unitOptions(context, "findAll", keywords)
 		if err != nil {
 			return err
 		}
 
 		value, what := getFindArgs(context, arguments)
+		unitIndex := unitIndexer(value, graphemes)
 
 		whatLen := len(what)
 		result := []elmo.Value{}
 		foundAt := strings.Index(value, what)
 		at := 0
 		for foundAt >= 0 {
-			result = append(result, elmo.NewIntegerLiteral(int64(at+foundAt)))
+			result = append(result, elmo.NewIntegerLiteral(int64(unitIndex(at+foundAt))))
 			at = at + foundAt + whatLen
-			value = value[foundAt+whatLen:]
-			foundAt = strings.Index(value, what)
+			foundAt = strings.Index(value[at:], what)
 		}
 		return elmo.NewListValue(result)
 	})
@@ -271,9 +319,16 @@ func count() elmo.NamedValue {
 }
 
 func split() elmo.NamedValue {
-	return elmo.NewGoFunctionWithHelp("split", `split a string / reverse joins`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+	return elmo.NewGoFunctionWithKeywords("split", `split a string / reverse joins`, []string{"graphemes"}, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		arguments, keywords := elmo.SplitArguments(arguments)
 
-		argLen, err := elmo.CheckArguments(arguments, 1, 2, "split", "<string> <value>?")
+		argLen, err := elmo.CheckArguments(arguments, 1, 2, "split", "<string> <value>? graphemes=<boolean>?")
+		if err != nil {
+			return err
+		}
+
+		graphemes, err := unitOptions(context, "split", keywords)
 		if err != nil {
 			return err
 		}
@@ -292,7 +347,12 @@ func split() elmo.NamedValue {
 			splitBy = by.String()
 		}
 
-		splitted := strings.Split(str.String(), splitBy)
+		var splitted []string
+		if splitBy == "" {
+			splitted = splitUnits(str.String(), graphemes)
+		} else {
+			splitted = strings.Split(str.String(), splitBy)
+		}
 		values := make([]elmo.Value, len(splitted))
 		for i, v := range splitted {
 			values[i] = elmo.NewStringLiteral(v)
@@ -388,72 +448,87 @@ func lower() elmo.NamedValue {
 	})
 }
 
-func applyPadRight(str string, l int, pad string) string {
+func applyPadRight(str []string, l int, pad []string) string {
 	if l <= 0 {
 		return ""
 	}
 	for {
-		str += pad
+		str = append(str, pad...)
 		if len(str) > l {
-			return str[0:l]
+			return strings.Join(str[0:l], "")
 		}
 	}
 }
 
-func applyPadLeft(str string, l int, pad string) string {
+func applyPadLeft(str []string, l int, pad []string) string {
 	if l <= 0 {
 		return ""
 	}
 	for {
-		str = pad + str
+		str = append(append([]string{}, pad...), str...)
 		if len(str) > l {
-			return str[len(str)-l:]
+			return strings.Join(str[len(str)-l:], "")
 		}
 	}
 }
 
-func applyPadBoth(str string, l int, pad string) string {
+func applyPadBoth(str []string, l int, pad []string) string {
 	if l <= 0 {
 		return ""
 	}
 	for {
-		str = pad + str + pad
+		str = append(append(append([]string{}, pad...), str...), pad...)
 		if len(str) > l {
 			mid := len(str) / 2
 			start := mid - (l / 2)
-			return str[start : start+l]
+			return strings.Join(str[start:start+l], "")
 		}
 	}
 }
 
-func getPadArgs(context elmo.RunContext, arguments []elmo.Argument) (string, int, string, elmo.ErrorValue) {
+// getPadArgs evaluates the arguments of the padding functions, the string and
+// its padding are split into runes or grapheme clusters so padding is
+// measured in characters instead of bytes
+//
+func getPadArgs(context elmo.RunContext, name string, arguments []elmo.Argument) ([]string, int, []string, elmo.ErrorValue) {
+
+	arguments, keywords := elmo.SplitArguments(arguments)
+
+	_, err := elmo.CheckArguments(arguments, 2, 3, name, "<string> <length> <padding>? graphemes=<boolean>?")
+	if err != nil {
+		return nil, -1, nil, err
+	}
+
+	graphemes, err := unitOptions(context, name, keywords)
+	if err != nil {
+		return nil, -1, nil, err
+	}
+
 	value := elmo.EvalArgument2String(context, arguments[0])
 	length := elmo.EvalArgument(context, arguments[1])
 	var lengthAsInt int
 	if length.Type() == elmo.TypeInteger {
 		lengthAsInt = int(length.Internal().(int64))
 	} else {
-		return "", -1, "", elmo.NewErrorValue("padding expects and integer length")
+		return nil, -1, nil, elmo.NewErrorValue("padding expects and integer length")
 	}
 
 	padding := " "
 	if len(arguments) == 3 {
 		padding = elmo.EvalArgument2String(context, arguments[2])
 	}
+	if padding == "" {
+		return nil, -1, nil, elmo.NewErrorValue("padding can not be empty")
+	}
 
-	return value, lengthAsInt, padding, nil
+	return splitUnits(value, graphemes), lengthAsInt, splitUnits(padding, graphemes), nil
 
 }
 
 func padLeft() elmo.NamedValue {
-	return elmo.NewGoFunctionWithHelp("padLeft", `add padding to the beginning of a string`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
-
-		_, err := elmo.CheckArguments(arguments, 2, 3, "padLeft", "<string> <length> <padding>?")
-		if err != nil {
-			return err
-		}
+	return elmo.NewGoFunctionWithKeywords("padLeft", `add padding to the beginning of a string`, []string{"graphemes"}, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
 
-		value, length, padding, err := getPadArgs(context, arguments)
+		value, length, padding, err := getPadArgs(context, "padLeft", arguments)
 		if err != nil {
 			return err
 		}
@@ -464,37 +539,67 @@ func padLeft() elmo.NamedValue {
 }
 
 func padRight() elmo.NamedValue {
-	return elmo.NewGoFunctionWithHelp("padRight", `add padding to the end of a string`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+	return elmo.NewGoFunctionWithKeywords("padRight", `add padding to the end of a string`, []string{"graphemes"}, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
 
-		_, err := elmo.CheckArguments(arguments, 2, 3, "padRight", "<string> <length> <padding>?")
+		value, length, padding, err := getPadArgs(context, "padRight", arguments)
 		if err != nil {
 			return err
 		}
 
-		value, length, padding, err := getPadArgs(context, arguments)
+		return elmo.NewStringLiteral(applyPadRight(value, length, padding))
+
+	})
+}
+
+func padBoth() elmo.NamedValue {
+	return elmo.NewGoFunctionWithKeywords("padBoth", `add padding to both the beginning and the end of a string`, []string{"graphemes"}, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		value, length, padding, err := getPadArgs(context, "padBoth", arguments)
 		if err != nil {
 			return err
 		}
 
-		return elmo.NewStringLiteral(applyPadRight(value, length, padding))
+		return elmo.NewStringLiteral(applyPadBoth(value, length, padding))
 
 	})
 }
 
-func padBoth() elmo.NamedValue {
-	return elmo.NewGoFunctionWithHelp("padBoth", `add padding to both the beginning and the end of a string`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+func format() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("format", `formats values using printf-style verbs
+	usage str.format <format> <value>*
+
+	%s, %q and %v use the textual representation of a value, %d, %x, %f, %e, %g
+	and %t use its number or boolean. Width, precision and flags are supported
+	like in %-10s or %08.3f
+
+	example:
 
-		_, err := elmo.CheckArguments(arguments, 2, 3, "padBoth", "<string> <length> <padding>?")
+	string: (load string)
+	string.format "%s costs %.2f" "chipotle" 3.14159 |eq "chipotle costs 3.14" |assert
+	`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		argLen, err := elmo.CheckArguments(arguments, 1, math.MaxInt16, "format", "<format> <value>*")
 		if err != nil {
 			return err
 		}
 
-		value, length, padding, err := getPadArgs(context, arguments)
-		if err != nil {
+		format := elmo.EvalArgument(context, arguments[0])
+		if format.Type() == elmo.TypeError {
+			return format
+		}
+
+		if err := elmo.CheckFormat(format.String(), argLen-1); err != nil {
 			return err
 		}
 
-		return elmo.NewStringLiteral(applyPadBoth(value, length, padding))
+		values := make([]elmo.Value, argLen-1)
+		for i := 1; i < argLen; i++ {
+			values[i-1] = elmo.EvalArgument(context, arguments[i])
+			if values[i-1].Type() == elmo.TypeError {
+				return values[i-1]
+			}
+		}
 
+		return elmo.NewStringLiteral(elmo.FormatValues(format.String(), values))
 	})
 }
@@ -1,6 +1,7 @@
 package str
 
 import (
+	"fmt"
 	"testing"
 
 	elmo "github.com/okke/elmo/core"
@@ -346,3 +347,237 @@ func TestPadBoth(t *testing.T) {
 		  str.padBoth "sou" 8 "+" |eq "+++sou++" |assert`, elmo.ExpectValue(t, elmo.True))
 
 }
+
+func TestRegex(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.regex "(chipotle"`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 type (str.regex "[0-9]+")`, elmo.ExpectValue(t, elmo.NewIdentifier("pattern")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 digits: (str.regex "[0-9]+")
+		 str.match "chipotle 42" $digits`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), `["42"]`)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.match "chipotle" 42`, elmo.ExpectErrorValueAt(t, 2))
+}
+
+func TestMatch(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.match "chipotle: 5" "(\\w+): (\\d+)"`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), `["chipotle: 5" "chipotle" "5"]`)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.match "chipotle" "\\d+"`, elmo.ExpectNothing(t))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.match "chipotle" "(chi)(x)?"`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), `["chi" "chi" $nil]`)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.matchAll "a1 b2" "([a-z])(\\d)"`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), `[["a1" "a" "1"] ["b2" "b" "2"]]`)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.matchAll "chipotle" "\\d"`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), `[]`)))
+}
+
+func TestGroups(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 pepper: (str.groups "chipotle: 5" "(?P<name>\\w+): (?P<hotness>\\d+)")
+		 [$pepper.name $pepper.hotness]`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), `["chipotle" "5"]`)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.groups "chipotle" "(?P<hotness>\\d+)"`, elmo.ExpectNothing(t))
+}
+
+func TestReplaceRegex(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.replaceRegex "chipotle 5 jalapeno 3" "(\\d+)" "[$1]"`, elmo.ExpectValue(t, elmo.NewStringLiteral("chipotle [5] jalapeno [3]")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.replaceRegex "chipotle" "[aeiou]" (func m { str.upper $m })`, elmo.ExpectValue(t, elmo.NewStringLiteral("chIpOtlE")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.replaceRegex "chipotle:5,jalapeno:3" "(\\w+):(\\d)" (func m name hotness { return "\{$hotness}=\{$name}" })`,
+		elmo.ExpectValue(t, elmo.NewStringLiteral("5=chipotle,3=jalapeno")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.replaceRegex "chipotle" "i" (func m { error "no peppers" })`, elmo.ExpectErrorValueAt(t, 2))
+}
+
+func TestSplitRegex(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.splitRegex "chipotle, jalapeno ,habanero" "\\s*,\\s*"`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), `["chipotle" "jalapeno" "habanero"]`)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.splitRegex "a1b2c" "\\d" 2`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), `["a" "b2c"]`)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.splitRegex "a1b2c" "\\d" 0`, elmo.ExpectErrorValueAt(t, 2))
+}
+
+func TestPatternCacheIsBounded(t *testing.T) {
+
+	context := strContext()
+	for i := 0; i < 2*patternCacheSize; i++ {
+		elmo.ParseTestAndRunBlockWithinContext(t, context,
+			fmt.Sprintf(`str: (load "string")
+			 str.match "chipotle %d" "chipotle %d"`, i, i), elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), fmt.Sprintf(`["chipotle %d"]`, i))))
+	}
+	if patternCache.Len() > patternCacheSize {
+		t.Errorf("expected at most %d cached patterns, found %d", patternCacheSize, patternCache.Len())
+	}
+}
+
+func TestUnicodePadding(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.padLeft "jalapeño" 10 "·"`, elmo.ExpectValue(t, elmo.NewStringLiteral("··jalapeño")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.padRight "jalapeño" 7`, elmo.ExpectValue(t, elmo.NewStringLiteral("jalapeñ")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.padBoth "ñ" 5 "¿?"`, elmo.ExpectValue(t, elmo.NewStringLiteral("¿?ñ¿?")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.padLeft "`+"é"+`" 3 "-" graphemes=true`, elmo.ExpectValue(t, elmo.NewStringLiteral("--é")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.padLeft "soup" 8 ""`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.padLeft "soup" 8 graphemes="yes"`, elmo.ExpectErrorValueAt(t, 2))
+}
+
+func TestUnicodeFind(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.findFirst "jalapeño in a jar" "in"`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(9)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.findLast "ñoño" "ñ"`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(2)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.findAll "ñañaña" "ña"`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), "[0 2 4]")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.findFirst "`+"été"+`" "t" graphemes=true`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(1)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.findFirst "chipotle" "x"`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(-1)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.findFirst "chipotle" "o" runes=true`, elmo.ExpectErrorValueAt(t, 2))
+}
+
+func TestGraphemes(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.graphemes "`+"née\U0001F1F3\U0001F1F1\U0001F44D\U0001F3FD"+`"`,
+		elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), `["n" "`+"é"+`" "e" "`+"\U0001F1F3\U0001F1F1"+`" "`+"\U0001F44D\U0001F3FD"+`"]`)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.len "`+"\U0001F469‍\U0001F373"+`" graphemes=true`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(1)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.len "`+"\U0001F469‍\U0001F373"+`"`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(3)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.at "`+"née"+`" 1 graphemes=true`, elmo.ExpectValue(t, elmo.NewStringLiteral("é")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.at "`+"née"+`" -1 0 graphemes=true`, elmo.ExpectValue(t, elmo.NewStringLiteral("eén")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.split "`+"née"+`" "" graphemes=true`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), `["n" "`+"é"+`" "e"]`)))
+}
+
+func TestFormat(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.format "%s costs %.2f" "chipotle" 3.14159`, elmo.ExpectValue(t, elmo.NewStringLiteral("chipotle costs 3.14")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.format "|%-8s|%5d|%x|%t|" "soup" 42 255 $true`, elmo.ExpectValue(t, elmo.NewStringLiteral("|soup    |   42|ff|true|")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.format "%v %q" [1 2] "hot"`, elmo.ExpectValue(t, elmo.NewStringLiteral(`[1 2] "hot"`)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.format "%d|%.1e" 123456789012345678901234567890 1234.5`, elmo.ExpectValue(t, elmo.NewStringLiteral("123456789012345678901234567890|1.2e+03")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.format`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.format "%d%% of %4d" 50 8`, elmo.ExpectValue(t, elmo.NewStringLiteral("50% of    8")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.format "%*d" 4 8`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.format "%s costs %d" "chipotle"`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, strContext(),
+		`str: (load "string")
+		 str.format "%s" "chipotle" 3`, elmo.ExpectErrorValueAt(t, 2))
+}
+
+func TestSplitStaysWithinCollectionSize(t *testing.T) {
+
+	context := strContext()
+	context.SetLimits(elmo.Limits{MaxCollectionSize: 3})
+
+	elmo.ParseTestAndRunBlockWithinContext(t, context,
+		`str: (load "string")
+		 str.split "a,b,c,d,e" ","`, elmo.ExpectAbortErrorValue(t))
+}
@@ -0,0 +1,233 @@
+package str
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	elmo "github.com/okke/elmo/core"
+)
+
+const zeroWidthJoiner = '\u200d'
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+func isEmojiModifier(r rune) bool {
+	return r >= 0x1f3fb && r <= 0x1f3ff
+}
+
+// extendsCluster tells if a rune belongs to the grapheme cluster of the
+// runes before it
+//
+func extendsCluster(cluster []rune, r rune) bool {
+	previous := cluster[len(cluster)-1]
+
+	switch {
+	case previous == '\r':
+		return r == '\n'
+	case previous == zeroWidthJoiner:
+		return true
+	case r == zeroWidthJoiner, isEmojiModifier(r), unicode.Is(unicode.M, r):
+		return true
+	case isRegionalIndicator(r):
+		// flags are pairs of regional indicators
+		//
+		return len(cluster) == 1 && isRegionalIndicator(previous)
+	}
+	return false
+}
+
+// splitGraphemes splits a string into user-perceived characters. It
+// approximates the unicode segmentation rules by keeping combining marks,
+// emoji modifiers, zero width joiner sequences, flags and \r\n together
+//
+func splitGraphemes(s string) []string {
+	clusters := []string{}
+	var cluster []rune
+	for _, r := range s {
+		if cluster != nil && !extendsCluster(cluster, r) {
+			clusters = append(clusters, string(cluster))
+			cluster = nil
+		}
+		cluster = append(cluster, r)
+	}
+	if cluster != nil {
+		clusters = append(clusters, string(cluster))
+	}
+	return clusters
+}
+
+// splitUnits splits a string into runes or, when asked for, into grapheme
+// clusters
+//
+func splitUnits(s string, graphemes bool) []string {
+	if graphemes {
+		return splitGraphemes(s)
+	}
+	units := make([]string, 0, len(s))
+	for _, r := range s {
+		units = append(units, string(r))
+	}
+	return units
+}
+
+// unitIndexer returns a function that converts byte indexes in a string
+// into rune or grapheme indexes
+//
+func unitIndexer(s string, graphemes bool) func(int) int {
+	if !graphemes {
+		return func(index int) int {
+			return utf8.RuneCountInString(s[:index])
+		}
+	}
+
+	ends := []int{}
+	end := 0
+	for _, cluster := range splitGraphemes(s) {
+		end = end + len(cluster)
+		ends = append(ends, end)
+	}
+	return func(index int) int {
+		units := 0
+		for units < len(ends) && ends[units] <= index {
+			units = units + 1
+		}
+		return units
+	}
+}
+
+// unitOptions takes the options of string functions from the keyword arguments
+// of a call. Only graphemes=true|false is supported which makes the function
+// work with grapheme clusters instead of runes
+//
+func unitOptions(context elmo.RunContext, name string, keywords map[string]elmo.Argument) (bool, elmo.ErrorValue) {
+	graphemes := false
+
+	for keyword, argument := range keywords {
+		if keyword != "graphemes" {
+			return false, elmo.NewErrorValue(fmt.Sprintf("invalid call to %s, unknown option %s", name, keyword))
+		}
+
+		// accept both booleans and the bare words true and false
+		//
+		value := elmo.EvalArgument(context, argument)
+		switch {
+		case value.Type() == elmo.TypeBoolean:
+			graphemes = value.Internal().(bool)
+		case (value.Type() == elmo.TypeIdentifier || value.Type() == elmo.TypeString) && (value.String() == "true" || value.String() == "false"):
+			graphemes = value.String() == "true"
+		default:
+			return false, elmo.NewErrorValue(fmt.Sprintf("invalid call to %s, graphemes should be true or false", name))
+		}
+	}
+
+	return graphemes, nil
+}
+
+// unitAt mimics string accessors on a string split in units
+//
+func unitAt(context elmo.RunContext, units []string, arguments []elmo.Argument) elmo.Value {
+
+	indexes := make([]int, len(arguments))
+	for a, argument := range arguments {
+		index := elmo.EvalArgument(context, argument)
+		if index.Type() != elmo.TypeInteger {
+			return elmo.NewErrorValue("string accessor must be an integer")
+		}
+
+		i := int(index.Internal().(int64))
+		if i < 0 {
+			i = len(units) + i
+		}
+		if i < 0 || i >= len(units) {
+			return elmo.NewErrorValue("string accessor out of bounds")
+		}
+		indexes[a] = i
+	}
+
+	if len(indexes) == 1 {
+		return elmo.NewStringLiteral(units[indexes[0]])
+	}
+
+	if indexes[0] > indexes[1] {
+		var sb strings.Builder
+		for i := indexes[0]; i >= indexes[1]; i-- {
+			sb.WriteString(units[i])
+		}
+		return elmo.NewStringLiteral(sb.String())
+	}
+
+	return elmo.NewStringLiteral(strings.Join(units[indexes[0]:indexes[1]+1], ""))
+}
+
+func length() elmo.NamedValue {
+	return elmo.NewGoFunctionWithKeywords("len", `get the number of characters of a string
+	usage str.len <string> graphemes=<boolean>?
+
+	characters are runes or, with graphemes=true, user-perceived characters
+
+	example:
+
+	string: (load string)
+	string.len "jalapeño" |eq 8 |assert
+	string.len "🇳🇱" graphemes=true |eq 1 |assert
+	`, []string{"graphemes"}, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		arguments, keywords := elmo.SplitArguments(arguments)
+
+		_, err := elmo.CheckArguments(arguments, 1, 1, "len", "<string> graphemes=<boolean>?")
+		if err != nil {
+			return err
+		}
+
+		graphemes, err := unitOptions(context, "len", keywords)
+		if err != nil {
+			return err
+		}
+
+		value := elmo.EvalArgument(context, arguments[0])
+		if value.Type() == elmo.TypeError {
+			return value
+		}
+
+		if graphemes {
+			return elmo.NewIntegerLiteral(int64(len(splitGraphemes(value.String()))))
+		}
+		return elmo.NewIntegerLiteral(int64(utf8.RuneCountInString(value.String())))
+	})
+}
+
+func graphemes() elmo.NamedValue {
+	return elmo.NewGoFunctionWithHelp("graphemes", `splits a string into user-perceived characters
+	usage str.graphemes <string>
+
+	combining marks, emoji modifiers, emoji joined by zero width joiners
+	and flags are kept together
+
+	example:
+
+	string: (load string)
+	string.graphemes "🇳🇱!" |eq ["🇳🇱" "!"] |assert
+	`, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {
+
+		_, err := elmo.CheckArguments(arguments, 1, 1, "graphemes", "<string>")
+		if err != nil {
+			return err
+		}
+
+		value := elmo.EvalArgument(context, arguments[0])
+		if value.Type() == elmo.TypeError {
+			return value
+		}
+
+		clusters := splitGraphemes(value.String())
+		values := make([]elmo.Value, len(clusters))
+		for i, cluster := range clusters {
+			values[i] = elmo.NewStringLiteral(cluster)
+		}
+		return elmo.NewListValue(values)
+	})
+}
@@ -27,3 +27,19 @@ func setEnvVar(name, value string) {
 	os.Setenv(name, value)
 	allEnvironmentVariables.Set(elmo.NewStringLiteral(name), elmo.NewStringLiteral(os.Getenv(name)))
 }
+
+// permittedEnvironmentVariables returns a dictionary with the environment
+// variables given policy permits to read
+//
+func permittedEnvironmentVariables(policy *elmo.Policy) elmo.DictionaryValue {
+
+	mapping := make(map[string]elmo.Value)
+
+	for _, name := range policy.Environment {
+		if value, found := allEnvironmentVariables.Resolve(name); found {
+			mapping[name] = value
+		}
+	}
+
+	return elmo.NewDictionaryValue(nil, mapping)
+}
@@ -2,6 +2,7 @@ package sys
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"os/exec"
@@ -12,6 +13,7 @@ import (
 var typeInfoCommand = elmo.NewTypeInfo("command")
 
 type command struct {
+	ctx      context.Context
 	pipeFrom Command
 	cmd      *exec.Cmd
 }
@@ -26,6 +28,7 @@ type running struct {
 //
 type Command interface {
 	elmo.Listable
+	elmo.IterableValue
 	Execute() elmo.Value
 	Pipe() (Running, error)
 }
@@ -112,23 +115,18 @@ func (command *command) Pipe() (Running, error) {
 
 func (command *command) Execute() elmo.Value {
 
-	// run piped commands first
-	//
-	run, err := command.Pipe()
-	if err != nil {
-		return elmo.NewErrorValue(err.Error())
-	}
+	iterator := command.Iterate()
 
 	// ensure pipes are closed when ready
 	//
-	defer run.CloseAll()
+	defer iterator.Close()
 
-	// read from Stdout and convert to elmo values
-	//
-	scanner := bufio.NewScanner(run.Stdout())
 	var result = []elmo.Value{}
-	for scanner.Scan() {
-		result = append(result, elmo.NewStringLiteral(scanner.Text()))
+	for value, ok := iterator.Next(); ok; value, ok = iterator.Next() {
+		if value.Type() == elmo.TypeError {
+			return value
+		}
+		result = append(result, value)
 	}
 
 	// return values as list
@@ -136,18 +134,47 @@ func (command *command) Execute() elmo.Value {
 	return elmo.NewListValue(result)
 }
 
+// Iterate runs the command and produces its output line by line, so
+// output is never kept in memory as a whole
+//
+func (command *command) Iterate() elmo.Iterator {
+
+	// run piped commands first
+	//
+	run, err := command.Pipe()
+	if err != nil {
+		return elmo.NewErrorIterator(elmo.NewErrorValue(err.Error()))
+	}
+
+	// read from Stdout and convert to elmo values
+	//
+	scanner := bufio.NewScanner(run.Stdout())
+	return elmo.NewIterator(func() (elmo.Value, bool) {
+		if scanner.Scan() {
+			return elmo.NewStringLiteral(scanner.Text()), true
+		}
+
+		// a cancelled command is killed, so its output is incomplete
+		//
+		if command.ctx.Err() != nil {
+			return elmo.NewAbortErrorValue(fmt.Sprintf("execution cancelled: %v", command.ctx.Err())), true
+		}
+		return nil, false
+	}, run.CloseAll)
+}
+
 func (command *command) List() []elmo.Value {
 	return command.Execute().Internal().([]elmo.Value)
 }
 
-// NewCommand creates a new Command
+// NewCommand creates a new Command that is killed when given context is done
 //
-func NewCommand(pipeFrom Command, name string, args []string) Command {
-	return &command{pipeFrom: pipeFrom, cmd: exec.Command(name, args...)}
+func NewCommand(ctx context.Context, pipeFrom Command, name string, args []string) Command {
+	return &command{ctx: ctx, pipeFrom: pipeFrom, cmd: exec.CommandContext(ctx, name, args...)}
 }
 
 // NewCommandValue constructs a new command as elmo value
 //
-func NewCommandValue(pipeFrom Command, name string, args []string) elmo.Value {
-	return elmo.NewInternalValue(typeInfoCommand, NewCommand(pipeFrom, name, args))
+func NewCommandValue(ctx context.Context, pipeFrom Command, name string, args []string) elmo.Value {
+	return elmo.NewInternalValue(typeInfoCommand, NewCommand(ctx, pipeFrom, name, args))
 }
@@ -1,6 +1,10 @@
 package sys
 
-import elmo "github.com/okke/elmo/core"
+import (
+	"fmt"
+
+	elmo "github.com/okke/elmo/core"
+)
 
 // Module contains system functions
 //
@@ -26,6 +30,10 @@ func undefined() elmo.NamedValue {
 		}
 
 		name := elmo.EvalArgument2String(context, arguments[0])
+
+		if policy := context.Policy(); policy != nil && !policy.AllowsCommand(name) {
+			return elmo.NewNotPermittedErrorValue(fmt.Sprintf("executing %s", name))
+		}
 		arglist := elmo.EvalArgument(context, arguments[1])
 		if arglist.Type() != elmo.TypeList {
 			return elmo.NewErrorValue("expected a list of arguments")
@@ -44,7 +52,7 @@ func undefined() elmo.NamedValue {
 			strArgs[i] = v.String()
 		}
 
-		return NewCommandValue(pipeFrom, name, strArgs)
+		return NewCommandValue(context.Context(), pipeFrom, name, strArgs)
 	})
 }
 
@@ -76,14 +84,25 @@ func env() elmo.NamedValue {
 			return err
 		}
 
+		policy := context.Policy()
+
 		if argLen == 1 {
-			value, found := allEnvironmentVariables.Resolve(elmo.EvalArgument2String(context, arguments[0]))
+			name := elmo.EvalArgument2String(context, arguments[0])
+			if policy != nil && !policy.AllowsEnvironment(name) {
+				return elmo.NewNotPermittedErrorValue(fmt.Sprintf("reading environment variable %s", name))
+			}
+
+			value, found := allEnvironmentVariables.Resolve(name)
 			if !found {
 				return elmo.Nothing
 			}
 			return value
 		}
 
+		if policy != nil {
+			return permittedEnvironmentVariables(policy)
+		}
+
 		return allEnvironmentVariables
 	})
 }
@@ -96,6 +115,10 @@ func setEnv() elmo.NamedValue {
 		}
 
 		name := elmo.EvalArgument2String(context, arguments[0])
+
+		if policy := context.Policy(); policy != nil && !policy.AllowsEnvironment(name) {
+			return elmo.NewNotPermittedErrorValue(fmt.Sprintf("setting environment variable %s", name))
+		}
 		value := elmo.EvalArgument2String(context, arguments[1])
 
 		setEnvVar(name, value)
@@ -1,7 +1,10 @@
 package sys
 
 import (
+	"context"
+	"os"
 	"testing"
+	"time"
 
 	elmo "github.com/okke/elmo/core"
 	"github.com/okke/elmo/modules/list"
@@ -22,7 +25,7 @@ func TestExec(t *testing.T) {
 
 	elmo.ParseTestAndRunBlockWithinContext(t, sysContext(),
 		`mixin (load sys)
-	   ls "./testdata" |wc "-l"|exec`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), "[\"       2\"]")))
+	   ls "./testdata" |grep "chipotle"|exec`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), "[\"chipotle.txt\"]")))
 
 	elmo.ParseTestAndRunBlockWithinContext(t, sysContext(),
 		`mixin (load sys)
@@ -33,6 +36,51 @@ func TestExec(t *testing.T) {
      ls | chipotle |exec`, elmo.ExpectErrorValueAt(t, 2))
 }
 
+func TestIterateOverOutput(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, sysContext(),
+		`mixin (load sys)
+		 list: (load list)
+		 list.map (ls "./testdata") file { len $file }`, elmo.ExpectIterated(t, elmo.ParseAndRun(elmo.NewGlobalContext(), "[12 12]")))
+
+	// output of commands is only read when needed
+	//
+	elmo.ParseTestAndRunBlockWithinContext(t, sysContext(),
+		`mixin (load sys)
+		 list: (load list)
+		 list.take (yes "chipotle") 2`, elmo.ExpectIterated(t, elmo.ParseAndRun(elmo.NewGlobalContext(), `["chipotle" "chipotle"]`)))
+}
+
+func TestExecIsKilledWhenCancelled(t *testing.T) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	sys := sysContext()
+	sys.SetContext(ctx)
+
+	elmo.ParseTestAndRunBlockWithinContext(t, sys,
+		`mixin (load sys)
+     tail "-f" "./testdata/chipotle.txt" |exec`, elmo.ExpectAbortErrorValue(t))
+}
+
+func sandboxContext() elmo.RunContext {
+	sandbox := elmo.NewSandboxContext(elmo.Policy{Commands: []string{"ls"}})
+	sandbox.RegisterModule(Module)
+	return sandbox
+}
+
+func TestExecWithinSandbox(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, sandboxContext(),
+		`mixin (load sys)
+     ls "./testdata" |exec`, elmo.ExpectValue(t, elmo.ParseAndRun(elmo.NewGlobalContext(), "[\"chipotle.txt\" \"jalapeno.txt\"]")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, sandboxContext(),
+		`mixin (load sys)
+     ls "./testdata" |grep "chipotle" |exec`, elmo.ExpectErrorValueAt(t, 2))
+}
+
 func TestAsList(t *testing.T) {
 	elmo.ParseTestAndRunBlockWithinContext(t, sysContext(),
 		`mixin (load sys)
@@ -50,3 +98,38 @@ func TestEnv(t *testing.T) {
 		setEnv "UBGDOGSUAODHUFGSGY" "chipotle"
 		(env UBGDOGSUAODHUFGSGY) |eq "chipotle" |assert`, elmo.ExpectValue(t, elmo.True))
 }
+
+func TestEnvWithinSandbox(t *testing.T) {
+
+	os.Setenv("UBGDOGSUAODHUFGSGY", "chipotle")
+
+	sandbox := func() elmo.RunContext {
+		sandbox := elmo.NewSandboxContext(elmo.Policy{Environment: []string{"UBGDOGSUAODHUFGSGY"}})
+		sandbox.RegisterModule(Module)
+		return sandbox
+	}
+
+	elmo.ParseTestAndRunBlockWithinContext(t, sandbox(),
+		`mixin (load sys)
+		 env UBGDOGSUAODHUFGSGY`, elmo.ExpectValue(t, elmo.NewStringLiteral("chipotle")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, sandbox(),
+		`mixin (load sys)
+		 env PATH`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, sandbox(),
+		`mixin (load sys)
+		 env`, func(context elmo.RunContext, result elmo.Value) {
+			if keys := result.(elmo.DictionaryValue).Keys(); len(keys) != 1 || keys[0] != "UBGDOGSUAODHUFGSGY" {
+				t.Errorf("expected only permitted environment variables, found %v", keys)
+			}
+		})
+
+	elmo.ParseTestAndRunBlockWithinContext(t, sandbox(),
+		`mixin (load sys)
+		 setEnv PATH "/tmp"`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, sandbox(),
+		`mixin (load sys)
+		 setEnv UBGDOGSUAODHUFGSGY "jalapeno"`, elmo.ExpectValue(t, elmo.NewStringLiteral("jalapeno")))
+}
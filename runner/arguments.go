@@ -1,6 +1,7 @@
 package runner
 
 import (
+	"os"
 	"strings"
 )
 
@@ -95,6 +96,11 @@ type runnerArgsSetter interface {
 }
 
 func (runnerArgs *runnerArgs) SetElmoFlag(name, value string) {
+	// include flags can be given more than once
+	//
+	if previous, found := runnerArgs.elmoFlags[name]; found && name == includeFlag {
+		value = previous + string(os.PathListSeparator) + value
+	}
 	runnerArgs.elmoFlags[name] = value
 }
 func (runnerArgs *runnerArgs) SetElmoFile(name string) {
@@ -1,6 +1,9 @@
 package runner
 
-import "testing"
+import (
+	"os"
+	"testing"
+)
 
 func TestNextArgOnEmptyArray(t *testing.T) {
 
@@ -150,3 +153,16 @@ func TestParseArguments(t *testing.T) {
 		t.Error("no debug arg found")
 	}
 }
+
+func TestParseIncludeArguments(t *testing.T) {
+
+	testSetter := newRunnerArgs()
+	parseArguments([]string{"-I=lib", "-I=vendor/lib", "chipotle.mo", "-I=user"}, testSetter)
+
+	if include := testSetter.elmoFlags[includeFlag]; include != "lib"+string(os.PathListSeparator)+"vendor/lib" {
+		t.Errorf("expected two included folders, found %s", include)
+	}
+	if include := testSetter.userFlags[includeFlag]; include != "user" {
+		t.Errorf("expected user flags to be left alone, found %s", include)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 
 	elmo "github.com/okke/elmo/core"
@@ -12,8 +13,10 @@ import (
 	"github.com/okke/elmo/modules/data"
 	dict "github.com/okke/elmo/modules/dictionary"
 	http "github.com/okke/elmo/modules/elmohttp"
+	math "github.com/okke/elmo/modules/elmomath"
 	"github.com/okke/elmo/modules/inspect"
 	"github.com/okke/elmo/modules/list"
+	"github.com/okke/elmo/modules/set"
 	"github.com/okke/elmo/modules/str"
 	"github.com/okke/elmo/modules/sys"
 
@@ -68,8 +71,17 @@ func (runner *runner) Stop() {
 // NewMainContext constructs a context with all elmo's default modules
 //
 func NewMainContext() elmo.RunContext {
-	context := elmo.NewGlobalContext()
+	return registerDefaultModules(elmo.NewGlobalContext())
+}
+
+// NewSandboxMainContext constructs a sandbox context with all elmo's default
+// modules registered. Given policy determines which of them can be loaded
+//
+func NewSandboxMainContext(policy elmo.Policy) elmo.RunContext {
+	return registerDefaultModules(elmo.NewSandboxContext(policy))
+}
 
+func registerDefaultModules(context elmo.RunContext) elmo.RunContext {
 	context.RegisterModule(str.Module)
 	context.RegisterModule(list.Module)
 	context.RegisterModule(dict.Module)
@@ -79,6 +91,8 @@ func NewMainContext() elmo.RunContext {
 	context.RegisterModule(data.Module)
 	context.RegisterModule(http.Module)
 	context.RegisterModule(inspect.Module)
+	context.RegisterModule(math.Module)
+	context.RegisterModule(set.Module)
 
 	return context
 }
@@ -225,6 +239,9 @@ func (runner *runner) read(source string) {
 	result := elmo.ParseAndRunWithFile(runner.context, string(b), source)
 	if result.Type() == elmo.TypeError {
 		fmt.Printf("error: %v\n", result)
+		for _, frame := range result.(elmo.ErrorValue).Trace() {
+			fmt.Printf("  %v\n", frame)
+		}
 	}
 }
 
@@ -247,8 +264,10 @@ func (runner *runner) storeArgs() {
 const debugFlag = "debug"
 const autoreloadFlag = "autoreload"
 const replFlag = "repl"
+const vmFlag = "vm"
 const versionFlag = "version"
 const helpFlag = "help"
+const includeFlag = "I"
 
 func help() {
 
@@ -256,7 +275,9 @@ func help() {
 	fmt.Printf("  %-15v  start elmo in debug mode\n", "-"+debugFlag)
 	fmt.Printf("  %-15v  start elmo in auto reload mode\n", "-"+autoreloadFlag)
 	fmt.Printf("  %-15v  open repl after script execution\n", "-"+replFlag)
+	fmt.Printf("  %-15v  run scripts using the bytecode vm\n", "-"+vmFlag)
 	fmt.Printf("  %-15v  print elmo's version\n", "-"+versionFlag)
+	fmt.Printf("  %-15v  load scripts from given folders, like ELMOPATH\n", "-"+includeFlag+"=<dirs>")
 }
 
 // Main starts the elmo runtime. Either in repl mode or by interpreting an elmo source file
@@ -280,6 +301,18 @@ func (runner *runner) Main() {
 	_, elmo.GlobalSettings().HotReload = runner.arguments.elmoFlags[autoreloadFlag]
 	_, elmo.GlobalSettings().StartRepl = runner.arguments.elmoFlags[replFlag]
 
+	// vm can also be enabled by setting ELMO_VM
+	//
+	if _, useVM := runner.arguments.elmoFlags[vmFlag]; useVM {
+		elmo.GlobalSettings().VM = true
+	}
+
+	// included folders are searched before the folders of ELMOPATH
+	//
+	if include, found := runner.arguments.elmoFlags[includeFlag]; found {
+		elmo.GlobalSettings().Path = append(filepath.SplitList(include), elmo.GlobalSettings().Path...)
+	}
+
 	runner.context.RegisterModule(elmo.NewModule("debug", initDebugModule(runner, elmo.GlobalSettings().Debug)))
 
 	if runner.arguments.elmoFile == "" {
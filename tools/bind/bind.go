@@ -0,0 +1,549 @@
+// Package bind generates elmo modules that expose the exported functions of a
+// go package. Generated functions convert their arguments without reflection
+// whenever the parameter types allow it
+//
+package bind
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const elmoPath = "github.com/okke/elmo/core"
+
+// Config denotes what package to bind and how to name the generated code
+//
+type Config struct {
+	// Pattern denotes the go package to bind, as understood by go list
+	Pattern string
+
+	// Dir is the directory the pattern is resolved in, the current directory when empty
+	Dir string
+
+	// Package is the name of the package of the generated source, the module name when empty
+	Package string
+
+	// Module is the name of the generated elmo module, the name of the go package when empty
+	Module string
+}
+
+// Generate creates the source of an elmo module for the package denoted by config
+//
+func Generate(config Config) ([]byte, error) {
+
+	mode := packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedTypesSizes | packages.NeedSyntax | packages.NeedTypesInfo
+	pkgs, err := packages.Load(&packages.Config{Mode: mode, Dir: config.Dir}, config.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected exactly one package for %s, found %d", config.Pattern, len(pkgs))
+	}
+
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		return nil, pkg.Errors[0]
+	}
+
+	if config.Module == "" {
+		config.Module = pkg.Name
+	}
+	if config.Package == "" {
+		config.Package = config.Module
+	}
+
+	generator := &generator{pkg: pkg, config: config, imports: map[string]string{}, names: map[string]string{}, used: map[string]bool{}}
+	return generator.generate()
+}
+
+// Main runs the bind command with given command line arguments and returns its exit code
+//
+func Main(args []string) int {
+
+	flags := flag.NewFlagSet("bind", flag.ContinueOnError)
+	output := flags.String("o", "", "write generated source to file instead of stdout")
+	pkg := flags.String("package", "", "name of the package of the generated source")
+	module := flags.String("module", "", "name of the generated elmo module")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: elmo bind [-o file] [-package name] [-module name] <package>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	pattern := "."
+	switch flags.NArg() {
+	case 0:
+	case 1:
+		pattern = flags.Arg(0)
+	default:
+		flags.Usage()
+		return 2
+	}
+
+	source, err := Generate(Config{Pattern: pattern, Package: *pkg, Module: *module})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if *output == "" {
+		os.Stdout.Write(source)
+		return 0
+	}
+
+	if err := ioutil.WriteFile(*output, source, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+type generator struct {
+	pkg    *packages.Package
+	config Config
+
+	// imports maps package paths to the names they are imported with
+	imports map[string]string
+	names   map[string]string
+	used    map[string]bool
+
+	functions bytes.Buffer
+	bound     []string
+	skipped   []string
+}
+
+// reserved names can not be used to import packages
+// because generated code uses them as variables
+//
+var reserved = map[string]bool{"elmo": true, "context": true, "arguments": true, "argLen": true, "err": true, "resultErr": true, "i": true, "value": true}
+
+// importPackage returns the name a package is imported with
+//
+func (generator *generator) importPackage(path string, name string) string {
+	if imported, found := generator.imports[path]; found {
+		return imported
+	}
+
+	imported := name
+	for i := 2; reserved[imported] || generator.used[imported]; i++ {
+		imported = fmt.Sprintf("%s%d", name, i)
+	}
+
+	generator.imports[path] = imported
+	generator.names[path] = name
+	generator.used[imported] = true
+	return imported
+}
+
+func (generator *generator) qualifier(pkg *types.Package) string {
+	if pkg.Path() == elmoPath {
+		return "elmo"
+	}
+	return generator.importPackage(pkg.Path(), pkg.Name())
+}
+
+func (generator *generator) typeString(t types.Type) string {
+	return types.TypeString(t, generator.qualifier)
+}
+
+func (generator *generator) generate() ([]byte, error) {
+
+	docs := map[string]string{}
+	for _, file := range generator.pkg.Syntax {
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil && funcDecl.Doc != nil {
+				docs[funcDecl.Name.Name] = strings.TrimSpace(funcDecl.Doc.Text())
+			}
+		}
+	}
+
+	scope := generator.pkg.Types.Scope()
+	names := scope.Names()
+	sort.Strings(names)
+
+	for _, name := range names {
+		function, ok := scope.Lookup(name).(*types.Func)
+		if !ok || !function.Exported() {
+			continue
+		}
+		if err := generator.bind(function, docs[name]); err != nil {
+			generator.skipped = append(generator.skipped, fmt.Sprintf("%s (%v)", name, err))
+		}
+	}
+
+	var source bytes.Buffer
+	fmt.Fprintf(&source, "// Code generated by elmo bind from %s. DO NOT EDIT.\n\n", generator.pkg.PkgPath)
+	fmt.Fprintf(&source, "package %s\n\n", generator.config.Package)
+
+	// standard packages are imported before other packages
+	//
+	paths := make([]string, 0, len(generator.imports))
+	for path := range generator.imports {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if standard(paths[i]) != standard(paths[j]) {
+			return standard(paths[i])
+		}
+		return paths[i] < paths[j]
+	})
+
+	source.WriteString("import (\n")
+	for i, path := range paths {
+		if i > 0 && standard(paths[i-1]) && !standard(path) {
+			source.WriteString("\n")
+		}
+		if generator.imports[path] != generator.names[path] {
+			fmt.Fprintf(&source, "\t%s %q\n", generator.imports[path], path)
+		} else {
+			fmt.Fprintf(&source, "\t%q\n", path)
+		}
+	}
+	if len(paths) == 0 || standard(paths[len(paths)-1]) {
+		source.WriteString("\n")
+	}
+	fmt.Fprintf(&source, "\telmo %q\n", elmoPath)
+	source.WriteString(")\n\n")
+
+	fmt.Fprintf(&source, "// Module contains the functions of go package %s\n//\n", generator.pkg.PkgPath)
+	fmt.Fprintf(&source, "var Module = elmo.NewModule(%q, initModule)\n\n", generator.config.Module)
+	source.WriteString("func initModule(context elmo.RunContext) elmo.Value {\n")
+	source.WriteString("\treturn elmo.NewMappingForModule(context, []elmo.NamedValue{\n")
+	for _, name := range generator.bound {
+		fmt.Fprintf(&source, "\t\tbind%s(),\n", name)
+	}
+	source.WriteString("\t})\n}\n")
+
+	source.Write(generator.functions.Bytes())
+
+	if len(generator.skipped) > 0 {
+		source.WriteString("\n// functions that could not be bound:\n//\n")
+		for _, skipped := range generator.skipped {
+			fmt.Fprintf(&source, "//   %s\n", skipped)
+		}
+	}
+
+	return format.Source(source.Bytes())
+}
+
+func standard(path string) bool {
+	return !strings.Contains(strings.Split(path, "/")[0], ".")
+}
+
+// elmoName converts the name of a go function into the name of an elmo function
+// by lowering its leading capitals, like Repeat to repeat and HTTPGet to httpGet
+//
+func elmoName(name string) string {
+	runes := []rune(name)
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+func isNamed(t types.Type, path string, name string) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == path && named.Obj().Name() == name
+}
+
+// checkType verifies that arguments can be converted into given type
+//
+func checkType(t types.Type, visited map[types.Type]bool) error {
+
+	if visited[t] {
+		return nil
+	}
+	visited[t] = true
+
+	if named, ok := t.(*types.Named); ok {
+		if named.Obj().Pkg() != nil && !named.Obj().Exported() {
+			return fmt.Errorf("unexported type %s", named.Obj().Name())
+		}
+		if named.TypeArgs().Len() > 0 {
+			return fmt.Errorf("generic type %s", named.Obj().Name())
+		}
+	}
+
+	switch underlying := t.Underlying().(type) {
+	case *types.Basic:
+		if underlying.Info()&(types.IsBoolean|types.IsString|types.IsInteger|types.IsFloat) == 0 || underlying.Kind() == types.UnsafePointer {
+			return fmt.Errorf("unsupported type %s", t)
+		}
+	case *types.Slice:
+		return checkType(underlying.Elem(), visited)
+	case *types.Array:
+		return checkType(underlying.Elem(), visited)
+	case *types.Pointer:
+		return checkType(underlying.Elem(), visited)
+	case *types.Map:
+		if basic, ok := underlying.Key().Underlying().(*types.Basic); !ok || basic.Info()&types.IsString == 0 {
+			return fmt.Errorf("map with non string keys %s", t)
+		}
+		return checkType(underlying.Elem(), visited)
+	case *types.Struct:
+		for i := 0; i < underlying.NumFields(); i++ {
+			if field := underlying.Field(i); field.Exported() {
+				if err := checkType(field.Type(), visited); err != nil {
+					return err
+				}
+			}
+		}
+	case *types.Interface:
+		if !underlying.Empty() && !isNamed(t, elmoPath, "Value") {
+			return fmt.Errorf("unsupported interface %s", t)
+		}
+	default:
+		return fmt.Errorf("unsupported type %s", t)
+	}
+	return nil
+}
+
+// parameter describes how to convert an argument into a go parameter
+//
+type parameter struct {
+	name    string
+	goType  types.Type
+	convert string
+	bits    int64
+}
+
+func (generator *generator) newParameter(variable *types.Var, variadic bool) (*parameter, error) {
+
+	t := variable.Type()
+	if variadic {
+		t = t.(*types.Slice).Elem()
+	}
+
+	if err := checkType(t, map[types.Type]bool{}); err != nil {
+		return nil, err
+	}
+
+	name := variable.Name()
+	if name == "" || name == "_" {
+		name = "value"
+	}
+
+	p := &parameter{name: name, goType: t}
+
+	if isNamed(t, elmoPath, "Value") {
+		p.convert = "Value"
+		return p, nil
+	}
+
+	if slice, ok := t.Underlying().(*types.Slice); ok {
+		if basic, ok := slice.Elem().(*types.Basic); ok && basic.Kind() == types.Byte {
+			p.convert = "ToBytes"
+		}
+		return p, nil
+	}
+
+	if basic, ok := t.Underlying().(*types.Basic); ok {
+		p.bits = generator.pkg.TypesSizes.Sizeof(basic) * 8
+		switch {
+		case basic.Info()&types.IsBoolean != 0:
+			p.convert = "ToBool"
+		case basic.Info()&types.IsString != 0:
+			p.convert = "ToString"
+		case basic.Info()&types.IsUnsigned != 0:
+			p.convert = "ToUint"
+		case basic.Info()&types.IsInteger != 0:
+			p.convert = "ToInt"
+		case basic.Info()&types.IsFloat != 0:
+			p.convert = "ToFloat"
+		}
+	}
+
+	return p, nil
+}
+
+// writeConversion writes code that converts an argument into a variable
+//
+func (generator *generator) writeConversion(out *bytes.Buffer, p *parameter, variable string, argument string, position string, fname string) {
+
+	fail := fmt.Sprintf("\t\treturn elmo.NewInvalidArgumentErrorValue(%q, %s, err)\n\t}\n", fname, position)
+	goType := generator.typeString(p.goType)
+
+	switch p.convert {
+	case "Value":
+		fmt.Fprintf(out, "\t%s := elmo.EvalArgument(context, %s)\n", variable, argument)
+	case "":
+		fmt.Fprintf(out, "\tvar %s %s\n", variable, goType)
+		fmt.Fprintf(out, "\tif err := elmo.DecodeArgument(context, %s, &%s); err != nil {\n%s", argument, variable, fail)
+	default:
+		bits := ""
+		if p.convert == "ToInt" || p.convert == "ToUint" {
+			bits = fmt.Sprintf(", %d", p.bits)
+		}
+		converted := map[string]string{"ToBool": "bool", "ToString": "string", "ToInt": "int64", "ToUint": "uint64", "ToFloat": "float64", "ToBytes": "[]byte"}[p.convert]
+		if goType == converted {
+			fmt.Fprintf(out, "\t%s, err := elmo.%s(elmo.EvalArgument(context, %s)%s)\n", variable, p.convert, argument, bits)
+			fmt.Fprintf(out, "\tif err != nil {\n%s", fail)
+		} else {
+			fmt.Fprintf(out, "\t%sValue, err := elmo.%s(elmo.EvalArgument(context, %s)%s)\n", variable, p.convert, argument, bits)
+			fmt.Fprintf(out, "\tif err != nil {\n%s", fail)
+			fmt.Fprintf(out, "\t%s := %s(%sValue)\n", variable, goType, variable)
+		}
+	}
+}
+
+// resultValue returns the code that converts a result into an elmo value
+//
+func resultValue(t types.Type, variable string) string {
+	if basic, ok := t.Underlying().(*types.Basic); ok {
+		switch {
+		case basic.Info()&types.IsBoolean != 0:
+			return fmt.Sprintf("elmo.TrueOrFalse(bool(%s))", variable)
+		case basic.Info()&types.IsString != 0:
+			return fmt.Sprintf("elmo.NewStringLiteral(string(%s))", variable)
+		case basic.Info()&types.IsInteger != 0:
+			return fmt.Sprintf("elmo.NewIntegerLiteral(int64(%s))", variable)
+		case basic.Info()&types.IsFloat != 0:
+			return fmt.Sprintf("elmo.NewFloatLiteral(float64(%s))", variable)
+		}
+	}
+	return fmt.Sprintf("elmo.EncodeValue(%s)", variable)
+}
+
+func (generator *generator) bind(function *types.Func, doc string) error {
+
+	signature := function.Type().(*types.Signature)
+	if signature.TypeParams().Len() > 0 {
+		return errors.New("generic function")
+	}
+
+	goName := function.Name()
+	name := elmoName(goName)
+
+	params := signature.Params()
+
+	// first parameter can be used to pass the context of the call
+	//
+	first := 0
+	callArguments := []string{}
+	if params.Len() > 0 {
+		if t := params.At(0).Type(); isNamed(t, "context", "Context") {
+			first = 1
+			callArguments = append(callArguments, "context.Context()")
+		} else if isNamed(t, elmoPath, "RunContext") {
+			first = 1
+			callArguments = append(callArguments, "context")
+		}
+	}
+
+	parameters := make([]*parameter, 0, params.Len())
+	usage := make([]string, 0, params.Len())
+	for i := first; i < params.Len(); i++ {
+		variadic := signature.Variadic() && i == params.Len()-1
+		p, err := generator.newParameter(params.At(i), variadic)
+		if err != nil {
+			return err
+		}
+		parameters = append(parameters, p)
+		if variadic {
+			usage = append(usage, fmt.Sprintf("<%s>*", p.name))
+		} else {
+			usage = append(usage, fmt.Sprintf("<%s>", p.name))
+		}
+	}
+
+	min := len(parameters)
+	max := strconv.Itoa(min)
+	if signature.Variadic() {
+		min--
+		max = generator.importPackage("math", "math") + ".MaxInt16"
+	}
+
+	if doc == "" {
+		doc = fmt.Sprintf("usage: %s %s", name, strings.Join(usage, " "))
+	}
+
+	var body bytes.Buffer
+
+	argLen := "_"
+	if signature.Variadic() {
+		argLen = "argLen"
+	}
+	fmt.Fprintf(&body, "\t%s, err := elmo.CheckArguments(arguments, %d, %s, %q, %q)\n", argLen, min, max, name, strings.Join(usage, " "))
+	body.WriteString("\tif err != nil {\n\t\treturn err\n\t}\n\n")
+
+	for i, p := range parameters {
+		variable := fmt.Sprintf("a%d", i)
+		if signature.Variadic() && i == len(parameters)-1 {
+			fmt.Fprintf(&body, "\t%s := make([]%s, 0, argLen-%d)\n", variable, generator.typeString(p.goType), i)
+			fmt.Fprintf(&body, "\tfor i := %d; i < argLen; i++ {\n", i)
+			generator.writeConversion(&body, p, "value", "arguments[i]", "i+1", name)
+			fmt.Fprintf(&body, "\t%s = append(%s, value)\n", variable, variable)
+			body.WriteString("\t}\n")
+			callArguments = append(callArguments, variable+"...")
+		} else {
+			generator.writeConversion(&body, p, variable, fmt.Sprintf("arguments[%d]", i), strconv.Itoa(i+1), name)
+			callArguments = append(callArguments, variable)
+		}
+		body.WriteString("\n")
+	}
+
+	// collect results, a trailing error becomes an error value
+	//
+	results := signature.Results()
+	variables := make([]string, results.Len())
+	withError := results.Len() > 0 && types.Identical(results.At(results.Len()-1).Type(), types.Universe.Lookup("error").Type())
+	for i := range variables {
+		variables[i] = fmt.Sprintf("r%d", i)
+	}
+	if withError {
+		variables[len(variables)-1] = "resultErr"
+	}
+
+	call := fmt.Sprintf("%s.%s(%s)", generator.qualifier(function.Pkg()), goName, strings.Join(callArguments, ", "))
+	if len(variables) == 0 {
+		fmt.Fprintf(&body, "\t%s\n", call)
+	} else {
+		fmt.Fprintf(&body, "\t%s := %s\n", strings.Join(variables, ", "), call)
+	}
+
+	if withError {
+		body.WriteString("\tif resultErr != nil {\n\t\treturn elmo.NewErrorValue(resultErr.Error())\n\t}\n")
+		variables = variables[:len(variables)-1]
+	}
+
+	values := make([]string, len(variables))
+	for i, variable := range variables {
+		values[i] = resultValue(results.At(i).Type(), variable)
+	}
+
+	switch len(values) {
+	case 0:
+		body.WriteString("\treturn elmo.Nothing\n")
+	case 1:
+		fmt.Fprintf(&body, "\treturn %s\n", values[0])
+	default:
+		fmt.Fprintf(&body, "\treturn elmo.NewReturnValue([]elmo.Value{%s})\n", strings.Join(values, ", "))
+	}
+
+	fmt.Fprintf(&generator.functions, "\nfunc bind%s() elmo.NamedValue {\n", goName)
+	fmt.Fprintf(&generator.functions, "\treturn elmo.NewGoFunctionWithHelp(%q, %q, func(context elmo.RunContext, arguments []elmo.Argument) elmo.Value {\n\n", name, doc)
+	generator.functions.Write(body.Bytes())
+	generator.functions.WriteString("\t})\n}\n")
+
+	generator.bound = append(generator.bound, goName)
+	return nil
+}
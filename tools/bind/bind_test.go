@@ -0,0 +1,97 @@
+package bind
+
+import (
+	"io/ioutil"
+	"testing"
+
+	elmo "github.com/okke/elmo/core"
+	"github.com/okke/elmo/examples/bind/peppersmodule"
+)
+
+func TestElmoName(t *testing.T) {
+
+	for goName, name := range map[string]string{
+		"Repeat":  "repeat",
+		"HTTPGet": "httpGet",
+		"ID":      "id",
+		"X":       "x",
+		"NewURL":  "newURL",
+	} {
+		if elmoName(goName) != name {
+			t.Errorf("expected %s to become %s, found %s", goName, name, elmoName(goName))
+		}
+	}
+}
+
+func TestGenerateIsUpToDate(t *testing.T) {
+
+	source, err := Generate(Config{Pattern: "./peppers", Dir: "../../examples/bind", Package: "peppersmodule", Module: "peppers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generated, err := ioutil.ReadFile("../../examples/bind/peppersmodule/module.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(source) != string(generated) {
+		t.Errorf("generated module is out of date, run go generate in examples/bind:\n%s", source)
+	}
+}
+
+func peppersContext() elmo.RunContext {
+	context := elmo.NewGlobalContext()
+	context.RegisterModule(peppersmodule.Module)
+	return context
+}
+
+func TestGeneratedModule(t *testing.T) {
+
+	elmo.ParseTestAndRunBlockWithinContext(t, peppersContext(),
+		`peppers: (load "peppers")
+		 peppers.hotness "chipotle"`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(5000)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, peppersContext(),
+		`peppers: (load "peppers")
+		 peppers.hotness "bell"`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, peppersContext(),
+		`peppers: (load "peppers")
+		 peppers.blend "salsa" "chipotle" "jalapeno"`, elmo.ExpectValue(t, elmo.NewStringLiteral("salsa with chipotle and jalapeno")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, peppersContext(),
+		`peppers: (load "peppers")
+		 peppers.dilute 10 4`, elmo.ExpectValue(t, elmo.NewFloatLiteral(2.5)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, peppersContext(),
+		`peppers: (load "peppers")
+		 peppers.dilute 10 400`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, peppersContext(),
+		`peppers: (load "peppers")
+		 p: (peppers.new "chipotle" 5000)
+		 p.Hotness`, elmo.ExpectValue(t, elmo.NewIntegerLiteral(5000)))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, peppersContext(),
+		`peppers: (load "peppers")
+		 peppers.describe {name: "chipotle"; hotness: 5000; dishes: ["salsa" "mole"]}`,
+		elmo.ExpectValue(t, elmo.NewStringLiteral("chipotle (5000) goes well with salsa, mole")))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, peppersContext(),
+		`peppers: (load "peppers")
+		 set a b (peppers.split 5)
+		 [$a $b]`, elmo.ExpectValue(t, elmo.NewListValue([]elmo.Value{elmo.NewIntegerLiteral(2), elmo.NewIntegerLiteral(3)})))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, peppersContext(),
+		`peppers: (load "peppers")
+		 peppers.known "jalapeno"`, elmo.ExpectValue(t, elmo.True))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, peppersContext(),
+		`peppers: (load "peppers")
+		 peppers.known 42`, elmo.ExpectErrorValueAt(t, 2))
+
+	elmo.ParseTestAndRunBlockWithinContext(t, peppersContext(),
+		`peppers: (load "peppers")
+		 peppers.grind "chipotle"`, elmo.ExpectErrorValueAt(t, 2))
+}
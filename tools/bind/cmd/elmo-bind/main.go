@@ -0,0 +1,14 @@
+// Command elmo-bind generates an elmo module out of a go package. It's also
+// available as elmo bind
+//
+package main
+
+import (
+	"os"
+
+	"github.com/okke/elmo/tools/bind"
+)
+
+func main() {
+	os.Exit(bind.Main(os.Args[1:]))
+}
@@ -0,0 +1,19 @@
+module github.com/okke/elmo/tools/bind
+
+go 1.22.0
+
+require (
+	github.com/okke/elmo v0.0.0-00010101000000-000000000000
+	golang.org/x/tools v0.26.0
+)
+
+require (
+	github.com/fsnotify/fsnotify v1.4.9 // indirect
+	github.com/google/uuid v1.1.1 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	golang.org/x/mod v0.21.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+	golang.org/x/sys v0.26.0 // indirect
+)
+
+replace github.com/okke/elmo => ../..
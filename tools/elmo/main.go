@@ -1,8 +1,57 @@
 package main
 
-import "github.com/okke/elmo/runner"
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/okke/elmo/runner"
+)
+
+// bindCommand is the executable that implements elmo bind. It lives in
+// its own module (tools/bind) so elmo itself does not depend on go/packages
+//
+const bindCommand = "elmo-bind"
+
+// bind runs elmo-bind with given arguments and returns its exit code
+//
+func bind(args []string) int {
+	path, err := exec.LookPath(bindCommand)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "elmo bind needs %s, install it by running go install in tools/bind/cmd/%s\n", bindCommand, bindCommand)
+		return 1
+	}
+
+	cmd := exec.Command(path, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		if exitErr, isExitErr := err.(*exec.ExitError); isExitErr {
+			return exitErr.ExitCode()
+		}
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// isBindCommand checks if elmo is asked to run elmo bind. A script that
+// is named bind is still run as a script
+//
+func isBindCommand(args []string) bool {
+	if len(args) < 2 || args[1] != "bind" {
+		return false
+	}
+	_, err := os.Stat(args[1])
+	return os.IsNotExist(err)
+}
 
 func main() {
+	// elmo bind generates modules from go packages
+	//
+	if isBindCommand(os.Args) {
+		os.Exit(bind(os.Args[2:]))
+	}
+
 	context := runner.NewMainContext()
 	runner := runner.NewRunner(context)
 	runner.Main()